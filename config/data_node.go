@@ -11,10 +11,14 @@ type DataNodeconfig struct {
 	NameNodeIP                   string //IP of the current name node
 	InternalRequestsPort         string //The internal requests ports
 	NameNodeInternalRequestsPort string //The internal requests port of the name node
+	NameNodeAddresses            string //Comma separated internal addresses of all the name nodes, failed over in order
 	Port                         string //Port to listen to requests
 	GPU                          string //Indicates if the datanode has a GPU
+	Zone                         string //Zone (failure domain) the data node is deployed in
+	Rack                         string //Rack the data node is mounted on, within its zone
+	DiskClass                    string //Class of the disk holding the files (hdd, ssd)
 	NetworkProtocol              string //Network protocol used by the data node
-	NameNodeReplicationURL       string //URL to request datanodes for replication
+	NameNodeReplicationURL       string //Comma separated URLs to request datanodes for replication, tried in order
 	StorageDBName                string //Storage database name
 	InternalReqTimeout           int    //Timeout for internal requests
 	MaxRequestSize               int64  //Maximum acceptable size of body size
@@ -35,6 +39,14 @@ type DataNodeconfig struct {
 	ThumbnailFolderName          string //Name of folder that contains thumbnail files
 	MaximumConcurrentJobs        int    //Maximum number of running concurrent jobs
 	JobTimeout                   int    //Maximum time for a job untill timeout, in seconds
+	UploadTTL                    int    //Time after which an incomplete upload is considered abandoned, in seconds
+	JanitorInterval              int    //Frequency of collecting abandoned uploads and orphaned files, in seconds
+	ReplicationMode              string //Replication mode, sync streams chunks to the replica before acknowledging them, async ships them in background
+	ReplicationWorkerInterval    int    //Frequency of shipping the chunks not yet replicated, in seconds
+	ScrubInterval                int    //Frequency of scrubbing a batch of completed files, in seconds
+	ScrubBatchSize               int    //Number of files scrubbed per round, the least recently scrubbed first
+	HeartbeatInterval            int    //Frequency of sending heartbeats to the name node, in seconds
+	BlockReportInterval          int    //Frequency of sending block reports of the files on disk to the name node, in seconds
 }
 
 // dataNodeConfigOnce Used to garauntee thread safety for singleton instances
@@ -53,8 +65,12 @@ func (manager *ConfigurationManager) DataNodeConfig() *DataNodeconfig {
 			NameNodeIP:                   envString("NAME_NODE_IP", "127.0.0.1"),
 			InternalRequestsPort:         envString("INTERNAL_REQ_PORT", "6000"),
 			NameNodeInternalRequestsPort: envString("NAME_NODE_INTERNAL_REQ_PORT", "7000"),
+			NameNodeAddresses:            envString("NAME_NODE_ADDRESSES", ""),
 			Port:                         envString("PORT", "8080"),
 			GPU:                          envString("GPU_STATUS", "false"),
+			Zone:                         envString("ZONE", ""),
+			Rack:                         envString("RACK", ""),
+			DiskClass:                    envString("DISK_CLASS", "hdd"),
 			NameNodeReplicationURL:       envString("NAME_NODE_REPLICATION_URL", "http://localhost:8080/replication"),
 			NetworkProtocol:              envString("NET_PROTOCOL", "tcp"),
 			StorageDBName:                envString("STO_DB_NAME", "videra_storage"),
@@ -77,6 +93,14 @@ func (manager *ConfigurationManager) DataNodeConfig() *DataNodeconfig {
 			ThumbnailFolderName:          envString("THUMBNAIL_FOLDER_NAME", "thumbnail"),
 			MaximumConcurrentJobs:        int(envInt("MAXIMUM_CONCURRENT_JOBS", "1")),
 			JobTimeout:                   int(envInt("JOB_TIMEOUT", "7200")),
+			UploadTTL:                    int(envInt("UPLOAD_TTL", "86400")),
+			JanitorInterval:              int(envInt("JANITOR_INTERVAL", "3600")),
+			ReplicationMode:              envString("REPLICATION_MODE", "sync"),
+			ReplicationWorkerInterval:    int(envInt("REPLICATION_WORKER_INTERVAL", "5")),
+			ScrubInterval:                int(envInt("SCRUB_INTERVAL", "600")),
+			ScrubBatchSize:               int(envInt("SCRUB_BATCH_SIZE", "20")),
+			HeartbeatInterval:            int(envInt("HEARTBEAT_INTERVAL", "5")),
+			BlockReportInterval:          int(envInt("BLOCK_REPORT_INTERVAL", "600")),
 		}
 
 		dataNodeConfigInstance = &dataNodeConfig
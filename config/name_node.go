@@ -7,6 +7,7 @@ import (
 // NameNodeconfig Houses the configurations of the name node
 type NameNodeconfig struct {
 	IP                       string //Name node IP
+	ID                       string //Unique ID of the name node instance, its internal address if empty
 	InternalRequestsPort     string //The internal requests ports
 	Port                     string //The external requests ports
 	NetworkProtocol          string //Name network protcol
@@ -15,6 +16,30 @@ type NameNodeconfig struct {
 	InternalReqTimeout       int    //Timeout for internal requests
 	HealthCheckInterval      int    //The frequency of the health check request to data nodes
 	DataNodeOfflineThreshold int    //Threshold of missed pings at which a data node is considered offline
+	ReplicationFactor        int    //Number of copies kept for each file, unless overridden per upload
+	ModelReplicaNodes        string //Comma separated IDs of the data nodes models are replicated to, all GPU nodes if empty
+	PlacementPolicy          string //Policy choosing the data nodes holding replicas (spread, ring)
+	RoutingPolicy            string //Policy choosing the data node uploads are routed to (least-queued, least-recent, most-free-disk, weighted-round-robin, model-affinity)
+	RoutingWeights           string //Comma separated id:weight pairs of the data nodes, used by weighted round robin routing
+	SearchResponseFormat     string //Default format of the search response (envelope, array), array is the format of the original frontend
+	ReplicationJobsKey       string //Redis key where re-replication jobs are stored
+	ReReplicationInterval    int    //The frequency of running pending re-replication jobs, in seconds
+	ReReplicationRetries     int    //Number of attempts of a re-replication job before it's considered failed
+	ReReplicationTimeout     int    //Timeout for copying a file to a new data node, in seconds
+	ReplicationJobsRetention int    //Time for which done and failed re-replication jobs are kept, in seconds
+	ScrubChecksumsKey        string //Redis key where the checksums reported by the scrubbers are stored
+	ScrubResultsKey          string //Redis key where the diverged copies found by the scrubbers are stored
+	ScrubMetricsKey          string //Redis key where the scrubbing counters are stored
+	FileExpectationsKey      string //Redis key where the checksum and intended number of copies of each file are stored
+	NodeStatesKey            string //Redis key where the draining and decommissioned data nodes are stored
+	DecommissionInterval     int    //The frequency of migrating the files off the draining data nodes, in seconds
+	LeaderLeaseKey           string //Redis key of the lease held by the leader name node
+	InventoryKeyPrefix       string //Prefix of the redis keys where the file copies held by each data node are stored
+	LocationsKeyPrefix       string //Prefix of the redis keys where the data nodes holding each file are stored
+	DiscrepanciesKey         string //Redis key where the discrepancies found in the block reports are stored
+	PendingDeletionsKey      string //Redis key where the deletions waiting for offline data nodes are stored
+	LeaderLeaseTTL           int    //Time after which the lease of a leader that stopped renewing it expires, in seconds
+	LeaderRenewInterval      int    //The frequency of renewing or campaigning for the leader lease, in seconds
 }
 
 // nameNodeConfigOnce Used to garauntee thread safety for singleton instances
@@ -28,6 +53,7 @@ func (manager *ConfigurationManager) NameNodeConfig() *NameNodeconfig {
 	nameNodeConfigOnce.Do(func() {
 		nameNodeConfig := NameNodeconfig{
 			IP:                       envString("IP", "127.0.0.1"),
+			ID:                       envString("NAME_NODE_ID", ""),
 			InternalRequestsPort:     envString("INTERNAL_REQ_PORT", "7000"),
 			Port:                     envString("PORT", "8080"),
 			NetworkProtocol:          envString("NET_PROTOCOL", "tcp"),
@@ -36,6 +62,30 @@ func (manager *ConfigurationManager) NameNodeConfig() *NameNodeconfig {
 			InternalReqTimeout:       int(envInt("INTERNAL_REQ_TIMEOUT", "5")),
 			HealthCheckInterval:      int(envInt("HEALTH_CHECK_INTERVAL", "2")),
 			DataNodeOfflineThreshold: int(envInt("DN_OFFLINE_THRESHOLD", "3")),
+			ReplicationFactor:        int(envInt("REPLICATION_FACTOR", "2")),
+			ModelReplicaNodes:        envString("MODEL_REPLICA_NODES", ""),
+			PlacementPolicy:          envString("PLACEMENT_POLICY", "spread"),
+			RoutingPolicy:            envString("ROUTING_POLICY", "least-queued"),
+			RoutingWeights:           envString("ROUTING_WEIGHTS", ""),
+			SearchResponseFormat:     envString("SEARCH_RESPONSE_FORMAT", "envelope"),
+			ReplicationJobsKey:       envString("REPLICATION_JOBS_REDIS_KEY", "storage:replication-jobs"),
+			ReReplicationInterval:    int(envInt("RE_REPLICATION_INTERVAL", "10")),
+			ReReplicationRetries:     int(envInt("RE_REPLICATION_RETRIES", "3")),
+			ReReplicationTimeout:     int(envInt("RE_REPLICATION_TIMEOUT", "600")),
+			ReplicationJobsRetention: int(envInt("REPLICATION_JOBS_RETENTION", "86400")),
+			ScrubChecksumsKey:        envString("SCRUB_CHECKSUMS_REDIS_KEY", "storage:scrub-checksums"),
+			ScrubResultsKey:          envString("SCRUB_RESULTS_REDIS_KEY", "storage:scrub-results"),
+			ScrubMetricsKey:          envString("SCRUB_METRICS_REDIS_KEY", "storage:scrub-metrics"),
+			FileExpectationsKey:      envString("FILE_EXPECTATIONS_REDIS_KEY", "storage:file-expectations"),
+			NodeStatesKey:            envString("NODE_STATES_REDIS_KEY", "storage:data-node-states"),
+			DecommissionInterval:     int(envInt("DECOMMISSION_INTERVAL", "30")),
+			LeaderLeaseKey:           envString("LEADER_LEASE_REDIS_KEY", "storage:name-node-leader"),
+			InventoryKeyPrefix:       envString("INVENTORY_REDIS_KEY_PREFIX", "storage:inventory"),
+			LocationsKeyPrefix:       envString("LOCATIONS_REDIS_KEY_PREFIX", "storage:locations"),
+			DiscrepanciesKey:         envString("DISCREPANCIES_REDIS_KEY", "storage:block-discrepancies"),
+			PendingDeletionsKey:      envString("PENDING_DELETIONS_REDIS_KEY", "storage:pending-deletions"),
+			LeaderLeaseTTL:           int(envInt("LEADER_LEASE_TTL", "10")),
+			LeaderRenewInterval:      int(envInt("LEADER_RENEW_INTERVAL", "3")),
 		}
 
 		nameNodeConfigInstance = &nameNodeConfig
@@ -0,0 +1,134 @@
+package datanode
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/SayedAlesawy/Videra-Storage/data_node/storage"
+	"github.com/SayedAlesawy/Videra-Storage/name_node/nnpb"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+	"google.golang.org/grpc"
+)
+
+// ReportBlocks A function to send a block report of the file copies on disk to the name node, which reconciles it
+// with the files recorded for the data node, an unreachable name node or a follower is failed over
+// to the leader or the next known name node
+func (dataNode *DataNode) ReportBlocks() (*nnpb.BlockReportResponse, error) {
+	inventory, err := dataNode.getBlockReport()
+	if errors.IsError(err) {
+		return nil, err
+	}
+
+	res, err := dataNode.sendBlockReport(inventory)
+	if errors.IsError(err) {
+		dataNode.failOverNameNode("")
+		return nil, err
+	}
+
+	if res.Status == nnpb.BlockReportResponse_NOT_LEADER {
+		dataNode.failOverNameNode(res.LeaderAddress)
+	}
+
+	return res, nil
+}
+
+// sendBlockReport A function to send the block report to the current name node
+func (dataNode *DataNode) sendBlockReport(inventory []*nnpb.InventoryEntry) (*nnpb.BlockReportResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dataNode.InternalReqTimeout)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, dataNode.getNameNodeAddress(), grpc.WithBlock(), grpc.WithInsecure())
+	if errors.IsError(err) {
+		return nil, err
+	}
+	defer conn.Close()
+
+	client := nnpb.NewNameNodeInternalRoutesClient(conn)
+	req := nnpb.BlockReportRequest{
+		DataNodeID: dataNode.ID,
+		Inventory:  inventory,
+	}
+
+	return client.ReportBlocks(ctx, &req)
+}
+
+// getBlockReport A function to list the file copies found on disk, the size of a copy is the size of its folder,
+// a copy is completed once all of its chunks were received, as chunks arrive out of order the size of its sparse
+// blobs doesn't tell, its checksum is the one computed from disk by the last scrub, copies with no record are
+// reported as is
+func (dataNode *DataNode) getBlockReport() ([]*nnpb.InventoryEntry, error) {
+	var files []File
+
+	err := dataNode.DB.Connection.Select("token, parent, scrubbed_checksum, completed_at").
+		Where("data_node_id = ?", dataNode.ID).Find(&files).Error
+	if errors.IsError(err) {
+		return nil, err
+	}
+
+	records := make(map[string]File)
+	for _, file := range files {
+		records[file.Token] = file
+	}
+
+	folders, err := ioutil.ReadDir(storage.FilesFolder())
+	if errors.IsError(err) && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	inventory := make([]*nnpb.InventoryEntry, 0, len(folders))
+	for _, folder := range folders {
+		if !folder.IsDir() {
+			continue
+		}
+
+		size, err := folderSize(storage.FileFolder(folder.Name()))
+		if errors.IsError(err) {
+			return nil, err
+		}
+
+		entry := nnpb.InventoryEntry{
+			Token: folder.Name(),
+			Size:  size,
+		}
+
+		if record, found := records[folder.Name()]; found {
+			entry.Parent = record.Parent
+			entry.Checksum = record.ScrubbedChecksum
+			entry.Completed = record.CompletedAt != nil
+			entry.Stream = fileExists(storage.StreamPlaylistPath(record.Parent))
+			entry.Thumbnail = fileExists(storage.ThumbnailPath(record.Parent))
+		}
+
+		inventory = append(inventory, &entry)
+	}
+
+	return inventory, nil
+}
+
+// folderSize A function to get the total size in bytes of the files in a folder
+func folderSize(folder string) (int64, error) {
+	var size int64
+
+	err := filepath.Walk(folder, func(_ string, info os.FileInfo, err error) error {
+		if errors.IsError(err) {
+			return err
+		}
+
+		if info.Mode().IsRegular() {
+			size += info.Size()
+		}
+
+		return nil
+	})
+
+	return size, err
+}
+
+// fileExists A function to check if a file exists on disk
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+
+	return !errors.IsError(err)
+}
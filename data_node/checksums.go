@@ -0,0 +1,52 @@
+package datanode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/SayedAlesawy/Videra-Storage/name_node/nnpb"
+	"google.golang.org/grpc"
+)
+
+// ReportChecksums A function to send the checksums computed by the scrubber to the name node,
+// it returns the number of copies the name node found to diverge from their originals,
+// an unreachable name node or a follower is failed over to the leader or the next known name node
+func (dataNode *DataNode) ReportChecksums(checksums []*nnpb.FileChecksum) (int, error) {
+	res, err := dataNode.sendChecksums(checksums)
+	if err != nil {
+		dataNode.failOverNameNode("")
+		return 0, err
+	}
+
+	if res.Status == nnpb.ReportChecksumsResponse_NOT_LEADER {
+		dataNode.failOverNameNode(res.LeaderAddress)
+		return 0, errors.New("Checksums reached a follower name node")
+	}
+
+	if res.Status != nnpb.ReportChecksumsResponse_SUCCESS {
+		return 0, errors.New(fmt.Sprintf("Name node refused the checksums of data node %s", dataNode.ID))
+	}
+
+	return int(res.MismatchCount), nil
+}
+
+// sendChecksums A function to send the checksums computed by the scrubber to the current name node
+func (dataNode *DataNode) sendChecksums(checksums []*nnpb.FileChecksum) (*nnpb.ReportChecksumsResponse, error) {
+	conn, err := grpc.Dial(dataNode.getNameNodeAddress(), grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	client := nnpb.NewNameNodeInternalRoutesClient(conn)
+	req := nnpb.ReportChecksumsRequest{
+		DataNodeID: dataNode.ID,
+		Checksums:  checksums,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dataNode.InternalReqTimeout)
+	defer cancel()
+
+	return client.ReportChecksums(ctx, &req)
+}
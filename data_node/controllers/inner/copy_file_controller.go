@@ -0,0 +1,41 @@
+package inner
+
+import (
+	context "context"
+	"fmt"
+	"log"
+
+	datanode "github.com/SayedAlesawy/Videra-Storage/data_node"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/dnpb"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/replication"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+// CopyFile Handles the copy file request, streaming the completed copy of the file held by the data node
+// to the target data node, used by the name node to restore the replicas lost with offline nodes
+func (server *Server) CopyFile(ctx context.Context, req *dnpb.CopyFileRequest) (*dnpb.CopyFileResponse, error) {
+	log.Println(logPrefix, fmt.Sprintf("Received copy request for file: %s to %s", req.Token, req.TargetAddress))
+
+	dataNode := datanode.NodeInstance()
+
+	var fileInfo datanode.File
+	notFound := dataNode.DB.Connection.Where("parent = ? AND data_node_id = ? AND completed_at IS NOT NULL",
+		req.Token, dataNode.ID).First(&fileInfo).RecordNotFound()
+	if notFound {
+		log.Println(logPrefix, fmt.Sprintf("No completed copy of file %s is found", req.Token))
+
+		return &dnpb.CopyFileResponse{Status: dnpb.CopyFileResponse_FAILURE}, nil
+	}
+
+	token, err := replication.CopyFile(fileInfo, req.TargetAddress)
+	if errors.IsError(err) {
+		log.Println(logPrefix, fmt.Sprintf("Unable to copy file %s to %s", req.Token, req.TargetAddress), err)
+
+		return &dnpb.CopyFileResponse{Status: dnpb.CopyFileResponse_FAILURE}, nil
+	}
+
+	return &dnpb.CopyFileResponse{
+		Status:   dnpb.CopyFileResponse_SUCCESS,
+		NewToken: token,
+	}, nil
+}
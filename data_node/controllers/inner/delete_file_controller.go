@@ -0,0 +1,41 @@
+package inner
+
+import (
+	context "context"
+	"fmt"
+	"log"
+
+	datanode "github.com/SayedAlesawy/Videra-Storage/data_node"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/dnpb"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/janitor"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+// DeleteFile Handles the delete file request, removing every copy of the file held by the data node
+func (server *Server) DeleteFile(ctx context.Context, req *dnpb.DeleteFileRequest) (*dnpb.DeleteFileResponse, error) {
+	log.Println(logPrefix, fmt.Sprintf("Received delete request for file: %s", req.Token))
+
+	dataNode := datanode.NodeInstance()
+
+	var files []datanode.File
+	err := dataNode.DB.Connection.Where("parent = ? AND data_node_id = ?", req.Token, dataNode.ID).Find(&files).Error
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to fetch copies of file", req.Token, err)
+
+		return &dnpb.DeleteFileResponse{Status: dnpb.DeleteFileResponse_FAILURE}, nil
+	}
+
+	for _, file := range files {
+		err := janitor.RemoveUpload(file)
+		if errors.IsError(err) {
+			log.Println(logPrefix, fmt.Sprintf("Unable to delete file %s", file.Token), err)
+
+			return &dnpb.DeleteFileResponse{Status: dnpb.DeleteFileResponse_FAILURE}, nil
+		}
+	}
+
+	return &dnpb.DeleteFileResponse{
+		Status:       dnpb.DeleteFileResponse_SUCCESS,
+		DeletedCount: int32(len(files)),
+	}, nil
+}
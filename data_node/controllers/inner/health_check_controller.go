@@ -5,13 +5,31 @@ import (
 	"log"
 
 	"github.com/SayedAlesawy/Videra-Storage/data_node/dnpb"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/membership"
 )
 
-// HealthCheck Handles the join cluster request
+// HealthCheck Handles the health check request, the response reports the capacity and the load of the data node,
+// which is unhealthy if its disk or database can't be reached
 func (server *Server) HealthCheck(ctx context.Context, req *dnpb.HealthCheckRequest) (*dnpb.HealthCheckResponse, error) {
 	log.Println(logPrefix, "Received health check ping from name node")
 
-	return &dnpb.HealthCheckResponse{
-		Status: dnpb.HealthCheckResponse_HEALTHY,
-	}, nil
+	load := membership.CollectLoad()
+
+	res := dnpb.HealthCheckResponse{
+		Status:          dnpb.HealthCheckResponse_HEALTHY,
+		FreeDisk:        load.FreeDisk,
+		UsedDisk:        load.UsedDisk,
+		TotalDisk:       load.TotalDisk,
+		FileCount:       load.FileCount,
+		RunningJobs:     int32(load.RunningJobs),
+		QueuedJobs:      int32(load.QueuedJobs),
+		InFlightUploads: int32(load.InFlightUploads),
+		GPU:             load.GPU,
+		GPUAvailable:    load.GPUAvailable,
+	}
+	if !load.Healthy {
+		res.Status = dnpb.HealthCheckResponse_UNHEALTHY
+	}
+
+	return &res, nil
 }
@@ -0,0 +1,308 @@
+package inner
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	datanode "github.com/SayedAlesawy/Videra-Storage/data_node"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/dnpb"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/replication"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/storage"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/upload"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ReplicateFile Handles the replicate file stream, the first message carries the file metadata,
+// creating the replica if it doesn't exist yet, followed by the chunks written into the replica,
+// the status of the replica is sent back once the stream ends, so a stream with no chunks only queries it
+func (server *Server) ReplicateFile(stream dnpb.DataNodeInternalRoutes_ReplicateFileServer) error {
+	req, err := stream.Recv()
+	if errors.IsError(err) {
+		return err
+	}
+
+	metadata := req.GetMetadata()
+	if metadata == nil {
+		return status.Error(codes.InvalidArgument, "The first message of the stream should carry the file metadata")
+	}
+
+	fileInfo, err := server.getReplica(metadata)
+	if errors.IsError(err) {
+		log.Println(logPrefix, fmt.Sprintf("Unable to get replica of file %s", metadata.Parent), err)
+		return err
+	}
+
+	// in sync mode, the chunks are forwarded to the next replica in the chain before being committed
+	replicate := fileInfo.ReplicaCount > 0 && replication.SyncMode()
+	var downstream *replication.ChunkStream
+	completed := false
+
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if errors.IsError(err) {
+			if downstream != nil {
+				downstream.Abort()
+			}
+			return err
+		}
+
+		chunk := req.GetChunk()
+		if chunk == nil {
+			err = status.Error(codes.InvalidArgument, "Only chunks are allowed after the file metadata")
+		} else {
+			if replicate && downstream == nil {
+				downstream, err = replication.OpenChunkStream(fileInfo.Token)
+			}
+
+			if !errors.IsError(err) {
+				var chunkCompleted bool
+				fileInfo, chunkCompleted, err = server.writeChunk(fileInfo, chunk, downstream)
+				completed = completed || chunkCompleted
+			}
+		}
+
+		if errors.IsError(err) {
+			log.Println(logPrefix, fmt.Sprintf("Unable to replicate chunk of file %s", fileInfo.Token), err)
+			if downstream != nil {
+				downstream.Abort()
+			}
+			return err
+		}
+	}
+
+	if downstream != nil {
+		_, err := downstream.Close()
+		if errors.IsError(err) {
+			log.Println(logPrefix, fmt.Sprintf("Unable to replicate file %s down the chain", fileInfo.Token), err)
+			return status.Error(codes.Unavailable, err.Error())
+		}
+	}
+
+	if completed {
+		log.Println(logPrefix, fmt.Sprintf("Replica %s of file %s was uploaded successfully!", fileInfo.Token, fileInfo.Parent))
+		upload.StartProcessing(fileInfo)
+
+		// a replica down the chain failing verification is verified later by the replication worker
+		if replicate {
+			_, err := replication.VerifyReplica(fileInfo)
+			if errors.IsError(err) {
+				log.Println(logPrefix, err)
+			}
+		}
+	}
+
+	return server.sendReplicaStatus(stream, fileInfo.Token)
+}
+
+// getReplica is a function to find the replica described by the metadata, creating it if it doesn't exist,
+// a new replica starts the replication to the rest of the pipeline it was given
+func (server *Server) getReplica(metadata *dnpb.FileMetadata) (datanode.File, error) {
+	dataNode := datanode.NodeInstance()
+
+	var fileInfo datanode.File
+	if metadata.ReplicaToken != "" {
+		notFound := dataNode.DB.Connection.Where("token = ? AND data_node_id = ?", metadata.ReplicaToken, dataNode.ID).
+			Find(&fileInfo).RecordNotFound()
+		if notFound {
+			return fileInfo, status.Error(codes.NotFound, fmt.Sprintf("Record with token: %s is not found", metadata.ReplicaToken))
+		}
+
+		return fileInfo, nil
+	}
+
+	err := validateFileMetadata(metadata)
+	if errors.IsError(err) {
+		return fileInfo, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// a retried stream resumes the copy created by the previous attempt
+	notFound := dataNode.DB.Connection.Where("parent = ? AND data_node_id = ?", metadata.Parent, dataNode.ID).
+		First(&fileInfo).RecordNotFound()
+	if !notFound {
+		return fileInfo, nil
+	}
+
+	id := datanode.GenerateRandomString(10)
+	switch metadata.Type {
+	case datanode.VideoFileType:
+		fileInfo = upload.NewVideo(id, metadata.Parent, metadata.Name, metadata.Size, metadata.Checksum, metadata.AssociatedModel)
+	case datanode.ModelFileType:
+		fileInfo = upload.NewModel(id, metadata.Parent, metadata.Name, metadata.ModelSize, metadata.ConfigSize, metadata.CodeSize, metadata.Checksum)
+	}
+
+	fileInfo.ReplicaCount, err = replication.StartReplication(fileInfo, metadata.Pipeline)
+	if errors.IsError(err) {
+		return fileInfo, status.Error(codes.Unavailable, err.Error())
+	}
+
+	err = upload.Create(&fileInfo)
+	if errors.IsError(err) {
+		return fileInfo, status.Error(codes.Internal, err.Error())
+	}
+
+	return fileInfo, nil
+}
+
+// validateFileMetadata is a function to validate the metadata of a replica to be created
+func validateFileMetadata(metadata *dnpb.FileMetadata) error {
+	err := storage.ValidateToken(metadata.Parent)
+	if errors.IsError(err) {
+		return err
+	}
+
+	_, err = storage.SanitizeFilename(metadata.Name)
+	if errors.IsError(err) {
+		return err
+	}
+
+	if metadata.Size <= 0 {
+		return errors.New("Invalid filesize")
+	}
+
+	switch metadata.Type {
+	case datanode.VideoFileType:
+		return nil
+	case datanode.ModelFileType:
+		if metadata.ModelSize <= 0 || metadata.ConfigSize <= 0 || metadata.CodeSize <= 0 ||
+			metadata.Size != metadata.ModelSize+metadata.ConfigSize+metadata.CodeSize {
+			return errors.New("Invalid filesize")
+		}
+
+		return nil
+	default:
+		return errors.New(fmt.Sprintf("Unsupported file type %s", metadata.Type))
+	}
+}
+
+// writeChunk is a function to write a chunk into the replica after verifying its digest,
+// the chunk is forwarded down the chain if a downstream stream is given, then committed,
+// chunks already held by the replica are compared against the held bytes, so retried streams can resend them
+func (server *Server) writeChunk(fileInfo datanode.File, chunk *dnpb.FileChunk, downstream *replication.ChunkStream) (datanode.File, bool, error) {
+	chunkSize := int64(len(chunk.Data))
+	if chunkSize == 0 || !upload.ValidOffset(fileInfo, chunk.Offset, chunkSize) {
+		return fileInfo, false, status.Error(codes.OutOfRange, fmt.Sprintf("Invalid file offset %d for file of size %d", chunk.Offset, fileInfo.Size))
+	}
+
+	checksum := sha256.Sum256(chunk.Data)
+	if !bytes.Equal(checksum[:], chunk.Checksum) {
+		return fileInfo, false, status.Error(codes.DataLoss, fmt.Sprintf("Chunk %d-%d digest mismatch", chunk.Offset, chunk.Offset+chunkSize))
+	}
+
+	filePath, writeOffset, err := upload.ChunkPath(fileInfo, chunk.Offset, chunkSize)
+	if errors.IsError(err) {
+		return fileInfo, false, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	byteRange := datanode.ByteRange{Start: chunk.Offset, End: chunk.Offset + chunkSize}
+	receivedRanges, err := upload.Reserve(fileInfo.Token, byteRange)
+	if receivedRanges.Contains(byteRange) {
+		return server.rewriteHeldChunk(fileInfo, chunk, filePath, writeOffset, downstream)
+	}
+	if errors.IsError(err) {
+		return fileInfo, false, status.Error(codes.AlreadyExists, err.Error())
+	}
+	defer upload.Release(fileInfo.Token, byteRange)
+
+	file, err := os.OpenFile(filePath, os.O_WRONLY, 0644)
+	if errors.IsError(err) {
+		return fileInfo, false, status.Error(codes.Internal, err.Error())
+	}
+	defer file.Close()
+
+	_, err = file.WriteAt(chunk.Data, writeOffset)
+	if errors.IsError(err) {
+		return fileInfo, false, status.Error(codes.Internal, err.Error())
+	}
+
+	if downstream != nil {
+		err := downstream.Send(chunk.Offset, chunk.Data)
+		if errors.IsError(err) {
+			return fileInfo, false, status.Error(codes.Unavailable, err.Error())
+		}
+	}
+
+	fileInfo, completed, err := upload.CommitChunk(fileInfo.Token, byteRange)
+	if err == upload.ErrFileChecksumMismatch {
+		return fileInfo, false, status.Error(codes.DataLoss, err.Error())
+	}
+	if errors.IsError(err) {
+		return fileInfo, false, status.Error(codes.Internal, err.Error())
+	}
+
+	return fileInfo, completed, nil
+}
+
+// rewriteHeldChunk is a function to handle a chunk already held by the replica, the held bytes
+// are overwritten if they differ from the chunk, and the digest of a completed replica is recomputed,
+// the chunk is forwarded down the chain either way, as the next replica may not hold it
+func (server *Server) rewriteHeldChunk(fileInfo datanode.File, chunk *dnpb.FileChunk, filePath string, writeOffset int64,
+	downstream *replication.ChunkStream) (datanode.File, bool, error) {
+	file, err := os.OpenFile(filePath, os.O_RDWR, 0644)
+	if errors.IsError(err) {
+		return fileInfo, false, status.Error(codes.Internal, err.Error())
+	}
+	defer file.Close()
+
+	held := make([]byte, len(chunk.Data))
+	_, err = file.ReadAt(held, writeOffset)
+	if errors.IsError(err) {
+		return fileInfo, false, status.Error(codes.Internal, err.Error())
+	}
+
+	if !bytes.Equal(held, chunk.Data) {
+		log.Println(logPrefix, fmt.Sprintf("Held chunk %d-%d of replica %s differs, overwriting it",
+			chunk.Offset, chunk.Offset+int64(len(chunk.Data)), fileInfo.Token))
+
+		_, err = file.WriteAt(chunk.Data, writeOffset)
+		if errors.IsError(err) {
+			return fileInfo, false, status.Error(codes.Internal, err.Error())
+		}
+
+		if upload.IsComplete(fileInfo) {
+			fileInfo, err = upload.RefreshChecksum(fileInfo.Token)
+			if errors.IsError(err) {
+				return fileInfo, false, status.Error(codes.Internal, err.Error())
+			}
+		}
+	}
+
+	if downstream != nil {
+		err := downstream.Send(chunk.Offset, chunk.Data)
+		if errors.IsError(err) {
+			return fileInfo, false, status.Error(codes.Unavailable, err.Error())
+		}
+	}
+
+	return fileInfo, false, nil
+}
+
+// sendReplicaStatus is a function to respond with the upload progress of a replica
+func (server *Server) sendReplicaStatus(stream dnpb.DataNodeInternalRoutes_ReplicateFileServer, token string) error {
+	var fileInfo datanode.File
+	err := datanode.NodeInstance().DB.Connection.Where("token = ?", token).Find(&fileInfo).Error
+	if errors.IsError(err) {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	receivedRanges, err := datanode.DecodeByteRanges(fileInfo.ReceivedRanges)
+	if errors.IsError(err) {
+		return status.Error(codes.Internal, err.Error())
+	}
+
+	return stream.SendAndClose(&dnpb.ReplicateFileResponse{
+		Token:           fileInfo.Token,
+		Completed:       upload.IsComplete(fileInfo),
+		Checksum:        fileInfo.Checksum,
+		ReceivedRanges:  receivedRanges.String(),
+		DurableReplicas: int32(fileInfo.DurableReplicas),
+	})
+}
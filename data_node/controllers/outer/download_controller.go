@@ -0,0 +1,57 @@
+package outer
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+
+	datanode "github.com/SayedAlesawy/Videra-Storage/data_node"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/storage"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/upload"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+	"github.com/SayedAlesawy/Videra-Storage/utils/requests"
+	"github.com/julienschmidt/httprouter"
+)
+
+var dcLogPrefix = "[Download-Controller]"
+
+// DownloadHandler is a handle responsible for serving a completed file under its original filename
+func (server *Server) DownloadHandler(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	id := p.ByName("id")
+	log.Println(dcLogPrefix, r.RemoteAddr, "Received download request for file", id)
+
+	err := storage.ValidateToken(id)
+	if errors.IsError(err) {
+		log.Println(dcLogPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var fileInfo datanode.File
+	notFound := datanode.NodeInstance().DB.Connection.Where("token = ?", id).Find(&fileInfo).RecordNotFound()
+	if notFound {
+		log.Println(dcLogPrefix, r.RemoteAddr, fmt.Sprintf("Record with token: %s is not found", id))
+		requests.HandleRequestError(w, http.StatusNotFound, fmt.Sprintf("Record with token: %s is not found", id))
+		return
+	}
+
+	if !upload.IsComplete(fileInfo) {
+		log.Println(dcLogPrefix, r.RemoteAddr, fmt.Sprintf("File %s is not completely uploaded", id))
+		requests.HandleRequestError(w, http.StatusConflict, fmt.Sprintf("File %s is not completely uploaded", id))
+		return
+	}
+
+	file, err := os.Open(fileInfo.Path)
+	if errors.IsError(err) {
+		log.Println(dcLogPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	defer file.Close()
+
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", storage.ContentDisposition(fileInfo.Name))
+	http.ServeContent(w, r, "", fileInfo.UpdatedAt, file)
+}
@@ -45,8 +45,16 @@ func ServerInstance() *Server {
 func (server *Server) Start() {
 	router := httprouter.New()
 	router.POST("/upload", server.UploadRequestHandler)
+	router.HEAD("/upload", server.UploadStatusHandler)
+	router.DELETE("/upload", server.UploadDeletionHandler)
+	router.OPTIONS("/files", server.TusOptionsHandler)
+	router.POST("/files", server.TusCreationHandler)
+	router.HEAD("/files/:id", server.TusHeadHandler)
+	router.PATCH("/files/:id", server.TusPatchHandler)
+	router.DELETE("/files/:id", server.TusTerminationHandler)
 	router.GET("/stream/*filepath", server.StreamingHandler)
 	router.GET("/thumbnail/*filepath", server.ThumbnailsHandler)
+	router.GET("/download/:id", server.DownloadHandler)
 	address := server.getAddress()
 
 	log.Println(logPrefix, fmt.Sprintf("Listening for external requests on %s", address))
@@ -0,0 +1,148 @@
+package outer
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	datanode "github.com/SayedAlesawy/Videra-Storage/data_node"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/janitor"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/upload"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+	"github.com/SayedAlesawy/Videra-Storage/utils/requests"
+	"github.com/julienschmidt/httprouter"
+)
+
+// uploadStatus Represents the upload progress of a file, used by clients to resume uploads
+type uploadStatus struct {
+	ID         string              `json:"id"`                    //Token of the file
+	Type       string              `json:"type"`                  //Type of the file (video, model)
+	Offset     int64               `json:"offset"`                //Offset at which the next chunk should be sent
+	Size       int64               `json:"size"`                  //Total size of the file in bytes
+	Completed  bool                `json:"completed"`             //Indicates if the file completed uploading
+	Checksum   string              `json:"checksum,omitempty"`    //SHA-256 digest of the file (once completed)
+	Part       string              `json:"part,omitempty"`        //Model sub-file in which the next byte lands (model case only)
+	PartOffset int64               `json:"part_offset,omitempty"` //Offset of the next byte relative to its sub-file (model case only)
+	Ranges     datanode.ByteRanges `json:"ranges"`                //Byte ranges received so far, chunks may be sent for any missing range
+	Replicas   int                 `json:"durable_replicas"`      //Number of replicas verified to hold the whole file
+}
+
+// UploadStatusHandler Handles the HEAD request on the upload endpoint
+func (server *Server) UploadStatusHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	server.handleUploadStatus(w, r, false)
+}
+
+// handleUploadStatus is a function responsible for reporting the upload progress of a file,
+// the status is always set in the response headers, and optionally written as json body
+func (server *Server) handleUploadStatus(w http.ResponseWriter, r *http.Request, writeBody bool) {
+	log.Println(ucLogPrefix, r.RemoteAddr, "Received STATUS request")
+
+	err := requests.ValidateHeaders(&r.Header, "ID")
+	if errors.IsError(err) {
+		log.Println(ucLogPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id := r.Header.Get("ID")
+	var fileInfo datanode.File
+
+	notFound := datanode.NodeInstance().DB.Connection.Where("token = ?", id).Find(&fileInfo).RecordNotFound()
+	if notFound {
+		log.Println(ucLogPrefix, r.RemoteAddr, fmt.Sprintf("Record with token: %s is not found", id))
+		requests.HandleRequestError(w, http.StatusNotFound, fmt.Sprintf("Record with token: %s is not found", id))
+		return
+	}
+
+	receivedRanges, err := datanode.DecodeByteRanges(fileInfo.ReceivedRanges)
+	if errors.IsError(err) {
+		log.Println(ucLogPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	status := uploadStatus{
+		ID:        fileInfo.Token,
+		Type:      fileInfo.Type,
+		Offset:    fileInfo.Offset,
+		Size:      fileInfo.Size,
+		Completed: upload.IsComplete(fileInfo),
+		Checksum:  fileInfo.Checksum,
+		Ranges:    receivedRanges,
+		Replicas:  fileInfo.DurableReplicas,
+	}
+
+	if fileInfo.Type == datanode.ModelFileType && !status.Completed {
+		var modelExtras datanode.ModelExtras
+		err := json.Unmarshal([]byte(fileInfo.Extras), &modelExtras)
+		if errors.IsError(err) {
+			log.Println(ucLogPrefix, r.RemoteAddr, err)
+			requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
+			return
+		}
+
+		status.Part, _, status.PartOffset = upload.GetModelPart(fileInfo, modelExtras, fileInfo.Offset)
+	}
+
+	w.Header().Set("ID", status.ID)
+	w.Header().Set("Filetype", status.Type)
+	w.Header().Set("Offset", fmt.Sprintf("%d", status.Offset))
+	w.Header().Set("Filesize", fmt.Sprintf("%d", status.Size))
+	w.Header().Set("Upload-Complete", fmt.Sprintf("%t", status.Completed))
+	w.Header().Set("Upload-Ranges", status.Ranges.String())
+	w.Header().Set("Durable-Replicas", fmt.Sprintf("%d", status.Replicas))
+	if status.Checksum != "" {
+		w.Header().Set("Checksum", status.Checksum)
+	}
+	if status.Part != "" {
+		w.Header().Set("Upload-Part", status.Part)
+		w.Header().Set("Upload-Part-Offset", fmt.Sprintf("%d", status.PartOffset))
+	}
+
+	if !writeBody {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	resp, err := json.Marshal(status)
+	if errors.IsError(err) {
+		log.Println(ucLogPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	w.Write(resp)
+}
+
+// UploadDeletionHandler Handles the DELETE request on the upload endpoint, removing the upload and its replica
+func (server *Server) UploadDeletionHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	log.Println(ucLogPrefix, r.RemoteAddr, "Received DELETE request")
+
+	err := requests.ValidateHeaders(&r.Header, "ID")
+	if errors.IsError(err) {
+		log.Println(ucLogPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	id := r.Header.Get("ID")
+	var fileInfo datanode.File
+
+	notFound := datanode.NodeInstance().DB.Connection.Where("token = ?", id).Find(&fileInfo).RecordNotFound()
+	if notFound {
+		log.Println(ucLogPrefix, r.RemoteAddr, fmt.Sprintf("Record with token: %s is not found", id))
+		requests.HandleRequestError(w, http.StatusNotFound, fmt.Sprintf("Record with token: %s is not found", id))
+		return
+	}
+
+	err = janitor.RemoveUpload(fileInfo)
+	if errors.IsError(err) {
+		log.Println(ucLogPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
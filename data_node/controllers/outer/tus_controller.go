@@ -0,0 +1,320 @@
+package outer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	datanode "github.com/SayedAlesawy/Videra-Storage/data_node"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/janitor"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+	"github.com/julienschmidt/httprouter"
+)
+
+var tusLogPrefix = "[Tus-Controller]"
+
+// tusVersion The supported version of the tus resumable upload protocol
+var tusVersion = "1.0.0"
+
+// tusExtensions The supported extensions of the tus protocol
+var tusExtensions = "creation,termination,checksum"
+
+// tusChecksumMismatch The status code tus clients expect when a chunk checksum mismatches
+var tusChecksumMismatch = 460
+
+// tusChecksumAlgorithms maps tus checksum algorithms to the digest algorithms used by the upload controller
+var tusChecksumAlgorithms = map[string]string{
+	"md5":    "md5",
+	"sha1":   "sha",
+	"sha256": "sha-256",
+}
+
+// tusMetadataHeaders maps tus Upload-Metadata keys to the headers used by the upload controller
+var tusMetadataHeaders = map[string]string{
+	"filename":            "Filename",
+	"filetype":            "Filetype",
+	"associated_model_id": "Associated-Model-ID",
+	"model_size":          "Model-Size",
+	"config_size":         "Config-Size",
+	"code_size":           "Code-Size",
+	"checksum":            "File-Checksum",
+	"replication_factor":  "Replication-Factor",
+}
+
+// tusResponseWriter Buffers the response of the upload controller, so it can be translated to tus semantics,
+// its headers are kept apart from the tus response, which only gets the tus headers
+type tusResponseWriter struct {
+	header http.Header  //Headers set by the upload controller
+	status int          //Status code set by the upload controller
+	body   bytes.Buffer //Body written by the upload controller
+}
+
+// Header Returns the buffered response headers
+func (tw *tusResponseWriter) Header() http.Header {
+	return tw.header
+}
+
+// Write Buffers the response body
+func (tw *tusResponseWriter) Write(data []byte) (int, error) {
+	if tw.status == 0 {
+		tw.status = http.StatusOK
+	}
+
+	return tw.body.Write(data)
+}
+
+// WriteHeader Buffers the response status code
+func (tw *tusResponseWriter) WriteHeader(statusCode int) {
+	if tw.status == 0 {
+		tw.status = statusCode
+	}
+}
+
+// succeeded Indicates whether the upload controller handled the request successfully,
+// a request it didn't write a status for isn't considered handled
+func (tw *tusResponseWriter) succeeded() bool {
+	return tw.status >= 200 && tw.status < 300
+}
+
+// forward Writes the buffered failure status and body as is, or an internal error if no status was written
+func (tw *tusResponseWriter) forward(w http.ResponseWriter) {
+	if tw.status == 0 {
+		handleRequestError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(tw.status)
+	w.Write(tw.body.Bytes())
+}
+
+// TusOptionsHandler Handles the tus OPTIONS request, advertising the server capabilities
+func (server *Server) TusOptionsHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	w.Header().Set("Tus-Resumable", tusVersion)
+	w.Header().Set("Tus-Version", tusVersion)
+	w.Header().Set("Tus-Extension", tusExtensions)
+	w.Header().Set("Tus-Checksum-Algorithm", "md5,sha1,sha256")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// TusCreationHandler Handles the tus creation request, mapped onto the upload init request
+func (server *Server) TusCreationHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	log.Println(tusLogPrefix, r.RemoteAddr, "Received creation request")
+
+	if !server.validateTusVersion(w, r) {
+		return
+	}
+
+	if r.Header.Get("Upload-Length") == "" {
+		log.Println(tusLogPrefix, r.RemoteAddr, "Upload-Length header not provided")
+		handleRequestError(w, http.StatusBadRequest, "Upload-Length header not provided")
+		return
+	}
+
+	metadata, err := parseTusMetadata(r.Header.Get("Upload-Metadata"))
+	if errors.IsError(err) {
+		log.Println(tusLogPrefix, r.RemoteAddr, err)
+		handleRequestError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	r.Header.Set("Request-Type", "init")
+	r.Header.Set("Filesize", r.Header.Get("Upload-Length"))
+	for key, header := range tusMetadataHeaders {
+		if value, ok := metadata[key]; ok {
+			r.Header.Set(header, value)
+		}
+	}
+
+	tw := tusResponseWriter{header: make(http.Header)}
+	server.handleInitialUpload(&tw, r)
+	if !tw.succeeded() {
+		tw.forward(w)
+		return
+	}
+
+	if tw.header.Get("ID") == "" {
+		log.Println(tusLogPrefix, r.RemoteAddr, "Upload was not created")
+		handleRequestError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/files/%s", tw.header.Get("ID")))
+	w.WriteHeader(http.StatusCreated)
+}
+
+// TusHeadHandler Handles the tus HEAD request, reporting the upload offset
+func (server *Server) TusHeadHandler(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	if !server.validateTusVersion(w, r) {
+		return
+	}
+
+	fileInfo, found := server.findTusUpload(p.ByName("id"))
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Upload-Offset", fmt.Sprintf("%d", fileInfo.Offset))
+	w.Header().Set("Upload-Length", fmt.Sprintf("%d", fileInfo.Size))
+	w.WriteHeader(http.StatusOK)
+}
+
+// TusPatchHandler Handles the tus PATCH request, mapped onto the upload append request
+func (server *Server) TusPatchHandler(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	log.Println(tusLogPrefix, r.RemoteAddr, "Received PATCH request")
+
+	if !server.validateTusVersion(w, r) {
+		return
+	}
+
+	if r.Header.Get("Content-Type") != "application/offset+octet-stream" {
+		log.Println(tusLogPrefix, r.RemoteAddr, "Invalid content type", r.Header.Get("Content-Type"))
+		handleRequestError(w, http.StatusUnsupportedMediaType, "Content-Type should be application/offset+octet-stream")
+		return
+	}
+
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if errors.IsError(err) {
+		log.Println(tusLogPrefix, r.RemoteAddr, "Invalid Upload-Offset header", r.Header.Get("Upload-Offset"))
+		handleRequestError(w, http.StatusBadRequest, "Invalid Upload-Offset header")
+		return
+	}
+
+	id := p.ByName("id")
+	fileInfo, found := server.findTusUpload(id)
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// tus clients upload sequentially, so a chunk not starting at the upload offset conflicts with it
+	if offset != fileInfo.Offset {
+		log.Println(tusLogPrefix, r.RemoteAddr, fmt.Sprintf("Upload-Offset %d mismatches offset %d", offset, fileInfo.Offset))
+		w.Header().Set("Upload-Offset", fmt.Sprintf("%d", fileInfo.Offset))
+		handleRequestError(w, http.StatusConflict, "Upload-Offset mismatches the upload offset")
+		return
+	}
+
+	if uploadChecksum := r.Header.Get("Upload-Checksum"); uploadChecksum != "" {
+		parts := strings.SplitN(uploadChecksum, " ", 2)
+		algorithm, ok := tusChecksumAlgorithms[strings.ToLower(parts[0])]
+		if len(parts) != 2 || !ok {
+			log.Println(tusLogPrefix, r.RemoteAddr, "Unsupported checksum", uploadChecksum)
+			handleRequestError(w, http.StatusBadRequest, "Unsupported checksum algorithm")
+			return
+		}
+
+		r.Header.Set("Digest", fmt.Sprintf("%s=%s", algorithm, parts[1]))
+	}
+
+	r.Header.Set("Request-Type", "append")
+	r.Header.Set("ID", id)
+	r.Header.Set("Offset", r.Header.Get("Upload-Offset"))
+
+	tw := tusResponseWriter{header: make(http.Header)}
+	server.handleAppendUpload(&tw, r)
+	if !tw.succeeded() {
+		// a chunk racing another one to the same offset is reported by the upload controller with the expected offset
+		if tw.status == http.StatusBadRequest && tw.header.Get("Offset") != "" {
+			w.Header().Set("Upload-Offset", tw.header.Get("Offset"))
+			tw.status = http.StatusConflict
+		}
+
+		tw.forward(w)
+		return
+	}
+
+	fileInfo, found = server.findTusUpload(id)
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Upload-Offset", fmt.Sprintf("%d", fileInfo.Offset))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// TusTerminationHandler Handles the tus DELETE request, removing the upload
+func (server *Server) TusTerminationHandler(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	log.Println(tusLogPrefix, r.RemoteAddr, "Received termination request")
+
+	if !server.validateTusVersion(w, r) {
+		return
+	}
+
+	fileInfo, found := server.findTusUpload(p.ByName("id"))
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	err := janitor.RemoveUpload(fileInfo)
+	if errors.IsError(err) {
+		log.Println(tusLogPrefix, r.RemoteAddr, err)
+		handleRequestError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateTusVersion is a function to validate the tus protocol version requested by the client
+func (server *Server) validateTusVersion(w http.ResponseWriter, r *http.Request) bool {
+	w.Header().Set("Tus-Resumable", tusVersion)
+
+	if r.Header.Get("Tus-Resumable") != tusVersion {
+		log.Println(tusLogPrefix, r.RemoteAddr, "Unsupported tus version", r.Header.Get("Tus-Resumable"))
+		w.Header().Set("Tus-Version", tusVersion)
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return false
+	}
+
+	return true
+}
+
+// findTusUpload is a function to fetch the file info of an upload
+func (server *Server) findTusUpload(id string) (datanode.File, bool) {
+	var fileInfo datanode.File
+
+	notFound := datanode.NodeInstance().DB.Connection.Where("token = ?", id).Find(&fileInfo).RecordNotFound()
+
+	return fileInfo, !notFound
+}
+
+// isTusRequest checks if the request was issued by a tus client
+func isTusRequest(r *http.Request) bool {
+	return r.Header.Get("Tus-Resumable") != ""
+}
+
+// parseTusMetadata is a function to decode the tus Upload-Metadata header,
+// which is a comma separated list of keys and base64 encoded values
+func parseTusMetadata(header string) (map[string]string, error) {
+	metadata := make(map[string]string)
+
+	for _, pair := range strings.Split(header, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		parts := strings.SplitN(pair, " ", 2)
+		if len(parts) == 1 {
+			metadata[parts[0]] = ""
+			continue
+		}
+
+		value, err := base64.StdEncoding.DecodeString(parts[1])
+		if errors.IsError(err) {
+			return nil, errors.New(fmt.Sprintf("Malformed Upload-Metadata value for key %s", parts[0]))
+		}
+
+		metadata[parts[0]] = string(value)
+	}
+
+	return metadata, nil
+}
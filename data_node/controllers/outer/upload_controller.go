@@ -1,25 +1,20 @@
 package outer
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"log"
 	"net/http"
 	"os"
-	"os/exec"
-	"path"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/SayedAlesawy/Videra-Storage/config"
 	datanode "github.com/SayedAlesawy/Videra-Storage/data_node"
-	"github.com/SayedAlesawy/Videra-Storage/data_node/ingest"
 	"github.com/SayedAlesawy/Videra-Storage/data_node/replication"
-	"github.com/SayedAlesawy/Videra-Storage/data_node/stream"
-	"github.com/SayedAlesawy/Videra-Storage/data_node/thumbnail"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/storage"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/upload"
 	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
 	"github.com/SayedAlesawy/Videra-Storage/utils/requests"
 	"github.com/julienschmidt/httprouter"
@@ -27,9 +22,6 @@ import (
 
 var ucLogPrefix = "[Upload-Controller]"
 
-//ModelUploadOrder represents the order in which model files will be uploaded
-var modelUploadOrder = [...]string{"model", "config", "code"}
-
 // UploadRequestHandler is upload endpoint handler
 func (server *Server) UploadRequestHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	reqType := strings.ToLower(r.Header.Get("Request-Type"))
@@ -39,6 +31,8 @@ func (server *Server) UploadRequestHandler(w http.ResponseWriter, r *http.Reques
 		server.handleInitialUpload(w, r)
 	case "append":
 		server.handleAppendUpload(w, r)
+	case "status":
+		server.handleUploadStatus(w, r, true)
 	default:
 		log.Println(ucLogPrefix, r.RemoteAddr, fmt.Sprintf("request-type header value undefined - %s", reqType))
 
@@ -66,11 +60,38 @@ func (server *Server) handleInitialUpload(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	err = validateChecksumFormat(r.Header.Get("File-Checksum"))
+	if errors.IsError(err) {
+		log.Println(ucLogPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filename, err := storage.SanitizeFilename(r.Header.Get("Filename"))
+	if errors.IsError(err) {
+		log.Println(ucLogPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	r.Header.Set("Filename", filename)
+
+	if factor := r.Header.Get("Replication-Factor"); factor != "" {
+		replicationFactor, err := strconv.Atoi(factor)
+		if errors.IsError(err) || replicationFactor < 1 {
+			log.Println(ucLogPrefix, r.RemoteAddr, "Invalid replication factor", factor)
+			requests.HandleRequestError(w, http.StatusBadRequest, "Invalid Replication-Factor header")
+			return
+		}
+	}
+
 	switch fileType {
 	case datanode.ModelFileType:
 		server.handleModelInitialUpload(w, r)
 	case datanode.VideoFileType:
 		server.handleVideoInitialUpload(w, r)
+	default:
+		log.Println(ucLogPrefix, r.RemoteAddr, "Unsupported file type", fileType)
+		handleRequestError(w, http.StatusBadRequest, "Supported types are video and model")
 	}
 }
 
@@ -90,78 +111,16 @@ func (server *Server) handleModelInitialUpload(w http.ResponseWriter, r *http.Re
 		handleRequestError(w, http.StatusBadRequest, err.Error())
 		return
 	}
-	filesize, _ := strconv.ParseInt(r.Header.Get("Filesize"), 10, 64)
 	modelSize, _ := strconv.ParseInt(r.Header.Get("Model-Size"), 10, 64)
 	configSize, _ := strconv.ParseInt(r.Header.Get("Config-Size"), 10, 64)
 	codeSize, _ := strconv.ParseInt(r.Header.Get("Code-Size"), 10, 64)
+	replicationFactor, _ := strconv.Atoi(r.Header.Get("Replication-Factor"))
 
 	id := datanode.GenerateRandomString(10)
-	filename := r.Header.Get("Filename")
-	fileType := strings.ToLower(r.Header.Get("Filetype"))
-
-	wd, _ := os.Getwd()
-	// file will be at path .../files/id/filaname
-	folderpath := path.Join(wd, "files", id)
-
-	modelPath := path.Join(folderpath, filename)
-	configPath := path.Join(folderpath, fmt.Sprintf("%s_config.conf", id))
-	codePath := path.Join(folderpath, "code_file.py")
-
-	log.Println(ucLogPrefix, r.RemoteAddr, "creating file with id", id)
-	err = datanode.CreateFileDirectory(folderpath, 0744)
-	if errors.IsError(err) {
-		log.Println(ucLogPrefix, r.RemoteAddr, err)
-		handleRequestError(w, http.StatusInternalServerError, "Internal server error")
-		return
-	}
+	fileInfo := upload.NewModel(id, id, r.Header.Get("Filename"), modelSize, configSize, codeSize, r.Header.Get("File-Checksum"))
 
-	err = datanode.CreateFile(modelPath)
-	if errors.IsError(err) {
-		log.Println(ucLogPrefix, r.RemoteAddr, err)
-		handleRequestError(w, http.StatusInternalServerError, "Internal server error")
-		return
-	}
-
-	err = datanode.CreateFile(configPath)
-	if errors.IsError(err) {
-		log.Println(ucLogPrefix, r.RemoteAddr, err)
-		handleRequestError(w, http.StatusInternalServerError, "Internal server error")
-		return
-	}
-
-	err = datanode.CreateFile(codePath)
-	if errors.IsError(err) {
-		log.Println(ucLogPrefix, r.RemoteAddr, err)
-		handleRequestError(w, http.StatusInternalServerError, "Internal server error")
-		return
-	}
-
-	extras := datanode.ModelExtras{
-		ModelSize:            modelSize,
-		AssociatedConfigPath: configPath,
-		AssociatedConfigSize: configSize,
-		AssociatedCodePath:   codePath,
-		AssociatedCodeSize:   codeSize,
-	}
-
-	parentID := id
-	if r.Header.Get("Parent") != "" {
-		parentID = r.Header.Get("Parent")
-	}
-
-	extrasBytes, _ := json.Marshal(extras)
-	//Insert a file info record in the database
-	err = datanode.NodeInstance().DB.Connection.Create(&datanode.File{
-		Token:      id,
-		Name:       filename,
-		Type:       fileType,
-		Path:       modelPath,
-		Extras:     string(extrasBytes),
-		Size:       filesize,
-		DataNodeID: datanode.NodeInstance().ID,
-		Parent:     parentID,
-		Offset:     0,
-	}).Error
+	// the model is replicated down a chain of nodes, so videos associated with it can be uploaded to any of them
+	err = server.createFile(&fileInfo, replicationFactor)
 	if errors.IsError(err) {
 		log.Println(ucLogPrefix, r.RemoteAddr, err)
 		handleRequestError(w, http.StatusInternalServerError, "Internal server error")
@@ -172,7 +131,7 @@ func (server *Server) handleModelInitialUpload(w http.ResponseWriter, r *http.Re
 
 	w.Header().Set("ID", id)
 	w.Header().Set("Max-Request-Size", fmt.Sprintf("%d", maxRequestSize))
-	uploadOrderJSON, _ := json.Marshal(modelUploadOrder)
+	uploadOrderJSON, _ := json.Marshal(upload.ModelUploadOrder)
 	w.Header().Set("Upload-Order", string(uploadOrderJSON))
 	w.WriteHeader(http.StatusCreated)
 }
@@ -206,7 +165,11 @@ func (server *Server) handleVideoInitialUpload(w http.ResponseWriter, r *http.Re
 		requests.HandleRequestError(w, http.StatusBadRequest, "Associated Model ID not provided")
 		return
 	}
-	notFound := datanode.NodeInstance().DB.Connection.Where("parent = ?", associatedModelID).RecordNotFound()
+	// the model is only needed by the original node running the ingestion, replicas may be on any node
+	notFound := datanode.NodeInstance().DB.Connection.
+		Where("parent = ? AND type = ? AND data_node_id = ? AND completed_at IS NOT NULL",
+			associatedModelID, datanode.ModelFileType, datanode.NodeInstance().ID).
+		Find(&datanode.File{}).RecordNotFound()
 	if notFound {
 		log.Println(ucLogPrefix, r.RemoteAddr, fmt.Sprintf("Model with token: %s is not found", associatedModelID))
 		requests.HandleRequestError(w, http.StatusNotFound, fmt.Sprintf("Record with token: %s is not found", associatedModelID))
@@ -220,76 +183,40 @@ func (server *Server) handleVideoInitialUpload(w http.ResponseWriter, r *http.Re
 	}
 
 	filesize, _ := strconv.ParseInt(r.Header.Get("Filesize"), 10, 64)
+	replicationFactor, _ := strconv.Atoi(r.Header.Get("Replication-Factor"))
+
 	id := datanode.GenerateRandomString(10)
-	filename := r.Header.Get("Filename")
-	fileType := strings.ToLower(r.Header.Get("Filetype"))
-	wd, _ := os.Getwd()
-	// file will be at path .../files/id/filaname
-	folderpath := path.Join(wd, "files", id)
-	filepath := path.Join(folderpath, filename)
+	fileInfo := upload.NewVideo(id, id, r.Header.Get("Filename"), filesize, r.Header.Get("File-Checksum"), associatedModelID)
 
-	log.Println(ucLogPrefix, r.RemoteAddr, "creating file with id", id)
-	err = datanode.CreateFileDirectory(folderpath, 0744)
+	// the original node starts the replica chain, and each replica forwards to the next node in it
+	err = server.createFile(&fileInfo, replicationFactor)
 	if errors.IsError(err) {
 		log.Println(ucLogPrefix, r.RemoteAddr, err)
 		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
-	err = datanode.CreateFile(filepath)
-	if errors.IsError(err) {
-		log.Println(ucLogPrefix, r.RemoteAddr, err)
-		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
-		return
-	}
+	maxRequestSize := config.ConfigurationManagerInstance("").DataNodeConfig().MaxRequestSize
 
-	metadata := datanode.VideoMetadata{}
-	metadata.AssociatedModel = associatedModelID
-	metadataJSON, err := json.Marshal(metadata)
-	if errors.IsError(err) {
-		log.Println(ucLogPrefix, r.RemoteAddr, err)
-		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
-		return
-	}
+	w.Header().Set("ID", id)
+	w.Header().Set("Max-Request-Size", fmt.Sprintf("%d", maxRequestSize))
+	w.WriteHeader(http.StatusCreated)
+}
 
-	parentID := id
-	if r.Header.Get("Parent") != "" {
-		parentID = r.Header.Get("Parent")
+// createFile is a function to start the replica chain of a new file, then create its blobs and info record
+func (server *Server) createFile(fileInfo *datanode.File, replicationFactor int) error {
+	pipeline, err := replication.GetPipeline(fileInfo.Type, replicationFactor, fileInfo.Size)
+	if errors.IsError(err) {
+		return err
 	}
 
-	// original node
-	if parentID == id {
-		log.Println(ucLogPrefix, "Send replicated init")
-		err := replication.ReplicateVideo(r, id)
-		if errors.IsError(err) {
-			log.Println(ucLogPrefix, r.RemoteAddr, err)
-			requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
-			return
-		}
-	}
-	//Insert a file info record in the database
-	err = datanode.NodeInstance().DB.Connection.Create(&datanode.File{
-		Token:      id,
-		Name:       filename,
-		Type:       fileType,
-		Path:       filepath,
-		Size:       filesize,
-		Extras:     string(metadataJSON),
-		DataNodeID: datanode.NodeInstance().ID,
-		Parent:     parentID,
-		Offset:     0,
-	}).Error
+	log.Println(ucLogPrefix, "Start replica chain of file", fileInfo.Token, pipeline)
+	fileInfo.ReplicaCount, err = replication.StartReplication(*fileInfo, pipeline)
 	if errors.IsError(err) {
-		log.Println(ucLogPrefix, r.RemoteAddr, err)
-		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
-		return
+		return err
 	}
 
-	maxRequestSize := config.ConfigurationManagerInstance("").DataNodeConfig().MaxRequestSize
-
-	w.Header().Set("ID", id)
-	w.Header().Set("Max-Request-Size", fmt.Sprintf("%d", maxRequestSize))
-	w.WriteHeader(http.StatusCreated)
+	return upload.Create(fileInfo)
 }
 
 // handleAppendUpload is a function responsible for handling the first upload request
@@ -331,7 +258,7 @@ func (server *Server) handleAppendUpload(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if server.isFileComplete(fileInfo) {
+	if upload.IsComplete(fileInfo) {
 		log.Println(ucLogPrefix, r.RemoteAddr, "File was completed from previous upload")
 		w.WriteHeader(http.StatusCreated)
 		return
@@ -339,133 +266,135 @@ func (server *Server) handleAppendUpload(w http.ResponseWriter, r *http.Request)
 
 	contentLength := r.ContentLength
 	offset, err := strconv.ParseInt(r.Header.Get("Offset"), 10, 64)
-	if errors.IsError(err) || !server.validateFileOffset(fileInfo, offset, contentLength) {
-		log.Println(ucLogPrefix, r.RemoteAddr, fmt.Sprintf("Invalid file offset, expected %v found %v", fileInfo.Offset, r.Header.Get("Offset")))
+	if errors.IsError(err) || !upload.ValidOffset(fileInfo, offset, contentLength) {
+		log.Println(ucLogPrefix, r.RemoteAddr, fmt.Sprintf("Invalid file offset %v for file of size %v", r.Header.Get("Offset"), fileInfo.Size))
 		w.Header().Set("Offset", fmt.Sprintf("%d", fileInfo.Offset))
 		requests.HandleRequestError(w, http.StatusBadRequest, "Invalid offset")
 		return
 	}
 
-	filePath := fileInfo.Path
-
-	// writeoffset is offset at file which will be written at,
-	// in video file it's the same as offset variable,
-	// but in model it's relative based on which file is being written at,
-	// for example offset maybe 500 but it will write at offset 0 of config file
-	var writeOffset int64
-	writeOffset = offset
+	filePath, writeOffset, err := upload.ChunkPath(fileInfo, offset, contentLength)
+	if err == upload.ErrChunkSpansParts {
+		log.Println(ucLogPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	if fileInfo.Type == datanode.ModelFileType {
-		var modelExtras datanode.ModelExtras
-		err := json.Unmarshal([]byte(fileInfo.Extras), &modelExtras)
-		if errors.IsError(err) {
-			log.Println(ucLogPrefix, r.RemoteAddr, err)
-			handleRequestError(w, http.StatusInternalServerError, "Internal server error")
-			return
-		}
+	if errors.IsError(err) {
+		log.Println(ucLogPrefix, r.RemoteAddr, err)
+		handleRequestError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
 
-		// ****Model****/**Config**/*Code*/
-		if offset >= modelExtras.ModelSize {
-			// chunk is either belongs to config file or code file
-			if offset < modelExtras.ModelSize+modelExtras.AssociatedConfigSize {
-				filePath = modelExtras.AssociatedConfigPath
-				writeOffset -= modelExtras.ModelSize
-			} else if offset >= modelExtras.ModelSize+modelExtras.AssociatedConfigSize {
-				filePath = modelExtras.AssociatedCodePath
-				writeOffset -= modelExtras.ModelSize + modelExtras.AssociatedConfigSize
-			}
-		}
+	// chunks can arrive concurrently and out of order, so the chunk range is reserved
+	// to reject overlapping chunks while this one is being written
+	chunk := datanode.ByteRange{Start: offset, End: offset + contentLength}
+	receivedRanges, err := upload.Reserve(id, chunk)
+	if errors.IsError(err) {
+		log.Println(ucLogPrefix, r.RemoteAddr, err)
+		w.Header().Set("Offset", fmt.Sprintf("%d", receivedRanges.ContiguousEnd()))
+		w.Header().Set("Upload-Ranges", receivedRanges.String())
+		requests.HandleRequestError(w, http.StatusBadRequest, "Invalid offset")
+		return
 	}
+	defer upload.Release(id, chunk)
 
 	file, err := os.OpenFile(filePath, os.O_WRONLY, 0644)
-	defer file.Close()
 	if errors.IsError(err) {
 		log.Println(ucLogPrefix, r.RemoteAddr, err)
 		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
+	defer file.Close()
 
-	body, err := ioutil.ReadAll(r.Body)
+	digestVerifier, err := newChunkDigestVerifier(&r.Header)
 	if errors.IsError(err) {
 		log.Println(ucLogPrefix, r.RemoteAddr, err)
-		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
+		requests.HandleRequestError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	log.Println(ucLogPrefix, r.RemoteAddr, filePath, "Writing at offset", fileInfo.Offset)
-	file.WriteAt(body, writeOffset)
+	// in sync mode, the chunk is shipped to the next replica in the chain once its digests are verified,
+	// so a replica never holds bytes the client is asked to resend, otherwise it's acknowledged
+	// once written locally and shipped to the replica by the replication worker, which also ships
+	// the chunks that failed to be shipped in sync mode
+	replicate := fileInfo.ReplicaCount > 0 && replication.SyncMode()
 
-	//Update values
-	fileInfo.Offset += contentLength
-	if fileInfo.Offset == fileInfo.Size {
-		now := time.Now()
-		fileInfo.CompletedAt = &now
+	log.Println(ucLogPrefix, r.RemoteAddr, filePath, "Writing at offset", offset)
+	writer := io.MultiWriter(upload.NewOffsetWriter(file, writeOffset), digestVerifier)
+	written, err := io.Copy(writer, r.Body)
+	if errors.IsError(err) || written != contentLength {
+		log.Println(ucLogPrefix, r.RemoteAddr, "Unable to write chunk", written, err)
+		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
 
-		if fileInfo.Type == datanode.VideoFileType {
-			var videoMetadata datanode.VideoMetadata
-			json.Unmarshal([]byte(fileInfo.Extras), &videoMetadata)
-			err := server.fetchVideoMetaData(fileInfo, &videoMetadata)
-			if errors.IsError(err) {
-				log.Println(ucLogPrefix, r.RemoteAddr, err)
-				handleRequestError(w, http.StatusInternalServerError, "Internal server error")
-				return
-			}
-			metaData, err := json.Marshal(videoMetadata)
-			fileInfo.Extras = string(metaData)
+	err = digestVerifier.verify()
+	if errors.IsError(err) {
+		log.Println(ucLogPrefix, r.RemoteAddr, err)
+		statusCode := http.StatusBadRequest
+		if isTusRequest(r) {
+			statusCode = tusChecksumMismatch
 		}
+
+		w.Header().Set("Offset", fmt.Sprintf("%d", fileInfo.Offset))
+		requests.HandleRequestError(w, statusCode, err.Error())
+		return
 	}
 
-	if fileInfo.Type == datanode.VideoFileType && !isReplica(fileInfo) {
-		r.Body = ioutil.NopCloser(bytes.NewReader(body))
-		err := replication.ReplicateVideo(r, id)
+	if replicate {
+		err := replicateWrittenChunk(id, filePath, offset, writeOffset, contentLength)
 		if errors.IsError(err) {
-			log.Println(ucLogPrefix, r.RemoteAddr, err)
-			handleRequestError(w, http.StatusInternalServerError, "Internal server error")
-			return
+			log.Println(ucLogPrefix, r.RemoteAddr, "Chunk left to the replication worker", err)
 		}
 	}
 
-	err = datanode.NodeInstance().DB.Connection.Save(&fileInfo).Error
+	fileInfo, completed, err := upload.CommitChunk(id, chunk)
+	if err == upload.ErrFileChecksumMismatch {
+		log.Println(ucLogPrefix, r.RemoteAddr, err)
+		w.Header().Set("Offset", fmt.Sprintf("%d", fileInfo.Offset))
+		requests.HandleRequestError(w, http.StatusUnprocessableEntity, "File checksum mismatch, upload has to be restarted")
+		return
+	}
+
 	if errors.IsError(err) {
 		log.Println(ucLogPrefix, r.RemoteAddr, err)
 		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
 		return
 	}
 
-	if fileInfo.Offset == fileInfo.Size {
-		if fileInfo.Type == datanode.VideoFileType {
-			if !isReplica(fileInfo) {
-				go ingest.StartJob(fileInfo)
-			} else {
-				go func() {
-					thumbnail.PrepareThumbnail(fileInfo)
-					stream.PrepareStreamingVideo(fileInfo)
-				}()
+	w.Header().Set("Offset", fmt.Sprintf("%d", fileInfo.Offset))
+
+	if completed {
+		// the completion is already saved, so processing starts regardless of the replica, a replica
+		// failing verification is reported as not durable, and verified later by the replication worker
+		upload.StartProcessing(fileInfo)
+
+		if replicate {
+			durableReplicas, err := replication.VerifyReplica(fileInfo)
+			if errors.IsError(err) {
+				log.Println(ucLogPrefix, r.RemoteAddr, err)
 			}
+
+			fileInfo.DurableReplicas = durableReplicas
 		}
 
 		log.Println(ucLogPrefix, r.RemoteAddr, fmt.Sprintf("File %s was uploaded successfully!", filePath))
+		w.Header().Set("Checksum", fileInfo.Checksum)
+		w.Header().Set("Durable-Replicas", fmt.Sprintf("%d", fileInfo.DurableReplicas))
 		w.WriteHeader(http.StatusCreated)
 	}
-
 }
 
-// validateFileOffset A function validate file offset
-func (server *Server) validateFileOffset(fileinfo datanode.File, offset int64, chunkSize int64) bool {
-	if offset < 0 {
-		return false
-	}
-
-	if fileinfo.Offset == offset && fileinfo.Offset+chunkSize <= fileinfo.Size {
-		return true
+// replicateWrittenChunk is a function to ship a chunk written to disk to the next replica in the chain
+func replicateWrittenChunk(token string, filePath string, offset int64, writeOffset int64, size int64) error {
+	file, err := os.Open(filePath)
+	if errors.IsError(err) {
+		return err
 	}
+	defer file.Close()
 
-	return false
-}
-
-// isFileComplete A function to check if file upload was completed previously
-func (server *Server) isFileComplete(fileinfo datanode.File) bool {
-	return fileinfo.CompletedAt != nil
+	return replication.ReplicateChunk(token, offset, io.NewSectionReader(file, writeOffset, size), size)
 }
 
 func (server *Server) validateFileTypes(fileType string) bool {
@@ -474,43 +403,5 @@ func (server *Server) validateFileTypes(fileType string) bool {
 			return true
 		}
 	}
-	return true
-}
-
-// fetchVideoMetaData is a function responsible of retrieving metadata from video file
-func (server *Server) fetchVideoMetaData(fileInfo datanode.File, extras *datanode.VideoMetadata) error {
-	dataNodeConfig := config.ConfigurationManagerInstance("").DataNodeConfig()
-
-	//temp file to save metadata
-	tempFile, err := ioutil.TempFile("", "*_metadata.txt")
-	if err != nil {
-		return err
-	}
-	tempFile.Close()
-	defer os.Remove(tempFile.Name()) //remove the temp file at the end
-
-	command := dataNodeConfig.MetadataCommand
-	script := dataNodeConfig.MetadataScriptPath
-	inputFile := fileInfo.Path
-	outputFile := tempFile.Name()
-
-	cmd := exec.Command(command, script, "-i", inputFile, "-o", outputFile)
-	err = cmd.Run()
-	if errors.IsError(err) {
-		return err
-	}
-	cmd.Wait()
-
-	content, err := ioutil.ReadFile(outputFile)
-	if errors.IsError(err) {
-		return err
-	}
-	err = json.Unmarshal(content, extras)
-	if errors.IsError(err) {
-		return err
-	}
-	if extras.FramesCount == 0 {
-		return errors.New("Error parsing file")
-	}
-	return nil
+	return false
 }
@@ -1,11 +1,18 @@
 package outer
 
 import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"hash"
 	"net/http"
 	"strconv"
+	"strings"
 
-	datanode "github.com/SayedAlesawy/Videra-Storage/data_node"
 	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
 )
 
@@ -36,10 +43,88 @@ func isValideSize(param string) error {
 	return nil
 }
 
-// isReplica checks if the given file is an original file or replica
-func isReplica(fileInfo datanode.File) bool {
-	if fileInfo.Token == fileInfo.Parent {
-		return false
+// digestAlgorithms maps the supported digest algorithms (RFC 3230 names) to their hash constructors
+var digestAlgorithms = map[string]func() hash.Hash{
+	"md5":     md5.New,
+	"sha":     sha1.New,
+	"sha-256": sha256.New,
+}
+
+// chunkDigestVerifier Computes the digests of a chunk while being written,
+// to verify them against the ones sent by the client
+type chunkDigestVerifier struct {
+	expectedDigests map[string][]byte    //Expected digest per algorithm
+	hashes          map[string]hash.Hash //Running hash per algorithm
+}
+
+// newChunkDigestVerifier is a function to create a verifier for the digests sent by the client,
+// in either the Content-MD5 header or the Digest header, chunks without digests are accepted
+func newChunkDigestVerifier(h *http.Header) (*chunkDigestVerifier, error) {
+	verifier := chunkDigestVerifier{
+		expectedDigests: make(map[string][]byte),
+		hashes:          make(map[string]hash.Hash),
 	}
-	return true
+
+	encodedDigests := make(map[string]string)
+	if contentMD5 := h.Get("Content-MD5"); contentMD5 != "" {
+		encodedDigests["md5"] = contentMD5
+	}
+
+	for _, digest := range strings.Split(h.Get("Digest"), ",") {
+		parts := strings.SplitN(strings.TrimSpace(digest), "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+
+		algorithm := strings.ToLower(parts[0])
+		if _, ok := digestAlgorithms[algorithm]; ok {
+			encodedDigests[algorithm] = parts[1]
+		}
+	}
+
+	for algorithm, encodedDigest := range encodedDigests {
+		expected, err := base64.StdEncoding.DecodeString(encodedDigest)
+		if errors.IsError(err) {
+			return nil, errors.New(fmt.Sprintf("Malformed %s digest", algorithm))
+		}
+
+		verifier.expectedDigests[algorithm] = expected
+		verifier.hashes[algorithm] = digestAlgorithms[algorithm]()
+	}
+
+	return &verifier, nil
+}
+
+// Write Feeds the chunk bytes to the running hashes
+func (verifier *chunkDigestVerifier) Write(data []byte) (int, error) {
+	for _, hash := range verifier.hashes {
+		hash.Write(data)
+	}
+
+	return len(data), nil
+}
+
+// verify is a function to compare the computed digests against the expected ones
+func (verifier *chunkDigestVerifier) verify() error {
+	for algorithm, hash := range verifier.hashes {
+		if !bytes.Equal(hash.Sum(nil), verifier.expectedDigests[algorithm]) {
+			return errors.New(fmt.Sprintf("Chunk %s digest mismatch", algorithm))
+		}
+	}
+
+	return nil
+}
+
+// validateChecksumFormat is a function to validate that the whole file checksum is a hex SHA-256 digest
+func validateChecksumFormat(checksum string) error {
+	if checksum == "" {
+		return nil
+	}
+
+	decoded, err := hex.DecodeString(checksum)
+	if errors.IsError(err) || len(decoded) != sha256.Size {
+		return errors.New("File-Checksum header should be a hex encoded SHA-256 digest")
+	}
+
+	return nil
 }
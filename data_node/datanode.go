@@ -34,17 +34,27 @@ func NodeInstance() *DataNode {
 			Port:                  dataNodeConfig.Port,
 			InternalPort:          dataNodeConfig.InternalRequestsPort,
 			GPU:                   checkGPUStatus(dataNodeConfig.GPU),
+			Zone:                  dataNodeConfig.Zone,
+			Rack:                  dataNodeConfig.Rack,
+			DiskClass:             dataNodeConfig.DiskClass,
 			InternalReqTimeout:    time.Duration(dataNodeConfig.InternalReqTimeout) * time.Second,
 			RejoinClusterInterval: time.Duration(dataNodeConfig.RejoinClusterInterval) * time.Second,
 			NameNode: NameNodeData{
 				IP:   dataNodeConfig.NameNodeIP,
 				Port: dataNodeConfig.NameNodeInternalRequestsPort,
 			},
+			NameNodes: parseNameNodes(dataNodeConfig.NameNodeAddresses),
 			DB:    database.DBInstance(dataNodeConfig.StorageDBName),
 			Cache: cacheInstance,
 		}
 
-		dataNode.DB.Connection.AutoMigrate(&File{})
+		// the name node given by IP and port is the only one, unless a list of name nodes is given
+		if len(dataNode.NameNodes) == 0 {
+			dataNode.NameNodes = []NameNodeData{dataNode.NameNode}
+		}
+		dataNode.NameNode = dataNode.NameNodes[0]
+
+		dataNode.DB.Connection.AutoMigrate(&File{}, &ReplicationLog{})
 
 		dataNodeInstance = &dataNode
 	})
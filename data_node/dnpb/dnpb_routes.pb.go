@@ -49,6 +49,56 @@ func (HealthCheckResponse_NodeStatus) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_08edf9c909488729, []int{1, 0}
 }
 
+type DeleteFileResponse_DeletionStatus int32
+
+const (
+	DeleteFileResponse_SUCCESS DeleteFileResponse_DeletionStatus = 0
+	DeleteFileResponse_FAILURE DeleteFileResponse_DeletionStatus = 1
+)
+
+var DeleteFileResponse_DeletionStatus_name = map[int32]string{
+	0: "SUCCESS",
+	1: "FAILURE",
+}
+
+var DeleteFileResponse_DeletionStatus_value = map[string]int32{
+	"SUCCESS": 0,
+	"FAILURE": 1,
+}
+
+func (x DeleteFileResponse_DeletionStatus) String() string {
+	return proto.EnumName(DeleteFileResponse_DeletionStatus_name, int32(x))
+}
+
+func (DeleteFileResponse_DeletionStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_08edf9c909488729, []int{3, 0}
+}
+
+type CopyFileResponse_CopyStatus int32
+
+const (
+	CopyFileResponse_SUCCESS CopyFileResponse_CopyStatus = 0
+	CopyFileResponse_FAILURE CopyFileResponse_CopyStatus = 1
+)
+
+var CopyFileResponse_CopyStatus_name = map[int32]string{
+	0: "SUCCESS",
+	1: "FAILURE",
+}
+
+var CopyFileResponse_CopyStatus_value = map[string]int32{
+	"SUCCESS": 0,
+	"FAILURE": 1,
+}
+
+func (x CopyFileResponse_CopyStatus) String() string {
+	return proto.EnumName(CopyFileResponse_CopyStatus_name, int32(x))
+}
+
+func (CopyFileResponse_CopyStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_08edf9c909488729, []int{5, 0}
+}
+
 type HealthCheckRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -82,6 +132,15 @@ var xxx_messageInfo_HealthCheckRequest proto.InternalMessageInfo
 
 type HealthCheckResponse struct {
 	Status               HealthCheckResponse_NodeStatus `protobuf:"varint,1,opt,name=Status,json=status,proto3,enum=dnpb.HealthCheckResponse_NodeStatus" json:"Status,omitempty"`
+	FreeDisk             uint64                         `protobuf:"varint,2,opt,name=FreeDisk,json=freeDisk,proto3" json:"FreeDisk,omitempty"`
+	UsedDisk             uint64                         `protobuf:"varint,3,opt,name=UsedDisk,json=usedDisk,proto3" json:"UsedDisk,omitempty"`
+	TotalDisk            uint64                         `protobuf:"varint,4,opt,name=TotalDisk,json=totalDisk,proto3" json:"TotalDisk,omitempty"`
+	FileCount            int64                          `protobuf:"varint,5,opt,name=FileCount,json=fileCount,proto3" json:"FileCount,omitempty"`
+	RunningJobs          int32                          `protobuf:"varint,6,opt,name=RunningJobs,json=runningJobs,proto3" json:"RunningJobs,omitempty"`
+	QueuedJobs           int32                          `protobuf:"varint,7,opt,name=QueuedJobs,json=queuedJobs,proto3" json:"QueuedJobs,omitempty"`
+	InFlightUploads      int32                          `protobuf:"varint,8,opt,name=InFlightUploads,json=inFlightUploads,proto3" json:"InFlightUploads,omitempty"`
+	GPU                  bool                           `protobuf:"varint,9,opt,name=GPU,json=gPU,proto3" json:"GPU,omitempty"`
+	GPUAvailable         bool                           `protobuf:"varint,10,opt,name=GPUAvailable,json=gPUAvailable,proto3" json:"GPUAvailable,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                       `json:"-"`
 	XXX_unrecognized     []byte                         `json:"-"`
 	XXX_sizecache        int32                          `json:"-"`
@@ -119,10 +178,588 @@ func (m *HealthCheckResponse) GetStatus() HealthCheckResponse_NodeStatus {
 	return HealthCheckResponse_HEALTHY
 }
 
+func (m *HealthCheckResponse) GetFreeDisk() uint64 {
+	if m != nil {
+		return m.FreeDisk
+	}
+	return 0
+}
+
+func (m *HealthCheckResponse) GetUsedDisk() uint64 {
+	if m != nil {
+		return m.UsedDisk
+	}
+	return 0
+}
+
+func (m *HealthCheckResponse) GetTotalDisk() uint64 {
+	if m != nil {
+		return m.TotalDisk
+	}
+	return 0
+}
+
+func (m *HealthCheckResponse) GetFileCount() int64 {
+	if m != nil {
+		return m.FileCount
+	}
+	return 0
+}
+
+func (m *HealthCheckResponse) GetRunningJobs() int32 {
+	if m != nil {
+		return m.RunningJobs
+	}
+	return 0
+}
+
+func (m *HealthCheckResponse) GetQueuedJobs() int32 {
+	if m != nil {
+		return m.QueuedJobs
+	}
+	return 0
+}
+
+func (m *HealthCheckResponse) GetInFlightUploads() int32 {
+	if m != nil {
+		return m.InFlightUploads
+	}
+	return 0
+}
+
+func (m *HealthCheckResponse) GetGPU() bool {
+	if m != nil {
+		return m.GPU
+	}
+	return false
+}
+
+func (m *HealthCheckResponse) GetGPUAvailable() bool {
+	if m != nil {
+		return m.GPUAvailable
+	}
+	return false
+}
+
+type DeleteFileRequest struct {
+	Token                string   `protobuf:"bytes,1,opt,name=Token,json=token,proto3" json:"Token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DeleteFileRequest) Reset()         { *m = DeleteFileRequest{} }
+func (m *DeleteFileRequest) String() string { return proto.CompactTextString(m) }
+func (*DeleteFileRequest) ProtoMessage()    {}
+func (*DeleteFileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_08edf9c909488729, []int{2}
+}
+
+func (m *DeleteFileRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteFileRequest.Unmarshal(m, b)
+}
+func (m *DeleteFileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteFileRequest.Marshal(b, m, deterministic)
+}
+func (m *DeleteFileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteFileRequest.Merge(m, src)
+}
+func (m *DeleteFileRequest) XXX_Size() int {
+	return xxx_messageInfo_DeleteFileRequest.Size(m)
+}
+func (m *DeleteFileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteFileRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteFileRequest proto.InternalMessageInfo
+
+func (m *DeleteFileRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+type DeleteFileResponse struct {
+	Status               DeleteFileResponse_DeletionStatus `protobuf:"varint,1,opt,name=Status,json=status,proto3,enum=dnpb.DeleteFileResponse_DeletionStatus" json:"Status,omitempty"`
+	DeletedCount         int32                             `protobuf:"varint,2,opt,name=DeletedCount,json=deletedCount,proto3" json:"DeletedCount,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                          `json:"-"`
+	XXX_unrecognized     []byte                            `json:"-"`
+	XXX_sizecache        int32                             `json:"-"`
+}
+
+func (m *DeleteFileResponse) Reset()         { *m = DeleteFileResponse{} }
+func (m *DeleteFileResponse) String() string { return proto.CompactTextString(m) }
+func (*DeleteFileResponse) ProtoMessage()    {}
+func (*DeleteFileResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_08edf9c909488729, []int{3}
+}
+
+func (m *DeleteFileResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DeleteFileResponse.Unmarshal(m, b)
+}
+func (m *DeleteFileResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DeleteFileResponse.Marshal(b, m, deterministic)
+}
+func (m *DeleteFileResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DeleteFileResponse.Merge(m, src)
+}
+func (m *DeleteFileResponse) XXX_Size() int {
+	return xxx_messageInfo_DeleteFileResponse.Size(m)
+}
+func (m *DeleteFileResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_DeleteFileResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DeleteFileResponse proto.InternalMessageInfo
+
+func (m *DeleteFileResponse) GetStatus() DeleteFileResponse_DeletionStatus {
+	if m != nil {
+		return m.Status
+	}
+	return DeleteFileResponse_SUCCESS
+}
+
+func (m *DeleteFileResponse) GetDeletedCount() int32 {
+	if m != nil {
+		return m.DeletedCount
+	}
+	return 0
+}
+
+type CopyFileRequest struct {
+	Token                string   `protobuf:"bytes,1,opt,name=Token,json=token,proto3" json:"Token,omitempty"`
+	TargetAddress        string   `protobuf:"bytes,2,opt,name=TargetAddress,json=targetAddress,proto3" json:"TargetAddress,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CopyFileRequest) Reset()         { *m = CopyFileRequest{} }
+func (m *CopyFileRequest) String() string { return proto.CompactTextString(m) }
+func (*CopyFileRequest) ProtoMessage()    {}
+func (*CopyFileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_08edf9c909488729, []int{4}
+}
+
+func (m *CopyFileRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CopyFileRequest.Unmarshal(m, b)
+}
+func (m *CopyFileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CopyFileRequest.Marshal(b, m, deterministic)
+}
+func (m *CopyFileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CopyFileRequest.Merge(m, src)
+}
+func (m *CopyFileRequest) XXX_Size() int {
+	return xxx_messageInfo_CopyFileRequest.Size(m)
+}
+func (m *CopyFileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CopyFileRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CopyFileRequest proto.InternalMessageInfo
+
+func (m *CopyFileRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *CopyFileRequest) GetTargetAddress() string {
+	if m != nil {
+		return m.TargetAddress
+	}
+	return ""
+}
+
+type CopyFileResponse struct {
+	Status               CopyFileResponse_CopyStatus `protobuf:"varint,1,opt,name=Status,json=status,proto3,enum=dnpb.CopyFileResponse_CopyStatus" json:"Status,omitempty"`
+	NewToken             string                      `protobuf:"bytes,2,opt,name=NewToken,json=newToken,proto3" json:"NewToken,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                    `json:"-"`
+	XXX_unrecognized     []byte                      `json:"-"`
+	XXX_sizecache        int32                       `json:"-"`
+}
+
+func (m *CopyFileResponse) Reset()         { *m = CopyFileResponse{} }
+func (m *CopyFileResponse) String() string { return proto.CompactTextString(m) }
+func (*CopyFileResponse) ProtoMessage()    {}
+func (*CopyFileResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_08edf9c909488729, []int{5}
+}
+
+func (m *CopyFileResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CopyFileResponse.Unmarshal(m, b)
+}
+func (m *CopyFileResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CopyFileResponse.Marshal(b, m, deterministic)
+}
+func (m *CopyFileResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CopyFileResponse.Merge(m, src)
+}
+func (m *CopyFileResponse) XXX_Size() int {
+	return xxx_messageInfo_CopyFileResponse.Size(m)
+}
+func (m *CopyFileResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CopyFileResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CopyFileResponse proto.InternalMessageInfo
+
+func (m *CopyFileResponse) GetStatus() CopyFileResponse_CopyStatus {
+	if m != nil {
+		return m.Status
+	}
+	return CopyFileResponse_SUCCESS
+}
+
+func (m *CopyFileResponse) GetNewToken() string {
+	if m != nil {
+		return m.NewToken
+	}
+	return ""
+}
+
+type FileMetadata struct {
+	Parent               string   `protobuf:"bytes,1,opt,name=Parent,json=parent,proto3" json:"Parent,omitempty"`
+	ReplicaToken         string   `protobuf:"bytes,2,opt,name=ReplicaToken,json=replicaToken,proto3" json:"ReplicaToken,omitempty"`
+	Name                 string   `protobuf:"bytes,3,opt,name=Name,json=name,proto3" json:"Name,omitempty"`
+	Type                 string   `protobuf:"bytes,4,opt,name=Type,json=type,proto3" json:"Type,omitempty"`
+	Size                 int64    `protobuf:"varint,5,opt,name=Size,json=size,proto3" json:"Size,omitempty"`
+	Checksum             string   `protobuf:"bytes,6,opt,name=Checksum,json=checksum,proto3" json:"Checksum,omitempty"`
+	AssociatedModel      string   `protobuf:"bytes,7,opt,name=AssociatedModel,json=associatedModel,proto3" json:"AssociatedModel,omitempty"`
+	ModelSize            int64    `protobuf:"varint,8,opt,name=ModelSize,json=modelSize,proto3" json:"ModelSize,omitempty"`
+	ConfigSize           int64    `protobuf:"varint,9,opt,name=ConfigSize,json=configSize,proto3" json:"ConfigSize,omitempty"`
+	CodeSize             int64    `protobuf:"varint,10,opt,name=CodeSize,json=codeSize,proto3" json:"CodeSize,omitempty"`
+	Pipeline             []string `protobuf:"bytes,11,rep,name=Pipeline,json=pipeline,proto3" json:"Pipeline,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FileMetadata) Reset()         { *m = FileMetadata{} }
+func (m *FileMetadata) String() string { return proto.CompactTextString(m) }
+func (*FileMetadata) ProtoMessage()    {}
+func (*FileMetadata) Descriptor() ([]byte, []int) {
+	return fileDescriptor_08edf9c909488729, []int{6}
+}
+
+func (m *FileMetadata) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FileMetadata.Unmarshal(m, b)
+}
+func (m *FileMetadata) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FileMetadata.Marshal(b, m, deterministic)
+}
+func (m *FileMetadata) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FileMetadata.Merge(m, src)
+}
+func (m *FileMetadata) XXX_Size() int {
+	return xxx_messageInfo_FileMetadata.Size(m)
+}
+func (m *FileMetadata) XXX_DiscardUnknown() {
+	xxx_messageInfo_FileMetadata.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FileMetadata proto.InternalMessageInfo
+
+func (m *FileMetadata) GetParent() string {
+	if m != nil {
+		return m.Parent
+	}
+	return ""
+}
+
+func (m *FileMetadata) GetReplicaToken() string {
+	if m != nil {
+		return m.ReplicaToken
+	}
+	return ""
+}
+
+func (m *FileMetadata) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *FileMetadata) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *FileMetadata) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *FileMetadata) GetChecksum() string {
+	if m != nil {
+		return m.Checksum
+	}
+	return ""
+}
+
+func (m *FileMetadata) GetAssociatedModel() string {
+	if m != nil {
+		return m.AssociatedModel
+	}
+	return ""
+}
+
+func (m *FileMetadata) GetModelSize() int64 {
+	if m != nil {
+		return m.ModelSize
+	}
+	return 0
+}
+
+func (m *FileMetadata) GetConfigSize() int64 {
+	if m != nil {
+		return m.ConfigSize
+	}
+	return 0
+}
+
+func (m *FileMetadata) GetCodeSize() int64 {
+	if m != nil {
+		return m.CodeSize
+	}
+	return 0
+}
+
+func (m *FileMetadata) GetPipeline() []string {
+	if m != nil {
+		return m.Pipeline
+	}
+	return nil
+}
+
+type FileChunk struct {
+	Offset               int64    `protobuf:"varint,1,opt,name=Offset,json=offset,proto3" json:"Offset,omitempty"`
+	Data                 []byte   `protobuf:"bytes,2,opt,name=Data,json=data,proto3" json:"Data,omitempty"`
+	Checksum             []byte   `protobuf:"bytes,3,opt,name=Checksum,json=checksum,proto3" json:"Checksum,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FileChunk) Reset()         { *m = FileChunk{} }
+func (m *FileChunk) String() string { return proto.CompactTextString(m) }
+func (*FileChunk) ProtoMessage()    {}
+func (*FileChunk) Descriptor() ([]byte, []int) {
+	return fileDescriptor_08edf9c909488729, []int{7}
+}
+
+func (m *FileChunk) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FileChunk.Unmarshal(m, b)
+}
+func (m *FileChunk) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FileChunk.Marshal(b, m, deterministic)
+}
+func (m *FileChunk) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FileChunk.Merge(m, src)
+}
+func (m *FileChunk) XXX_Size() int {
+	return xxx_messageInfo_FileChunk.Size(m)
+}
+func (m *FileChunk) XXX_DiscardUnknown() {
+	xxx_messageInfo_FileChunk.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FileChunk proto.InternalMessageInfo
+
+func (m *FileChunk) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *FileChunk) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *FileChunk) GetChecksum() []byte {
+	if m != nil {
+		return m.Checksum
+	}
+	return nil
+}
+
+type ReplicateFileRequest struct {
+	// Types that are valid to be assigned to Payload:
+	//	*ReplicateFileRequest_Metadata
+	//	*ReplicateFileRequest_Chunk
+	Payload              isReplicateFileRequest_Payload `protobuf_oneof:"Payload"`
+	XXX_NoUnkeyedLiteral struct{}                       `json:"-"`
+	XXX_unrecognized     []byte                         `json:"-"`
+	XXX_sizecache        int32                          `json:"-"`
+}
+
+func (m *ReplicateFileRequest) Reset()         { *m = ReplicateFileRequest{} }
+func (m *ReplicateFileRequest) String() string { return proto.CompactTextString(m) }
+func (*ReplicateFileRequest) ProtoMessage()    {}
+func (*ReplicateFileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_08edf9c909488729, []int{8}
+}
+
+func (m *ReplicateFileRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplicateFileRequest.Unmarshal(m, b)
+}
+func (m *ReplicateFileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplicateFileRequest.Marshal(b, m, deterministic)
+}
+func (m *ReplicateFileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplicateFileRequest.Merge(m, src)
+}
+func (m *ReplicateFileRequest) XXX_Size() int {
+	return xxx_messageInfo_ReplicateFileRequest.Size(m)
+}
+func (m *ReplicateFileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplicateFileRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplicateFileRequest proto.InternalMessageInfo
+
+type isReplicateFileRequest_Payload interface {
+	isReplicateFileRequest_Payload()
+}
+
+type ReplicateFileRequest_Metadata struct {
+	Metadata *FileMetadata `protobuf:"bytes,1,opt,name=Metadata,json=metadata,proto3,oneof"`
+}
+
+type ReplicateFileRequest_Chunk struct {
+	Chunk *FileChunk `protobuf:"bytes,2,opt,name=Chunk,json=chunk,proto3,oneof"`
+}
+
+func (*ReplicateFileRequest_Metadata) isReplicateFileRequest_Payload() {}
+
+func (*ReplicateFileRequest_Chunk) isReplicateFileRequest_Payload() {}
+
+func (m *ReplicateFileRequest) GetPayload() isReplicateFileRequest_Payload {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *ReplicateFileRequest) GetMetadata() *FileMetadata {
+	if x, ok := m.GetPayload().(*ReplicateFileRequest_Metadata); ok {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (m *ReplicateFileRequest) GetChunk() *FileChunk {
+	if x, ok := m.GetPayload().(*ReplicateFileRequest_Chunk); ok {
+		return x.Chunk
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*ReplicateFileRequest) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*ReplicateFileRequest_Metadata)(nil),
+		(*ReplicateFileRequest_Chunk)(nil),
+	}
+}
+
+type ReplicateFileResponse struct {
+	Token                string   `protobuf:"bytes,1,opt,name=Token,json=token,proto3" json:"Token,omitempty"`
+	Completed            bool     `protobuf:"varint,2,opt,name=Completed,json=completed,proto3" json:"Completed,omitempty"`
+	Checksum             string   `protobuf:"bytes,3,opt,name=Checksum,json=checksum,proto3" json:"Checksum,omitempty"`
+	ReceivedRanges       string   `protobuf:"bytes,4,opt,name=ReceivedRanges,json=receivedRanges,proto3" json:"ReceivedRanges,omitempty"`
+	DurableReplicas      int32    `protobuf:"varint,5,opt,name=DurableReplicas,json=durableReplicas,proto3" json:"DurableReplicas,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReplicateFileResponse) Reset()         { *m = ReplicateFileResponse{} }
+func (m *ReplicateFileResponse) String() string { return proto.CompactTextString(m) }
+func (*ReplicateFileResponse) ProtoMessage()    {}
+func (*ReplicateFileResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_08edf9c909488729, []int{9}
+}
+
+func (m *ReplicateFileResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplicateFileResponse.Unmarshal(m, b)
+}
+func (m *ReplicateFileResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplicateFileResponse.Marshal(b, m, deterministic)
+}
+func (m *ReplicateFileResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplicateFileResponse.Merge(m, src)
+}
+func (m *ReplicateFileResponse) XXX_Size() int {
+	return xxx_messageInfo_ReplicateFileResponse.Size(m)
+}
+func (m *ReplicateFileResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplicateFileResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplicateFileResponse proto.InternalMessageInfo
+
+func (m *ReplicateFileResponse) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *ReplicateFileResponse) GetCompleted() bool {
+	if m != nil {
+		return m.Completed
+	}
+	return false
+}
+
+func (m *ReplicateFileResponse) GetChecksum() string {
+	if m != nil {
+		return m.Checksum
+	}
+	return ""
+}
+
+func (m *ReplicateFileResponse) GetReceivedRanges() string {
+	if m != nil {
+		return m.ReceivedRanges
+	}
+	return ""
+}
+
+func (m *ReplicateFileResponse) GetDurableReplicas() int32 {
+	if m != nil {
+		return m.DurableReplicas
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("dnpb.HealthCheckResponse_NodeStatus", HealthCheckResponse_NodeStatus_name, HealthCheckResponse_NodeStatus_value)
+	proto.RegisterEnum("dnpb.DeleteFileResponse_DeletionStatus", DeleteFileResponse_DeletionStatus_name, DeleteFileResponse_DeletionStatus_value)
+	proto.RegisterEnum("dnpb.CopyFileResponse_CopyStatus", CopyFileResponse_CopyStatus_name, CopyFileResponse_CopyStatus_value)
 	proto.RegisterType((*HealthCheckRequest)(nil), "dnpb.HealthCheckRequest")
 	proto.RegisterType((*HealthCheckResponse)(nil), "dnpb.HealthCheckResponse")
+	proto.RegisterType((*DeleteFileRequest)(nil), "dnpb.DeleteFileRequest")
+	proto.RegisterType((*DeleteFileResponse)(nil), "dnpb.DeleteFileResponse")
+	proto.RegisterType((*CopyFileRequest)(nil), "dnpb.CopyFileRequest")
+	proto.RegisterType((*CopyFileResponse)(nil), "dnpb.CopyFileResponse")
+	proto.RegisterType((*FileMetadata)(nil), "dnpb.FileMetadata")
+	proto.RegisterType((*FileChunk)(nil), "dnpb.FileChunk")
+	proto.RegisterType((*ReplicateFileRequest)(nil), "dnpb.ReplicateFileRequest")
+	proto.RegisterType((*ReplicateFileResponse)(nil), "dnpb.ReplicateFileResponse")
 }
 
 func init() {
@@ -130,19 +767,63 @@ func init() {
 }
 
 var fileDescriptor_08edf9c909488729 = []byte{
-	// 189 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x4c, 0xc9, 0x2b, 0x48,
-	0x8a, 0x2f, 0xca, 0x2f, 0x2d, 0x49, 0x2d, 0xd6, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x01,
-	0x09, 0x29, 0x89, 0x70, 0x09, 0x79, 0xa4, 0x26, 0xe6, 0x94, 0x64, 0x38, 0x67, 0xa4, 0x26, 0x67,
-	0x07, 0xa5, 0x16, 0x96, 0xa6, 0x16, 0x97, 0x28, 0xd5, 0x72, 0x09, 0xa3, 0x88, 0x16, 0x17, 0xe4,
-	0xe7, 0x15, 0xa7, 0x0a, 0xd9, 0x70, 0xb1, 0x05, 0x97, 0x24, 0x96, 0x94, 0x16, 0x4b, 0x30, 0x2a,
-	0x30, 0x6a, 0xf0, 0x19, 0xa9, 0xe8, 0x81, 0xcc, 0xd0, 0xc3, 0xa2, 0x54, 0xcf, 0x2f, 0x3f, 0x25,
-	0x15, 0xa2, 0x36, 0x88, 0xad, 0x18, 0x4c, 0x2b, 0x69, 0x70, 0x71, 0x21, 0x44, 0x85, 0xb8, 0xb9,
-	0xd8, 0x3d, 0x5c, 0x1d, 0x7d, 0x42, 0x3c, 0x22, 0x05, 0x18, 0x84, 0x78, 0xb9, 0x38, 0x43, 0xfd,
-	0x60, 0x5c, 0x46, 0xa3, 0x18, 0x2e, 0x31, 0x97, 0xc4, 0x92, 0x44, 0x90, 0x6a, 0xcf, 0xbc, 0x92,
-	0xd4, 0xa2, 0xbc, 0xc4, 0x9c, 0x20, 0xb0, 0xd3, 0x85, 0x9c, 0xb8, 0xb8, 0x91, 0x6c, 0x13, 0x92,
-	0xc0, 0xe2, 0x00, 0xb0, 0x0f, 0xa4, 0x24, 0x71, 0x3a, 0x2d, 0x89, 0x0d, 0xec, 0x7f, 0x63, 0x40,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0x71, 0x61, 0x46, 0x84, 0x14, 0x01, 0x00, 0x00,
+	// 889 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x55, 0xc1, 0x6e, 0xdb, 0x46,
+	0x10, 0x35, 0x4d, 0x49, 0x26, 0x47, 0xb2, 0xe5, 0x6c, 0x1d, 0x97, 0x75, 0x83, 0x42, 0x25, 0x82,
+	0x44, 0xed, 0xc1, 0x28, 0xdc, 0x53, 0x80, 0x02, 0x81, 0x22, 0xdb, 0x91, 0x83, 0xd8, 0x55, 0x57,
+	0xd2, 0xa1, 0xa7, 0x62, 0x4d, 0x8e, 0x64, 0xc2, 0xd4, 0x92, 0xe1, 0x2e, 0x53, 0xd8, 0xe8, 0x4f,
+	0x14, 0xfd, 0x85, 0xf6, 0x23, 0x7a, 0xed, 0x97, 0x15, 0x3b, 0xa4, 0x2c, 0x4a, 0x56, 0xda, 0x93,
+	0x34, 0x6f, 0x86, 0x3b, 0xfb, 0x66, 0xe6, 0xcd, 0xc2, 0x93, 0x50, 0xa6, 0xd7, 0xbf, 0x64, 0x49,
+	0xae, 0x51, 0x1d, 0xa7, 0x59, 0xa2, 0x13, 0x56, 0x33, 0x90, 0x7f, 0x00, 0x6c, 0x80, 0x22, 0xd6,
+	0x37, 0xfd, 0x1b, 0x0c, 0x6e, 0x39, 0x7e, 0xc8, 0x51, 0x69, 0xff, 0x0f, 0x1b, 0x3e, 0x5b, 0x81,
+	0x55, 0x9a, 0x48, 0x85, 0xec, 0x07, 0x68, 0x8c, 0xb4, 0xd0, 0xb9, 0xf2, 0xac, 0x8e, 0xd5, 0xdd,
+	0x3b, 0x79, 0x7e, 0x6c, 0x0e, 0x39, 0xde, 0x10, 0x7a, 0x7c, 0x95, 0x84, 0x58, 0xc4, 0xf2, 0x86,
+	0xa2, 0x5f, 0x76, 0x04, 0xce, 0x79, 0x86, 0x78, 0x1a, 0xa9, 0x5b, 0x6f, 0xbb, 0x63, 0x75, 0x6b,
+	0xdc, 0x99, 0x96, 0xb6, 0xf1, 0x4d, 0x14, 0x86, 0xe4, 0xb3, 0x0b, 0x5f, 0x5e, 0xda, 0xec, 0x19,
+	0xb8, 0xe3, 0x44, 0x8b, 0x98, 0x9c, 0x35, 0x72, 0xba, 0x7a, 0x01, 0x18, 0xef, 0x79, 0x14, 0x63,
+	0x3f, 0xc9, 0xa5, 0xf6, 0xea, 0x1d, 0xab, 0x6b, 0x73, 0x77, 0xba, 0x00, 0x58, 0x07, 0x9a, 0x3c,
+	0x97, 0x32, 0x92, 0xb3, 0x77, 0xc9, 0xb5, 0xf2, 0x1a, 0x1d, 0xab, 0x5b, 0xe7, 0xcd, 0x6c, 0x09,
+	0xb1, 0xaf, 0x00, 0x7e, 0xca, 0x31, 0xc7, 0x90, 0x02, 0x76, 0x28, 0x00, 0x3e, 0x3c, 0x20, 0xac,
+	0x0b, 0xed, 0x0b, 0x79, 0x1e, 0x47, 0xb3, 0x1b, 0x3d, 0x49, 0xe3, 0x44, 0x84, 0xca, 0x73, 0x28,
+	0xa8, 0x1d, 0xad, 0xc2, 0x6c, 0x1f, 0xec, 0xb7, 0xc3, 0x89, 0xe7, 0x76, 0xac, 0xae, 0xc3, 0xed,
+	0xd9, 0x70, 0xc2, 0x7c, 0x68, 0xbd, 0x1d, 0x4e, 0x7a, 0x1f, 0x45, 0x14, 0x8b, 0xeb, 0x18, 0x3d,
+	0x20, 0x57, 0x6b, 0x56, 0xc1, 0xfc, 0x2e, 0xc0, 0xb2, 0x56, 0xac, 0x09, 0x3b, 0x83, 0xb3, 0xde,
+	0xfb, 0xf1, 0xe0, 0xe7, 0xfd, 0x2d, 0xb6, 0x0b, 0xee, 0xe4, 0x6a, 0x61, 0x5a, 0xfe, 0x37, 0xf0,
+	0xe4, 0x14, 0x63, 0xd4, 0x68, 0xf8, 0x96, 0xad, 0x62, 0x07, 0x50, 0x1f, 0x27, 0xb7, 0x28, 0xa9,
+	0x23, 0x2e, 0xaf, 0x6b, 0x63, 0xf8, 0x7f, 0x59, 0xc0, 0xaa, 0xb1, 0x65, 0xff, 0x5e, 0xaf, 0xf5,
+	0xef, 0x65, 0xd1, 0xbf, 0xc7, 0x91, 0x05, 0x14, 0x25, 0x72, 0xad, 0x85, 0x3e, 0xb4, 0x8a, 0xe0,
+	0xb0, 0xa8, 0xf7, 0x36, 0x55, 0xa2, 0x15, 0x56, 0x30, 0xff, 0x5b, 0xd8, 0x5b, 0xfd, 0xda, 0x90,
+	0x1a, 0x4d, 0xfa, 0xfd, 0xb3, 0xd1, 0x68, 0x7f, 0xcb, 0x18, 0xe7, 0xbd, 0x8b, 0xf7, 0x13, 0x7e,
+	0xb6, 0x6f, 0xf9, 0x97, 0xd0, 0xee, 0x27, 0xe9, 0xdd, 0xff, 0x12, 0x62, 0xcf, 0x61, 0x77, 0x2c,
+	0xb2, 0x19, 0xea, 0x5e, 0x18, 0x66, 0xa8, 0x14, 0x65, 0x76, 0xf9, 0xae, 0xae, 0x82, 0xfe, 0xef,
+	0x16, 0xec, 0x2f, 0xcf, 0x2b, 0x49, 0xbf, 0x5a, 0x23, 0xfd, 0x75, 0x41, 0x7a, 0x3d, 0x8e, 0x80,
+	0xc7, 0x13, 0x7b, 0x85, 0xbf, 0x16, 0xd7, 0x29, 0x12, 0x3a, 0xb2, 0xb4, 0xfd, 0x17, 0x00, 0xcb,
+	0x2f, 0xfe, 0x83, 0xe2, 0x3f, 0xdb, 0xd0, 0x32, 0x79, 0x2e, 0x51, 0x8b, 0x50, 0x68, 0xc1, 0x0e,
+	0xa1, 0x31, 0x14, 0x19, 0x4a, 0x5d, 0x32, 0x6c, 0xa4, 0x64, 0x99, 0xda, 0x72, 0x4c, 0xe3, 0x28,
+	0x10, 0xd5, 0x84, 0xad, 0xac, 0x82, 0x31, 0x06, 0xb5, 0x2b, 0x31, 0x47, 0x92, 0x88, 0xcb, 0x6b,
+	0x52, 0xcc, 0xd1, 0x60, 0xe3, 0xbb, 0x14, 0x49, 0x19, 0x2e, 0xaf, 0xe9, 0xbb, 0x94, 0xb0, 0x51,
+	0x74, 0x8f, 0xa5, 0x1e, 0x6a, 0x2a, 0xba, 0x47, 0x43, 0x86, 0x24, 0xaa, 0xf2, 0x39, 0xe9, 0xc0,
+	0xe5, 0x4e, 0x50, 0xda, 0x66, 0xc8, 0x7b, 0x4a, 0x25, 0x41, 0x24, 0x34, 0x86, 0x97, 0x49, 0x88,
+	0x31, 0x29, 0xc1, 0xe5, 0x6d, 0xb1, 0x0a, 0x1b, 0xb9, 0xd1, 0x1f, 0x3a, 0xde, 0x29, 0xe4, 0x36,
+	0x5f, 0x00, 0x46, 0x4c, 0xfd, 0x44, 0x4e, 0xa3, 0x19, 0xb9, 0x5d, 0x72, 0x43, 0xf0, 0x80, 0xd0,
+	0x1d, 0xcc, 0xb0, 0x47, 0xf7, 0x85, 0x18, 0x6c, 0xee, 0x04, 0xa5, 0x6d, 0x7c, 0xc3, 0x28, 0xc5,
+	0x38, 0x92, 0xe8, 0x35, 0x3b, 0xb6, 0xb9, 0x5f, 0x5a, 0xda, 0xfe, 0xa8, 0x14, 0xf9, 0x4d, 0x2e,
+	0x6f, 0x4d, 0x01, 0x7f, 0x9c, 0x4e, 0x15, 0x16, 0x05, 0xb4, 0x79, 0x23, 0x21, 0xcb, 0x90, 0x3e,
+	0x15, 0x5a, 0x50, 0xe1, 0x5a, 0xbc, 0x46, 0xc5, 0xae, 0x92, 0xb6, 0x09, 0x7f, 0x20, 0xed, 0xff,
+	0x06, 0x07, 0x65, 0xc1, 0x57, 0x25, 0xf5, 0x1d, 0x38, 0x8b, 0x66, 0x51, 0x86, 0xe6, 0x09, 0x2b,
+	0x46, 0xa6, 0xda, 0xc6, 0xc1, 0x16, 0x77, 0xe6, 0xe5, 0x7f, 0xf6, 0x12, 0xea, 0x74, 0x35, 0x4a,
+	0xdd, 0x3c, 0x69, 0x2f, 0xc3, 0x09, 0x1e, 0x6c, 0xf1, 0x7a, 0x60, 0xfe, 0xbc, 0x71, 0x61, 0x67,
+	0x28, 0xee, 0xcc, 0xba, 0xf0, 0xff, 0xb6, 0xe0, 0xe9, 0x5a, 0xfa, 0x72, 0x60, 0x37, 0x2b, 0xe0,
+	0x19, 0xb8, 0xfd, 0x64, 0x9e, 0x92, 0xd0, 0x28, 0x8f, 0xc3, 0xdd, 0x60, 0x01, 0x3c, 0xe2, 0x59,
+	0x6d, 0xee, 0x0b, 0xd8, 0xe3, 0x18, 0x60, 0xf4, 0x11, 0x43, 0x2e, 0xe4, 0x0c, 0x55, 0x39, 0x2a,
+	0x7b, 0xd9, 0x0a, 0x6a, 0x86, 0xe0, 0x34, 0xcf, 0xcc, 0x52, 0x2a, 0xef, 0xa5, 0x68, 0x7e, 0xea,
+	0xbc, 0x1d, 0xae, 0xc2, 0x27, 0x7f, 0x6e, 0xc3, 0xa1, 0x29, 0xb5, 0x59, 0x5c, 0x17, 0x52, 0x63,
+	0x26, 0x45, 0xcc, 0xe9, 0x71, 0x61, 0x6f, 0xa0, 0x59, 0x79, 0x0e, 0x98, 0xb7, 0xe1, 0x85, 0xa0,
+	0x2a, 0x1f, 0x7d, 0xf1, 0xc9, 0xb7, 0x83, 0xbd, 0x06, 0x58, 0xae, 0x24, 0xf6, 0xf9, 0xe3, 0x25,
+	0x55, 0x9c, 0xe0, 0x7d, 0x6a, 0x7b, 0xb1, 0x57, 0xe0, 0x2c, 0xe4, 0xcd, 0x9e, 0xae, 0xcb, 0xbd,
+	0xf8, 0xf8, 0x70, 0xf3, 0x16, 0x60, 0xef, 0x60, 0x77, 0xa5, 0x2b, 0xec, 0xa8, 0x08, 0xdc, 0x34,
+	0x29, 0x47, 0x5f, 0x6e, 0xf4, 0x15, 0x27, 0x75, 0xad, 0xeb, 0x06, 0xbd, 0xb4, 0xdf, 0xff, 0x3b,
+	0x00, 0x1d, 0xc9, 0xe5, 0x09, 0x7e, 0x07, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -158,6 +839,9 @@ const _ = grpc.SupportPackageIsVersion6
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type DataNodeInternalRoutesClient interface {
 	HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error)
+	DeleteFile(ctx context.Context, in *DeleteFileRequest, opts ...grpc.CallOption) (*DeleteFileResponse, error)
+	CopyFile(ctx context.Context, in *CopyFileRequest, opts ...grpc.CallOption) (*CopyFileResponse, error)
+	ReplicateFile(ctx context.Context, opts ...grpc.CallOption) (DataNodeInternalRoutes_ReplicateFileClient, error)
 }
 
 type dataNodeInternalRoutesClient struct {
@@ -177,9 +861,64 @@ func (c *dataNodeInternalRoutesClient) HealthCheck(ctx context.Context, in *Heal
 	return out, nil
 }
 
+func (c *dataNodeInternalRoutesClient) DeleteFile(ctx context.Context, in *DeleteFileRequest, opts ...grpc.CallOption) (*DeleteFileResponse, error) {
+	out := new(DeleteFileResponse)
+	err := c.cc.Invoke(ctx, "/dnpb.DataNodeInternalRoutes/DeleteFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataNodeInternalRoutesClient) CopyFile(ctx context.Context, in *CopyFileRequest, opts ...grpc.CallOption) (*CopyFileResponse, error) {
+	out := new(CopyFileResponse)
+	err := c.cc.Invoke(ctx, "/dnpb.DataNodeInternalRoutes/CopyFile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataNodeInternalRoutesClient) ReplicateFile(ctx context.Context, opts ...grpc.CallOption) (DataNodeInternalRoutes_ReplicateFileClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_DataNodeInternalRoutes_serviceDesc.Streams[0], "/dnpb.DataNodeInternalRoutes/ReplicateFile", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &dataNodeInternalRoutesReplicateFileClient{stream}
+	return x, nil
+}
+
+type DataNodeInternalRoutes_ReplicateFileClient interface {
+	Send(*ReplicateFileRequest) error
+	CloseAndRecv() (*ReplicateFileResponse, error)
+	grpc.ClientStream
+}
+
+type dataNodeInternalRoutesReplicateFileClient struct {
+	grpc.ClientStream
+}
+
+func (x *dataNodeInternalRoutesReplicateFileClient) Send(m *ReplicateFileRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *dataNodeInternalRoutesReplicateFileClient) CloseAndRecv() (*ReplicateFileResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ReplicateFileResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // DataNodeInternalRoutesServer is the server API for DataNodeInternalRoutes service.
 type DataNodeInternalRoutesServer interface {
 	HealthCheck(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error)
+	DeleteFile(context.Context, *DeleteFileRequest) (*DeleteFileResponse, error)
+	CopyFile(context.Context, *CopyFileRequest) (*CopyFileResponse, error)
+	ReplicateFile(DataNodeInternalRoutes_ReplicateFileServer) error
 }
 
 // UnimplementedDataNodeInternalRoutesServer can be embedded to have forward compatible implementations.
@@ -189,6 +928,15 @@ type UnimplementedDataNodeInternalRoutesServer struct {
 func (*UnimplementedDataNodeInternalRoutesServer) HealthCheck(ctx context.Context, req *HealthCheckRequest) (*HealthCheckResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method HealthCheck not implemented")
 }
+func (*UnimplementedDataNodeInternalRoutesServer) DeleteFile(ctx context.Context, req *DeleteFileRequest) (*DeleteFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteFile not implemented")
+}
+func (*UnimplementedDataNodeInternalRoutesServer) CopyFile(ctx context.Context, req *CopyFileRequest) (*CopyFileResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CopyFile not implemented")
+}
+func (*UnimplementedDataNodeInternalRoutesServer) ReplicateFile(srv DataNodeInternalRoutes_ReplicateFileServer) error {
+	return status.Errorf(codes.Unimplemented, "method ReplicateFile not implemented")
+}
 
 func RegisterDataNodeInternalRoutesServer(s *grpc.Server, srv DataNodeInternalRoutesServer) {
 	s.RegisterService(&_DataNodeInternalRoutes_serviceDesc, srv)
@@ -212,6 +960,68 @@ func _DataNodeInternalRoutes_HealthCheck_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _DataNodeInternalRoutes_DeleteFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataNodeInternalRoutesServer).DeleteFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dnpb.DataNodeInternalRoutes/DeleteFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataNodeInternalRoutesServer).DeleteFile(ctx, req.(*DeleteFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataNodeInternalRoutes_CopyFile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CopyFileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataNodeInternalRoutesServer).CopyFile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dnpb.DataNodeInternalRoutes/CopyFile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataNodeInternalRoutesServer).CopyFile(ctx, req.(*CopyFileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DataNodeInternalRoutes_ReplicateFile_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DataNodeInternalRoutesServer).ReplicateFile(&dataNodeInternalRoutesReplicateFileServer{stream})
+}
+
+type DataNodeInternalRoutes_ReplicateFileServer interface {
+	SendAndClose(*ReplicateFileResponse) error
+	Recv() (*ReplicateFileRequest, error)
+	grpc.ServerStream
+}
+
+type dataNodeInternalRoutesReplicateFileServer struct {
+	grpc.ServerStream
+}
+
+func (x *dataNodeInternalRoutesReplicateFileServer) SendAndClose(m *ReplicateFileResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *dataNodeInternalRoutesReplicateFileServer) Recv() (*ReplicateFileRequest, error) {
+	m := new(ReplicateFileRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _DataNodeInternalRoutes_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "dnpb.DataNodeInternalRoutes",
 	HandlerType: (*DataNodeInternalRoutesServer)(nil),
@@ -220,7 +1030,21 @@ var _DataNodeInternalRoutes_serviceDesc = grpc.ServiceDesc{
 			MethodName: "HealthCheck",
 			Handler:    _DataNodeInternalRoutes_HealthCheck_Handler,
 		},
+		{
+			MethodName: "DeleteFile",
+			Handler:    _DataNodeInternalRoutes_DeleteFile_Handler,
+		},
+		{
+			MethodName: "CopyFile",
+			Handler:    _DataNodeInternalRoutes_CopyFile_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ReplicateFile",
+			Handler:       _DataNodeInternalRoutes_ReplicateFile_Handler,
+			ClientStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "dnpb_routes.proto",
 }
@@ -4,12 +4,24 @@ import (
 	datanode "github.com/SayedAlesawy/Videra-Storage/data_node"
 	"github.com/SayedAlesawy/Videra-Storage/data_node/controllers/inner"
 	"github.com/SayedAlesawy/Videra-Storage/data_node/controllers/outer"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/janitor"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/membership"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/replication"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/scrubber"
 )
 
 func main() {
 	dataNode := datanode.NodeInstance()
 	dataNode.JoinCluster()
 
+	go membership.Instance().Start()
+
+	go janitor.Instance().Start()
+
+	go replication.WorkerInstance().Start()
+
+	go scrubber.Instance().Start()
+
 	go inner.ServerInstance().Start()
 
 	outer.ServerInstance().Start()
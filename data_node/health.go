@@ -2,51 +2,113 @@ package datanode
 
 import (
 	"context"
-	"fmt"
 	"log"
 	"time"
 
+	"github.com/SayedAlesawy/Videra-Storage/data_node/storage"
 	"github.com/SayedAlesawy/Videra-Storage/name_node/nnpb"
 	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
 	"google.golang.org/grpc"
 )
 
-// JoinCluster A function to notify the name node to join the cluster
+// JoinCluster A function to notify the name node to join the cluster along with a block report of the data node,
+// an unreachable name node or a follower is failed over to the leader or the next known name node, and the request is retried
 func (dataNode *DataNode) JoinCluster() {
 	for range time.Tick(dataNode.RejoinClusterInterval) {
-		conn, err := grpc.Dial(dataNode.getNameNodeAddress(), grpc.WithBlock(), grpc.WithInsecure())
-		errors.HandleError(err, fmt.Sprintf("%s Unable to connect to name node", logPrefix), true)
-		defer conn.Close()
-
-		client := nnpb.NewNameNodeInternalRoutesClient(conn)
-
-		log.Println(logPrefix, "Sending join cluster request to name node")
-		req := nnpb.JoinClusterRequest{
-			ID:           dataNode.ID,
-			IP:           dataNode.IP,
-			Port:         dataNode.Port,
-			InternalPort: dataNode.InternalPort,
-			GPU:          dataNode.GPU,
-		}
-
-		ctx, cancel := context.WithTimeout(context.Background(), dataNode.InternalReqTimeout)
-		defer cancel()
-
-		joinStatus, err := client.JoinCluster(ctx, &req)
+		joinStatus, err := dataNode.sendJoinCluster()
 		if errors.IsError(err) {
-			log.Println(logPrefix, "Unable to join cluster")
-			conn.Close()
+			log.Println(logPrefix, "Unable to join cluster", err)
+			dataNode.failOverNameNode("")
 
 			continue
 		}
 
-		if joinStatus.Status == nnpb.JoinClusterResponse_SUCCESS {
+		switch joinStatus.Status {
+		case nnpb.JoinClusterResponse_SUCCESS:
 			log.Println(logPrefix, "Successfully joined cluster")
 
 			return
+		case nnpb.JoinClusterResponse_NOT_LEADER:
+			log.Println(logPrefix, "Join cluster request reached a follower name node")
+			dataNode.failOverNameNode(joinStatus.LeaderAddress)
+		default:
+			log.Println(logPrefix, "Unable to join cluster")
 		}
+	}
+}
+
+// sendJoinCluster A function to send the join cluster request to the current name node
+func (dataNode *DataNode) sendJoinCluster() (*nnpb.JoinClusterResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dataNode.InternalReqTimeout)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, dataNode.getNameNodeAddress(), grpc.WithBlock(), grpc.WithInsecure())
+	if errors.IsError(err) {
+		return nil, err
+	}
+	defer conn.Close()
+
+	client := nnpb.NewNameNodeInternalRoutesClient(conn)
+
+	freeDisk, totalDisk, err := DiskUsage(storage.FilesFolder())
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to get disk usage", err)
+	}
+
+	inventory, err := dataNode.getBlockReport()
+	if errors.IsError(err) {
+		return nil, err
+	}
 
-		log.Println(logPrefix, "Unable to join cluster")
-		conn.Close()
+	log.Println(logPrefix, "Sending join cluster request to name node")
+	req := nnpb.JoinClusterRequest{
+		ID:           dataNode.ID,
+		IP:           dataNode.IP,
+		Port:         dataNode.Port,
+		InternalPort: dataNode.InternalPort,
+		GPU:          dataNode.GPU,
+		Zone:         dataNode.Zone,
+		Rack:         dataNode.Rack,
+		DiskClass:    dataNode.DiskClass,
+		FreeDisk:     freeDisk,
+		TotalDisk:    totalDisk,
+		Inventory:    inventory,
 	}
+
+	return client.JoinCluster(ctx, &req)
+}
+
+// Heartbeat A function to report the liveness and load of the data node to the name node, an unreachable name node
+// or a follower is failed over to the leader or the next known name node, the returned status tells
+// if the data node has to join the cluster again
+func (dataNode *DataNode) Heartbeat(req *nnpb.HeartbeatRequest) (nnpb.HeartbeatResponse_HeartbeatStatus, error) {
+	req.DataNodeID = dataNode.ID
+
+	res, err := dataNode.sendHeartbeat(req)
+	if errors.IsError(err) {
+		dataNode.failOverNameNode("")
+		return nnpb.HeartbeatResponse_NOT_LEADER, err
+	}
+
+	if res.Status == nnpb.HeartbeatResponse_NOT_LEADER {
+		dataNode.failOverNameNode(res.LeaderAddress)
+	}
+
+	return res.Status, nil
+}
+
+// sendHeartbeat A function to send the heartbeat to the current name node
+func (dataNode *DataNode) sendHeartbeat(req *nnpb.HeartbeatRequest) (*nnpb.HeartbeatResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), dataNode.InternalReqTimeout)
+	defer cancel()
+
+	conn, err := grpc.DialContext(ctx, dataNode.getNameNodeAddress(), grpc.WithBlock(), grpc.WithInsecure())
+	if errors.IsError(err) {
+		return nil, err
+	}
+	defer conn.Close()
+
+	client := nnpb.NewNameNodeInternalRoutesClient(conn)
+
+	return client.Heartbeat(ctx, req)
 }
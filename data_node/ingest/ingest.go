@@ -18,13 +18,14 @@ func StartJob(videoInfo datanode.File) {
 	var metadata datanode.VideoMetadata
 	json.Unmarshal([]byte(videoInfo.Extras), &metadata)
 
+	// the associated model is the original model token, the local copy may be a replica with its own token
 	var modelInfo datanode.File
-	datanode.NodeInstance().DB.Connection.Where("token = ?", metadata.AssociatedModel).Find(&modelInfo)
+	datanode.NodeInstance().DB.Connection.Where("parent = ? AND data_node_id = ?", metadata.AssociatedModel, datanode.NodeInstance().ID).Find(&modelInfo)
 
 	var modelExtras datanode.ModelExtras
 	json.Unmarshal([]byte(modelInfo.Extras), &modelExtras)
 
-	executeJob(videoInfo.Path, videoInfo.Token, modelInfo.Path, modelExtras.AssociatedConfigPath, modelExtras.AssociatedCodePath, modelInfo.Token, 0, metadata.FramesCount)
+	executeJob(videoInfo.Path, videoInfo.Token, modelInfo.Path, modelExtras.AssociatedConfigPath, modelExtras.AssociatedCodePath, modelInfo.Parent, 0, metadata.FramesCount)
 }
 
 // executeJob starts command for starting ingestion
@@ -0,0 +1,185 @@
+package janitor
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/SayedAlesawy/Videra-Storage/config"
+	datanode "github.com/SayedAlesawy/Videra-Storage/data_node"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/replication"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/storage"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/upload"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+// logPrefix Used for hierarchical logging
+var logPrefix = "[Janitor]"
+
+// janitorOnce Used to garauntee thread safety for singleton instances
+var janitorOnce sync.Once
+
+// janitorInstance A singleton instance of the janitor object
+var janitorInstance *Janitor
+
+// Janitor Collects abandoned uploads and orphaned files on the data node
+type Janitor struct {
+	uploadTTL time.Duration //Time after which an incomplete upload is considered abandoned
+	interval  time.Duration //Frequency of the collection
+}
+
+// Instance A function to return a singleton janitor instance
+func Instance() *Janitor {
+	dataNodeConfig := config.ConfigurationManagerInstance("").DataNodeConfig()
+
+	janitorOnce.Do(func() {
+		janitor := Janitor{
+			uploadTTL: time.Duration(dataNodeConfig.UploadTTL) * time.Second,
+			interval:  time.Duration(dataNodeConfig.JanitorInterval) * time.Second,
+		}
+
+		janitorInstance = &janitor
+	})
+
+	return janitorInstance
+}
+
+// Start A function to periodically collect abandoned uploads and orphaned files
+func (janitor *Janitor) Start() {
+	for range time.Tick(janitor.interval) {
+		janitor.collectAbandonedUploads()
+		janitor.collectOrphanedRecords()
+		janitor.collectOrphanedFolders()
+		janitor.collectOrphanedReplicaEntries()
+	}
+}
+
+// collectAbandonedUploads A function to remove incomplete uploads that weren't updated within the upload TTL,
+// along with their counterparts on the replica nodes
+func (janitor *Janitor) collectAbandonedUploads() {
+	dataNode := datanode.NodeInstance()
+	deadline := time.Now().Add(-janitor.uploadTTL)
+
+	var files []datanode.File
+	err := dataNode.DB.Connection.Where("data_node_id = ? AND completed_at IS NULL AND updated_at < ?", dataNode.ID, deadline).Find(&files).Error
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to fetch abandoned uploads", err)
+		return
+	}
+
+	for _, file := range files {
+		log.Println(logPrefix, fmt.Sprintf("Removing abandoned upload %s, last updated at %v", file.Token, file.UpdatedAt))
+
+		err := RemoveUpload(file)
+		if errors.IsError(err) {
+			log.Println(logPrefix, fmt.Sprintf("Unable to remove abandoned upload %s", file.Token), err)
+		}
+	}
+}
+
+// collectOrphanedRecords A function to remove file records whose files no longer exist on disk
+func (janitor *Janitor) collectOrphanedRecords() {
+	dataNode := datanode.NodeInstance()
+
+	var files []datanode.File
+	err := dataNode.DB.Connection.Where("data_node_id = ?", dataNode.ID).Find(&files).Error
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to fetch file records", err)
+		return
+	}
+
+	for _, file := range files {
+		_, err := os.Stat(file.Path)
+		if !os.IsNotExist(err) {
+			continue
+		}
+
+		log.Println(logPrefix, fmt.Sprintf("Removing record of file %s, its file %s is not found on disk", file.Token, file.Path))
+
+		err = dataNode.DB.Connection.Unscoped().Delete(&file).Error
+		if errors.IsError(err) {
+			log.Println(logPrefix, fmt.Sprintf("Unable to remove record of file %s", file.Token), err)
+		}
+	}
+}
+
+// collectOrphanedFolders A function to remove file folders on disk that have no file record,
+// folders younger than the upload TTL are skipped since their record may be still being created
+func (janitor *Janitor) collectOrphanedFolders() {
+	dataNode := datanode.NodeInstance()
+	deadline := time.Now().Add(-janitor.uploadTTL)
+
+	filesFolder := storage.FilesFolder()
+
+	folders, err := ioutil.ReadDir(filesFolder)
+	if errors.IsError(err) {
+		if !os.IsNotExist(err) {
+			log.Println(logPrefix, "Unable to list files folder", err)
+		}
+
+		return
+	}
+
+	for _, folder := range folders {
+		if !folder.IsDir() || folder.ModTime().After(deadline) {
+			continue
+		}
+
+		notFound := dataNode.DB.Connection.Where("token = ?", folder.Name()).Find(&datanode.File{}).RecordNotFound()
+		if !notFound {
+			continue
+		}
+
+		log.Println(logPrefix, fmt.Sprintf("Removing folder %s, it has no file record", folder.Name()))
+
+		err := os.RemoveAll(storage.FileFolder(folder.Name()))
+		if errors.IsError(err) {
+			log.Println(logPrefix, fmt.Sprintf("Unable to remove folder %s", folder.Name()), err)
+		}
+	}
+}
+
+// collectOrphanedReplicaEntries A function to remove replica entries in cache whose original file no longer exists
+func (janitor *Janitor) collectOrphanedReplicaEntries() {
+	dataNode := datanode.NodeInstance()
+
+	tokens, err := replication.GetReplicatedTokens()
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to fetch replica entries", err)
+		return
+	}
+
+	for _, token := range tokens {
+		notFound := dataNode.DB.Connection.Where("token = ?", token).Find(&datanode.File{}).RecordNotFound()
+		if !notFound {
+			continue
+		}
+
+		log.Println(logPrefix, fmt.Sprintf("Removing replica entry of file %s, it has no file record", token))
+
+		err := replication.RemoveReplica(token)
+		if errors.IsError(err) {
+			log.Println(logPrefix, fmt.Sprintf("Unable to remove replica of file %s", token), err)
+		}
+	}
+}
+
+// RemoveUpload A function to remove an upload from disk and database, and to drop its upload state,
+// in case the file is still being forwarded down a replica chain, the next replica is removed as well,
+// a replica that couldn't be removed keeps its replica entry, so it's retried by the collection of orphaned
+// replica entries, and the local file is removed regardless
+func RemoveUpload(file datanode.File) error {
+	if file.ReplicaCount > 0 {
+		err := replication.RemoveReplica(file.Token)
+		if errors.IsError(err) {
+			log.Println(logPrefix, fmt.Sprintf("Unable to remove replica of file %s", file.Token), err)
+		}
+	}
+
+	defer upload.Forget(file.Token)
+
+	return datanode.NodeInstance().RemoveFile(file)
+}
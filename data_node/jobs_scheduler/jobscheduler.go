@@ -5,6 +5,7 @@ import (
 	"log"
 	"os/exec"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/SayedAlesawy/Videra-Storage/config"
@@ -47,9 +48,25 @@ func (jobQueue *JobQueue) InsertJob(name string, cmd string, args []string, post
 
 // InsertJobWithDir inserts a job into job queue to be executed at dir
 func (jobQueue *JobQueue) InsertJobWithDir(name string, dir string, cmd string, args []string, postExecution PostJob) {
+	atomic.AddInt32(&jobQueue.queued, 1)
 	jobQueue.jobsQueue <- job{name: name, dir: dir, cmd: cmd, args: args, postExecution: postExecution}
 }
 
+// Running returns the number of jobs being executed
+func (jobQueue *JobQueue) Running() int {
+	return int(atomic.LoadInt32(&jobQueue.running))
+}
+
+// Queued returns the number of jobs waiting to be executed
+func (jobQueue *JobQueue) Queued() int {
+	return int(atomic.LoadInt32(&jobQueue.queued))
+}
+
+// Capacity returns the maximum number of concurrent jobs
+func (jobQueue *JobQueue) Capacity() int {
+	return jobQueue.capacity
+}
+
 // processJobs is responsible for periodically process jobs from job queue
 func (jobQueue *JobQueue) processJobs() {
 	// Blocks untill there's a token
@@ -57,6 +74,7 @@ func (jobQueue *JobQueue) processJobs() {
 	for {
 		<-jobQueue.tokens
 		nextJob := <-jobQueue.jobsQueue
+		atomic.AddInt32(&jobQueue.queued, -1)
 		jobQueue.executeJob(nextJob)
 	}
 }
@@ -66,6 +84,9 @@ func (jobQueue *JobQueue) executeJob(executedJob job) {
 	// add a token when job is finished
 	defer jobQueue.addTokens(1)
 
+	atomic.AddInt32(&jobQueue.running, 1)
+	defer atomic.AddInt32(&jobQueue.running, -1)
+
 	log.Println(logPrefix, "Starting executing job", executedJob.name)
 
 	cmd := exec.Command(executedJob.cmd, executedJob.args...)
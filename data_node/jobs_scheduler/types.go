@@ -8,6 +8,8 @@ type JobQueue struct {
 	tokens    chan struct{} //represents available slots for job
 	capacity  int           //maximum number of concurrent jobs
 	timeout   time.Duration //time out for executing job
+	running   int32         //number of jobs being executed
+	queued    int32         //number of jobs waiting to be executed
 }
 
 type job struct {
@@ -0,0 +1,114 @@
+package membership
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/SayedAlesawy/Videra-Storage/config"
+	datanode "github.com/SayedAlesawy/Videra-Storage/data_node"
+	"github.com/SayedAlesawy/Videra-Storage/name_node/nnpb"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+// logPrefix Used for hierarchical logging
+var logPrefix = "[Membership]"
+
+// heartbeatOnce Used to garauntee thread safety for singleton instances
+var heartbeatOnce sync.Once
+
+// heartbeatInstance A singleton instance of the heartbeat object
+var heartbeatInstance *Heartbeat
+
+// Heartbeat Keeps the data node a member of the cluster, by periodically reporting its liveness and load
+// and the file copies on its disk to the name node, and joining the cluster again along with a block report
+// whenever the name node lost track of it
+type Heartbeat struct {
+	interval            time.Duration //Frequency of sending heartbeats
+	blockReportInterval time.Duration //Frequency of sending block reports
+}
+
+// Instance A function to return a singleton heartbeat instance
+func Instance() *Heartbeat {
+	dataNodeConfig := config.ConfigurationManagerInstance("").DataNodeConfig()
+
+	heartbeatOnce.Do(func() {
+		heartbeat := Heartbeat{
+			interval:            time.Duration(dataNodeConfig.HeartbeatInterval) * time.Second,
+			blockReportInterval: time.Duration(dataNodeConfig.BlockReportInterval) * time.Second,
+		}
+
+		heartbeatInstance = &heartbeat
+	})
+
+	return heartbeatInstance
+}
+
+// Start A function to periodically send heartbeats and block reports to the name node
+func (heartbeat *Heartbeat) Start() {
+	beats := time.Tick(heartbeat.interval)
+	blockReports := time.Tick(heartbeat.blockReportInterval)
+
+	for {
+		select {
+		case <-beats:
+			heartbeat.beat()
+		case <-blockReports:
+			heartbeat.reportBlocks()
+		}
+	}
+}
+
+// beat A function to send a heartbeat, the data node joins the cluster again if the name node doesn't know it,
+// like after a name node restart or a leader fail over
+func (heartbeat *Heartbeat) beat() {
+	dataNode := datanode.NodeInstance()
+	load := CollectLoad()
+
+	status, err := dataNode.Heartbeat(&nnpb.HeartbeatRequest{
+		Healthy:         load.Healthy,
+		FreeDisk:        load.FreeDisk,
+		UsedDisk:        load.UsedDisk,
+		TotalDisk:       load.TotalDisk,
+		FileCount:       load.FileCount,
+		RunningJobs:     int32(load.RunningJobs),
+		QueuedJobs:      int32(load.QueuedJobs),
+		InFlightUploads: int32(load.InFlightUploads),
+		GPU:             load.GPU,
+		GPUAvailable:    load.GPUAvailable,
+	})
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to send heartbeat", err)
+		return
+	}
+
+	if status == nnpb.HeartbeatResponse_UNKNOWN_NODE {
+		log.Println(logPrefix, "Name node lost track of the data node, joining the cluster again")
+		dataNode.JoinCluster()
+	}
+}
+
+// reportBlocks A function to send a block report, the data node joins the cluster again if the name node doesn't know it
+func (heartbeat *Heartbeat) reportBlocks() {
+	dataNode := datanode.NodeInstance()
+
+	res, err := dataNode.ReportBlocks()
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to send block report", err)
+		return
+	}
+
+	switch res.Status {
+	case nnpb.BlockReportResponse_SUCCESS:
+		log.Println(logPrefix, fmt.Sprintf("Block report sent, missing: %d, extra: %d, stale: %d",
+			res.MissingCount, res.ExtraCount, res.StaleCount))
+	case nnpb.BlockReportResponse_UNKNOWN_NODE:
+		log.Println(logPrefix, "Name node lost track of the data node, joining the cluster again")
+		dataNode.JoinCluster()
+	case nnpb.BlockReportResponse_NOT_LEADER:
+		log.Println(logPrefix, "Block report reached a follower name node")
+	default:
+		log.Println(logPrefix, "Name node was unable to process the block report")
+	}
+}
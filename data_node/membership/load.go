@@ -0,0 +1,67 @@
+package membership
+
+import (
+	"log"
+	"time"
+
+	"github.com/SayedAlesawy/Videra-Storage/config"
+	datanode "github.com/SayedAlesawy/Videra-Storage/data_node"
+	jobscheduler "github.com/SayedAlesawy/Videra-Storage/data_node/jobs_scheduler"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/storage"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+// Load Represents the capacity and load of the data node
+type Load struct {
+	Healthy         bool   //Indicates if the disk and database of the data node are reachable
+	FreeDisk        uint64 //Free bytes on the disk holding the files
+	UsedDisk        uint64 //Used bytes on the disk holding the files
+	TotalDisk       uint64 //Total bytes of the disk holding the files
+	FileCount       int64  //Number of files held by the data node
+	RunningJobs     int    //Number of ingestion jobs being executed
+	QueuedJobs      int    //Number of ingestion jobs waiting to be executed
+	InFlightUploads int    //Number of uploads in progress
+	GPU             bool   //Indicates if the data node has a GPU
+	GPUAvailable    bool   //Indicates if the GPU can take more jobs
+}
+
+// CollectLoad A function to collect the capacity and load of the data node,
+// which is unhealthy if its disk or database can't be reached
+func CollectLoad() Load {
+	dataNode := datanode.NodeInstance()
+	jobQueue := jobscheduler.JobQueueInstance()
+
+	load := Load{
+		Healthy:     true,
+		RunningJobs: jobQueue.Running(),
+		QueuedJobs:  jobQueue.Queued(),
+		GPU:         dataNode.GPU,
+	}
+	load.GPUAvailable = dataNode.GPU && load.RunningJobs+load.QueuedJobs < jobQueue.Capacity()
+
+	freeDisk, totalDisk, err := datanode.DiskUsage(storage.FilesFolder())
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to get disk usage", err)
+		load.Healthy = false
+	}
+	load.FreeDisk = freeDisk
+	load.TotalDisk = totalDisk
+	load.UsedDisk = totalDisk - freeDisk
+
+	err = dataNode.DB.Connection.Model(&datanode.File{}).Where("data_node_id = ?", dataNode.ID).Count(&load.FileCount).Error
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to count files", err)
+		load.Healthy = false
+	}
+
+	// uploads idle for longer than the upload TTL are abandoned, and collected by the janitor
+	deadline := time.Now().Add(-time.Duration(config.ConfigurationManagerInstance("").DataNodeConfig().UploadTTL) * time.Second)
+	err = dataNode.DB.Connection.Model(&datanode.File{}).
+		Where("data_node_id = ? AND completed_at IS NULL AND updated_at >= ?", dataNode.ID, deadline).Count(&load.InFlightUploads).Error
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to count in flight uploads", err)
+		load.Healthy = false
+	}
+
+	return load
+}
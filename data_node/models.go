@@ -9,18 +9,38 @@ import (
 // File Represents the file info model
 type File struct {
 	gorm.Model
-	Token          string     `gorm:"unique_index;not null"` //Unique token for the file
-	Name           string     //Name of file
-	Type           string     //ndicates type of file (video, model .... etc)
-	Path           string     //Path to file (excluding file name)
-	HLSPath        string     //Path to HLS file in case of video
-	ThumbnailPath  string     //Path to thumbnail of video (in case of video)
-	Extras         string     `gorm:"size:500"` //Extras json field for any extra info
+	Token            string     `gorm:"unique_index;not null"` //Unique token for the file
+	Name             string     //Name of file
+	Type             string     //ndicates type of file (video, model .... etc)
+	Path             string     //Path to file (excluding file name)
+	HLSPath          string     //Path to HLS file in case of video
+	ThumbnailPath    string     //Path to thumbnail of video (in case of video)
+	Extras           string     `gorm:"size:500"` //Extras json field for any extra info
+	DataNodeID       string     //ID of the data node that has the file
+	Parent           string     //Token of the parent file in case it's a replica
+	Offset           int64      //Offset of bytes to start writing data at
+	ReceivedRanges   string     `gorm:"type:text"` //Json encoded byte ranges received so far
+	Size             int64      //Total size of file in bytes
+	TotalJobCount    int        //Total number of jobs needed to apply on video
+	TotalDoneCount   int        //Number of jobs applied to the video
+	ExpectedChecksum string     //SHA-256 digest of the whole file as sent by the client (hex)
+	Checksum         string     //SHA-256 digest of the whole file computed on completion (hex)
+	ReplicaCount     int        //Number of nodes down the replica chain this node forwards the file to
+	DurableReplicas  int        //Number of replicas down the chain verified to hold the whole file
+	CompletedAt      *time.Time //Indicates if file completed uploading
+	ScrubbedAt       *time.Time //Time at which the file checksum was last recomputed by the scrubber
+	ScrubbedChecksum string     //SHA-256 digest of the whole file as last recomputed by the scrubber (hex)
+}
+
+// ReplicationLog Represents the replication progress of a file to the next replica in its chain
+type ReplicationLog struct {
+	gorm.Model
+	Token          string     `gorm:"unique_index;not null"` //Token of the file being replicated
 	DataNodeID     string     //ID of the data node that has the file
-	Parent         string     //Token of the parent file in case it's a replica
-	Offset         int64      //Offset of bytes to start writing data at
-	Size           int64      //Total size of file in bytes
-	TotalJobCount  int        //Total number of jobs needed to apply on video
-	TotalDoneCount int        //Number of jobs applied to the video
-	CompletedAt    *time.Time //Indicates if file completed uploading
+	ReplicaAddress string     //Internal address of the node holding the replica
+	ReplicaID      string     //Token of the replica on its node
+	ShippedRanges  string     `gorm:"type:text"` //Json encoded byte ranges the replica is known to hold
+	Attempts       int        //Number of consecutive failed attempts to ship chunks to the replica
+	LastError      string     `gorm:"size:500"` //Error of the last failed attempt
+	VerifiedAt     *time.Time //Indicates if the whole replica chain was verified to hold the file
 }
@@ -0,0 +1,174 @@
+package datanode
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+// ByteRange Represents a range of bytes of a file, starting at Start (inclusive) and ending at End (exclusive)
+type ByteRange struct {
+	Start int64 `json:"start"` //Offset of the first byte in the range
+	End   int64 `json:"end"`   //Offset after the last byte in the range
+}
+
+// ByteRanges Represents a set of byte ranges, kept sorted and merged when built using Insert
+type ByteRanges []ByteRange
+
+// DecodeByteRanges Decodes the stringified byte ranges
+func DecodeByteRanges(encodedRanges string) (ByteRanges, error) {
+	var ranges ByteRanges
+	if encodedRanges == "" {
+		return ranges, nil
+	}
+
+	err := json.Unmarshal([]byte(encodedRanges), &ranges)
+
+	return ranges, err
+}
+
+// ParseByteRanges Parses byte ranges formatted as a comma separated list of start-end pairs
+func ParseByteRanges(formattedRanges string) (ByteRanges, error) {
+	var ranges ByteRanges
+	for _, formattedRange := range strings.Split(formattedRanges, ",") {
+		if formattedRange = strings.TrimSpace(formattedRange); formattedRange == "" {
+			continue
+		}
+
+		var byteRange ByteRange
+		_, err := fmt.Sscanf(formattedRange, "%d-%d", &byteRange.Start, &byteRange.End)
+		if errors.IsError(err) || byteRange.Start < 0 || byteRange.End <= byteRange.Start {
+			return nil, errors.New(fmt.Sprintf("Malformed byte range %q", formattedRange))
+		}
+
+		ranges = ranges.Insert(byteRange)
+	}
+
+	return ranges, nil
+}
+
+// Encode A function to encode the byte ranges into json format
+func (ranges ByteRanges) Encode() string {
+	if len(ranges) == 0 {
+		return ""
+	}
+
+	encodedRanges, _ := json.Marshal(ranges)
+
+	return string(encodedRanges)
+}
+
+// Overlaps Checks if the given range overlaps any of the ranges
+func (ranges ByteRanges) Overlaps(byteRange ByteRange) bool {
+	for _, existingRange := range ranges {
+		if byteRange.Start < existingRange.End && existingRange.Start < byteRange.End {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Contains Checks if the given range is entirely held by one of the ranges
+func (ranges ByteRanges) Contains(byteRange ByteRange) bool {
+	for _, existingRange := range ranges {
+		if existingRange.Start <= byteRange.Start && byteRange.End <= existingRange.End {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Insert Adds the given range, merging it with adjacent ranges
+func (ranges ByteRanges) Insert(byteRange ByteRange) ByteRanges {
+	merged := append(ByteRanges{}, ranges...)
+	merged = append(merged, byteRange)
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Start < merged[j].Start
+	})
+
+	result := ByteRanges{merged[0]}
+	for _, current := range merged[1:] {
+		last := &result[len(result)-1]
+		if current.Start <= last.End {
+			if current.End > last.End {
+				last.End = current.End
+			}
+
+			continue
+		}
+
+		result = append(result, current)
+	}
+
+	return result
+}
+
+// Remove Drops the given range, used to release reserved ranges
+func (ranges ByteRanges) Remove(byteRange ByteRange) ByteRanges {
+	var result ByteRanges
+	for _, existingRange := range ranges {
+		if existingRange != byteRange {
+			result = append(result, existingRange)
+		}
+	}
+
+	return result
+}
+
+// Subtract Returns the parts of the ranges not covered by the given ranges
+func (ranges ByteRanges) Subtract(other ByteRanges) ByteRanges {
+	var result ByteRanges
+	for _, byteRange := range ranges {
+		remaining := ByteRanges{byteRange}
+		for _, otherRange := range other {
+			var next ByteRanges
+			for _, current := range remaining {
+				if otherRange.End <= current.Start || current.End <= otherRange.Start {
+					next = append(next, current)
+					continue
+				}
+
+				if current.Start < otherRange.Start {
+					next = append(next, ByteRange{Start: current.Start, End: otherRange.Start})
+				}
+				if otherRange.End < current.End {
+					next = append(next, ByteRange{Start: otherRange.End, End: current.End})
+				}
+			}
+			remaining = next
+		}
+
+		result = append(result, remaining...)
+	}
+
+	return result
+}
+
+// ContiguousEnd Returns the end of the range received contiguously from the start of the file
+func (ranges ByteRanges) ContiguousEnd() int64 {
+	if len(ranges) == 0 || ranges[0].Start != 0 {
+		return 0
+	}
+
+	return ranges[0].End
+}
+
+// Covers Checks if the ranges cover a whole file of the given size
+func (ranges ByteRanges) Covers(size int64) bool {
+	return ranges.ContiguousEnd() >= size
+}
+
+// String Formats the ranges as a comma separated list of start-end pairs
+func (ranges ByteRanges) String() string {
+	var formattedRanges []string
+	for _, byteRange := range ranges {
+		formattedRanges = append(formattedRanges, fmt.Sprintf("%d-%d", byteRange.Start, byteRange.End))
+	}
+
+	return strings.Join(formattedRanges, ",")
+}
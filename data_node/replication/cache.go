@@ -21,6 +21,11 @@ func getFromHash(key string, field string) (string, error) {
 	return datanode.NodeInstance().Cache.HGet(key, field).Result()
 }
 
+// getHashFields A function to get all fields of a redis hash
+func getHashFields(key string) ([]string, error) {
+	return datanode.NodeInstance().Cache.HKeys(key).Result()
+}
+
 // invalidCacheValue checks the value returned from cache is invalid
 func invalidCacheValue(value string, err error) bool {
 	return fmt.Sprintf("%v", err) == "redis: nil" && value == ""
@@ -0,0 +1,97 @@
+package replication
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	datanode "github.com/SayedAlesawy/Videra-Storage/data_node"
+)
+
+// CopyFile is responsible for streaming a completed file to another data node as a new replica,
+// the copy is created from the file metadata and verified against the file digest,
+// it returns the token of the new copy
+func CopyFile(fileInfo datanode.File, targetAddress string) (string, error) {
+	parts, err := getFileParts(fileInfo)
+	if err != nil {
+		return "", err
+	}
+
+	metadata, err := fileMetadata(fileInfo, fileInfo.Checksum, nil)
+	if err != nil {
+		return "", err
+	}
+
+	chunkStream, err := openStream(targetAddress, metadata)
+	if err != nil {
+		return "", err
+	}
+
+	// chunks are not allowed to span the model sub-files, so each part is sent on its own
+	var partStart int64
+	for _, part := range parts {
+		err := sendPart(chunkStream, part, partStart, partStart, partStart+part.size)
+		if err != nil {
+			chunkStream.Abort()
+			return "", err
+		}
+
+		partStart += part.size
+	}
+
+	status, err := chunkStream.Close()
+	if err != nil {
+		return "", err
+	}
+
+	if !status.completed {
+		return "", errors.New(fmt.Sprintf("Copy of file %s wasn't completed by %s", fileInfo.Token, targetAddress))
+	}
+
+	if status.checksum != fileInfo.Checksum {
+		return "", errors.New(fmt.Sprintf("Copy of file %s diverged, expected checksum %s found %s", fileInfo.Token, fileInfo.Checksum, status.checksum))
+	}
+
+	log.Println(replicationLogPrefix, fmt.Sprintf("Copied file %s to %s as %s", fileInfo.Token, targetAddress, status.token))
+	return status.token, nil
+}
+
+// filePart Represents one of the files on disk making up an uploaded file
+type filePart struct {
+	path string //Path of the part on disk
+	size int64  //Size of the part in bytes
+}
+
+// getFileParts is a function to get the files on disk making up an uploaded file, in upload order
+func getFileParts(fileInfo datanode.File) ([]filePart, error) {
+	if fileInfo.Type != datanode.ModelFileType {
+		return []filePart{{path: fileInfo.Path, size: fileInfo.Size}}, nil
+	}
+
+	var modelExtras datanode.ModelExtras
+	err := json.Unmarshal([]byte(fileInfo.Extras), &modelExtras)
+	if err != nil {
+		return nil, err
+	}
+
+	return []filePart{
+		{path: fileInfo.Path, size: modelExtras.ModelSize},
+		{path: modelExtras.AssociatedConfigPath, size: modelExtras.AssociatedConfigSize},
+		{path: modelExtras.AssociatedCodePath, size: modelExtras.AssociatedCodeSize},
+	}, nil
+}
+
+// sendPart is a function to stream the bytes between start and end (file offsets) of a part,
+// which starts at partStart in the file
+func sendPart(chunkStream *ChunkStream, part filePart, partStart int64, start int64, end int64) error {
+	file, err := os.Open(part.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return chunkStream.SendFrom(io.NewSectionReader(file, start-partStart, end-start), start, end-start)
+}
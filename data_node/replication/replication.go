@@ -2,151 +2,216 @@ package replication
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/SayedAlesawy/Videra-Storage/config"
+	datanode "github.com/SayedAlesawy/Videra-Storage/data_node"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/dnpb"
+	grpc "google.golang.org/grpc"
 )
 
 // for logging hierarchy
 var replicationLogPrefix = "[Replication]"
 
-// ReplicateVideo is responsible for replicating video to other data nodes
-func ReplicateVideo(r *http.Request, token string) error {
-	reqType := strings.ToLower(r.Header.Get("Request-Type"))
-
-	switch reqType {
-	case "init":
-		err := replicateInitialRequest(r, token)
-		return err
-	case "append":
-		err := replicateAppendRequest(r, token)
-		return err
-	default:
-		log.Println(replicationLogPrefix, "Undefined request", reqType)
-		return errors.New("Undefined request")
+// GetPipeline is a function to get the ordered internal addresses of the nodes that should hold
+// the replicas of a new file, a factor of 0 means the cluster wide replication factor
+func GetPipeline(fileType string, factor int, size int64) ([]string, error) {
+	// a single copy needs no replicas
+	if factor == 1 {
+		return nil, nil
 	}
+
+	return getAvailableNodes(factor, fileType, size)
 }
 
-func replicateInitialRequest(r *http.Request, token string) error {
-	config := config.ConfigurationManagerInstance("").DataNodeConfig()
-	replicationNode, err := getReplicationNode(token, "init")
+// StartReplication is responsible for starting the replica chain of a file, the replica is created
+// on the first node of the pipeline, which forwards the rest of the pipeline to the next node in the chain,
+// it returns the number of nodes down the chain that will hold a replica
+func StartReplication(fileInfo datanode.File, pipeline []string) (int, error) {
+	// end of the chain
+	if len(pipeline) == 0 {
+		return 0, nil
+	}
+
+	replicationNode, err := registerReplicationNode(fileInfo, pipeline[0])
 	if err != nil {
 		log.Println(replicationLogPrefix, err)
-		return err
+		return 0, err
 	}
-	fmt.Println(replicationLogPrefix, "Sending initial replicate request to ", replicationNode.URL)
+	log.Println(replicationLogPrefix, "Sending replica metadata to", replicationNode.Address)
 
-	client := newClient(config.ReplicationNumberOfRetries, config.ReplicationWaitingTime)
-	req, _ := http.NewRequest(http.MethodPost, replicationNode.URL, nil)
-	req.Header = r.Header.Clone()
-	req.Header.Set("Parent", token)
-	res, err := client.Do(req)
+	metadata, err := fileMetadata(fileInfo, fileInfo.ExpectedChecksum, pipeline[1:])
 	if err != nil {
-		log.Println(replicationLogPrefix, err)
+		cleanUp(fileInfo.Token)
+		return 0, err
+	}
+
+	var status replicaStatus
+	err = withRetries(func() error {
+		chunkStream, err := openStream(replicationNode.Address, metadata)
+		if err != nil {
+			return err
+		}
+
+		status, err = chunkStream.Close()
 		return err
+	})
+	if err != nil {
+		cleanUp(fileInfo.Token)
+		log.Println(replicationLogPrefix, err)
+		return 0, err
 	}
 
-	if res.StatusCode != http.StatusCreated {
-		return errors.New("Initial replicate request denied")
+	err = updateReplicaID(replicationNode, fileInfo.Token, status.token)
+	if err != nil {
+		return 0, err
 	}
-	fileID := res.Header.Get("ID")
-	updateReplicaID(replicationNode, token, fileID)
-	return nil
+
+	replicationNode.ID = status.token
+	err = createReplicationLog(fileInfo.Token, replicationNode)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(pipeline), nil
 }
 
-func replicateAppendRequest(r *http.Request, token string) error {
-	config := config.ConfigurationManagerInstance("").DataNodeConfig()
-	replicationNode, err := getReplicationNode(token, "append")
+// ReplicateChunk is responsible for streaming a chunk of a file to its replica, reading it from the given reader
+func ReplicateChunk(token string, offset int64, chunk io.Reader, size int64) error {
+	chunkStream, err := OpenChunkStream(token)
 	if err != nil {
 		log.Println(replicationLogPrefix, err)
 		return err
 	}
-	client := newClient(config.ReplicationNumberOfRetries, config.ReplicationWaitingTime)
-	// for some reason, I can't pass old request body to new request
-	// I have to recreate new body
-	content, err := ioutil.ReadAll(r.Body)
+
+	err = chunkStream.SendFrom(chunk, offset, size)
 	if err != nil {
+		chunkStream.Abort()
 		log.Println(replicationLogPrefix, err)
 		return err
 	}
 
-	body := bytes.NewReader(content)
+	_, err = chunkStream.Close()
+	return err
+}
 
-	req, _ := http.NewRequest(http.MethodPost, replicationNode.URL, body)
-	req.Header = r.Header.Clone()
-	req.Header.Set("ID", replicationNode.ID)
+// VerifyReplica is responsible for verifying that the replica of a completed file holds identical bytes,
+// by comparing its whole file digest against the original's one, it returns the number of
+// durable replicas down the chain, which is the verified replica and the ones it verified
+func VerifyReplica(fileInfo datanode.File) (int, error) {
+	replicationLog, err := getReplicationLog(fileInfo.Token)
+	if err != nil {
+		log.Println(replicationLogPrefix, err)
+		return 0, err
+	}
 
-	res, err := client.Do(req)
+	status, err := getReplicaStatus(replicaOf(replicationLog, fileInfo))
 	if err != nil {
-		cleanUp(token)
 		log.Println(replicationLogPrefix, err)
-		return err
+		return 0, err
 	}
 
-	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusCreated {
-		return errors.New("Replication request denied")
+	if !status.completed || status.checksum != fileInfo.Checksum {
+		return 0, errors.New(fmt.Sprintf("Replica of file %s diverged, expected checksum %s found %s", fileInfo.Token, fileInfo.Checksum, status.checksum))
 	}
-	// Replication is finished
-	if res.StatusCode == http.StatusCreated {
-		cleanUp(token)
+
+	durableReplicas := status.durableReplicas + 1
+	err = recordVerification(&replicationLog, fileInfo, durableReplicas)
+	if err != nil {
+		return 0, err
 	}
-	return nil
+
+	return durableReplicas, nil
 }
 
-func getReplicationNode(token string, reqType string) (Replica, error) {
-	if reqType == "init" {
-		replica := Replica{}
+// registerReplicationNode is a function to record the node holding the replica of a file in cache
+func registerReplicationNode(fileInfo datanode.File, address string) (Replica, error) {
+	token := fileInfo.Token
+	replica := Replica{Address: address, Parent: fileInfo.Parent}
+	replicaJSON, err := encodeReplicaNode(replica)
+	if err != nil {
+		return replica, err
+	}
 
-		url, err := getAvailableNode()
-		if err != nil {
-			return replica, err
-		}
-		replica.URL = url
-		replicaJSON, err := encodeReplicaNode(replica)
-		if err != nil {
-			return replica, err
-		}
+	key := getReplicaKey()
+	field := getReplicaField(token)
+	err = insertIntoHash(key, field, replicaJSON)
+	if err != nil {
+		return replica, err
+	}
 
-		key := getReplicaKey()
-		field := getReplicaField(token)
-		insertIntoHash(key, field, replicaJSON)
-		log.Println(replicationLogPrefix, fmt.Sprintf("Replication node for file %s is set to URL: %s", token, url))
-		return replica, nil
-	} else if reqType == "append" {
-		key := getReplicaKey()
-		field := getReplicaField(token)
-		node, err := getFromHash(key, field)
-
-		// an error has happend
-		if invalidCacheValue(node, err) {
-			log.Println(replicationLogPrefix, fmt.Sprintf("Cache missed for file %s", token))
-			return Replica{}, errors.New("Cache missed for file")
-		}
+	log.Println(replicationLogPrefix, fmt.Sprintf("Replication node for file %s is set to address: %s", token, address))
+	return replica, nil
+}
 
-		replica, err := decodeReplicaNodeData(node)
-		if err != nil {
-			return Replica{}, err
+// getReplicationNode is a function to get the node holding the replica of a file from cache
+func getReplicationNode(token string) (Replica, error) {
+	key := getReplicaKey()
+	field := getReplicaField(token)
+	node, err := getFromHash(key, field)
+
+	// an error has happend
+	if invalidCacheValue(node, err) {
+		log.Println(replicationLogPrefix, fmt.Sprintf("Cache missed for file %s", token))
+		return Replica{}, errors.New("Cache missed for file")
+	}
+
+	return decodeReplicaNodeData(node)
+}
+
+// getAvailableNodes is a function to request an ordered pipeline of nodes from the name node
+// to hold the replicas of a file, a factor of 0 means the cluster wide replication factor,
+// while models are replicated to the nodes chosen by the name node regardless of the factor,
+// the size lets the name node skip nodes without enough free disk for the file,
+// the name nodes are tried in order until one of them is reachable
+func getAvailableNodes(factor int, fileType string, size int64) ([]string, error) {
+	dataNodeConfig := config.ConfigurationManagerInstance("").DataNodeConfig()
+
+	var err error
+	for _, replicationURL := range strings.Split(dataNodeConfig.NameNodeReplicationURL, ",") {
+		var pipeline []string
+		pipeline, err = requestPipeline(strings.TrimSpace(replicationURL), factor, fileType, size)
+		if err == nil {
+			return pipeline, nil
 		}
 
-		return replica, nil
-	} else {
-		return Replica{}, errors.New("Invalid request type")
+		log.Println(replicationLogPrefix, "Unable to get replication pipeline from", replicationURL, err)
 	}
+
+	return nil, err
 }
 
-func getAvailableNode() (string, error) {
+// requestPipeline is a function to request an ordered pipeline of nodes from a name node
+func requestPipeline(replicationURL string, factor int, fileType string, size int64) ([]string, error) {
 	dataNodeConfig := config.ConfigurationManagerInstance("").DataNodeConfig()
 	client := newClient(dataNodeConfig.ReplicationNumberOfRetries, dataNodeConfig.ReplicationWaitingTime)
 	nodeID := bytes.NewReader(([]byte(dataNodeConfig.ID)))
-	req, _ := http.NewRequest(http.MethodGet, dataNodeConfig.NameNodeReplicationURL, nodeID)
+	req, err := http.NewRequest(http.MethodGet, replicationURL, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	query := req.URL.Query()
+	if factor > 0 {
+		query.Set("factor", strconv.Itoa(factor))
+	}
+	if fileType == datanode.ModelFileType {
+		query.Set("type", fileType)
+	}
+	query.Set("size", strconv.FormatInt(size, 10))
+	req.URL.RawQuery = query.Encode()
+
 	res, err := client.Do(req)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	defer res.Body.Close()
@@ -154,17 +219,21 @@ func getAvailableNode() (string, error) {
 	bodyBytes, err := ioutil.ReadAll(res.Body)
 	if err != nil {
 		log.Println(err)
-		return "", err
+		return nil, err
 	}
 
-	body := string(bodyBytes)
-
 	if res.StatusCode != http.StatusOK {
-		log.Println(body)
-		return "", errors.New(body)
+		log.Println(string(bodyBytes))
+		return nil, errors.New(string(bodyBytes))
 	}
 
-	return body, nil
+	var pipeline []string
+	err = json.Unmarshal(bodyBytes, &pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	return pipeline, nil
 }
 
 func updateReplicaID(replica Replica, token string, fileID string) error {
@@ -183,3 +252,70 @@ func updateReplicaID(replica Replica, token string, fileID string) error {
 
 	return nil
 }
+
+// RemoveReplica is responsible for removing the replica of a file from its replication node
+func RemoveReplica(token string) error {
+	key := getReplicaKey()
+	field := getReplicaField(token)
+	node, err := getFromHash(key, field)
+
+	// file has no replica in progress
+	if invalidCacheValue(node, err) {
+		return deleteReplicationLog(token)
+	}
+
+	replicationNode, err := decodeReplicaNodeData(node)
+	if err != nil {
+		return err
+	}
+
+	if replicationNode.ID != "" {
+		err := withRetries(func() error {
+			return deleteReplica(replicationNode)
+		})
+		if err != nil {
+			log.Println(replicationLogPrefix, err)
+			return err
+		}
+	}
+
+	log.Println(replicationLogPrefix, fmt.Sprintf("Removed replica of file %s from %s", token, replicationNode.Address))
+	cleanUp(token)
+	return deleteReplicationLog(token)
+}
+
+// deleteReplica is a function to request the node holding a replica to delete its copies of the file
+func deleteReplica(replica Replica) error {
+	conn, err := grpc.Dial(replica.Address, grpc.WithInsecure())
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	client := dnpb.NewDataNodeInternalRoutesClient(conn)
+	res, err := client.DeleteFile(context.Background(), &dnpb.DeleteFileRequest{Token: replica.Parent})
+	if err != nil {
+		return err
+	}
+
+	if res.Status != dnpb.DeleteFileResponse_SUCCESS {
+		return errors.New("Replica removal request denied")
+	}
+
+	return nil
+}
+
+// GetReplicatedTokens is responsible for listing the tokens of files with a replica entry in cache
+func GetReplicatedTokens() ([]string, error) {
+	fields, err := getHashFields(getReplicaKey())
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens []string
+	for _, field := range fields {
+		tokens = append(tokens, strings.TrimPrefix(field, getReplicaField("")))
+	}
+
+	return tokens, nil
+}
@@ -0,0 +1,196 @@
+package replication
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"io"
+
+	datanode "github.com/SayedAlesawy/Videra-Storage/data_node"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/dnpb"
+	grpc "google.golang.org/grpc"
+)
+
+// streamChunkSize Maximum size of a chunk sent on a replication stream, kept under the gRPC message size limit
+var streamChunkSize int64 = 1 << 20
+
+// ChunkStream Streams the chunks of a file to the node holding its replica
+type ChunkStream struct {
+	conn   *grpc.ClientConn                                //Connection to the node holding the replica
+	stream dnpb.DataNodeInternalRoutes_ReplicateFileClient //Stream carrying the chunks
+	cancel context.CancelFunc                              //Aborts the stream
+}
+
+// OpenChunkStream A function to open a stream to the replica of a file, registered when the replication started
+func OpenChunkStream(token string) (*ChunkStream, error) {
+	replica, err := getReplicationNode(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return openStream(replica.Address, &dnpb.FileMetadata{Parent: replica.Parent, ReplicaToken: replica.ID})
+}
+
+// openStream is a function to open a replication stream to a node, the metadata is sent as its first message
+func openStream(address string, metadata *dnpb.FileMetadata) (*ChunkStream, error) {
+	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := dnpb.NewDataNodeInternalRoutesClient(conn)
+	stream, err := client.ReplicateFile(ctx)
+	if err != nil {
+		cancel()
+		conn.Close()
+		return nil, err
+	}
+
+	chunkStream := ChunkStream{conn: conn, stream: stream, cancel: cancel}
+	err = stream.Send(&dnpb.ReplicateFileRequest{
+		Payload: &dnpb.ReplicateFileRequest_Metadata{Metadata: metadata},
+	})
+	if err != nil {
+		chunkStream.Abort()
+		return nil, err
+	}
+
+	return &chunkStream, nil
+}
+
+// Send A function to send a chunk of the file at the given offset, along with its digest
+func (chunkStream *ChunkStream) Send(offset int64, data []byte) error {
+	checksum := sha256.Sum256(data)
+
+	return chunkStream.stream.Send(&dnpb.ReplicateFileRequest{
+		Payload: &dnpb.ReplicateFileRequest_Chunk{Chunk: &dnpb.FileChunk{
+			Offset:   offset,
+			Data:     data,
+			Checksum: checksum[:],
+		}},
+	})
+}
+
+// SendFrom A function to send the given number of bytes read from a reader, starting at the given offset,
+// the bytes are split into chunks of acceptable size
+func (chunkStream *ChunkStream) SendFrom(reader io.Reader, offset int64, size int64) error {
+	buffer := make([]byte, streamChunkSize)
+
+	for sent := int64(0); sent < size; {
+		chunkSize := size - sent
+		if chunkSize > streamChunkSize {
+			chunkSize = streamChunkSize
+		}
+
+		_, err := io.ReadFull(reader, buffer[:chunkSize])
+		if err != nil {
+			return err
+		}
+
+		err = chunkStream.Send(offset+sent, buffer[:chunkSize])
+		if err != nil {
+			return err
+		}
+
+		sent += chunkSize
+	}
+
+	return nil
+}
+
+// Close A function to end the stream and wait for the status of the replica
+func (chunkStream *ChunkStream) Close() (replicaStatus, error) {
+	defer chunkStream.Abort()
+
+	res, err := chunkStream.stream.CloseAndRecv()
+	if err != nil {
+		return replicaStatus{}, err
+	}
+
+	return decodeReplicaStatus(res)
+}
+
+// Abort A function to release the stream, dropping it if it wasn't closed
+func (chunkStream *ChunkStream) Abort() {
+	chunkStream.cancel()
+	chunkStream.conn.Close()
+}
+
+// replicaStatus Represents the upload progress of a replica as reported by its node
+type replicaStatus struct {
+	token           string              //Token of the replica
+	completed       bool                //Indicates if the replica completed uploading
+	checksum        string              //SHA-256 digest of the replica (once completed)
+	ranges          datanode.ByteRanges //Byte ranges held by the replica
+	durableReplicas int                 //Number of replicas verified down the chain of the replica
+}
+
+// decodeReplicaStatus is a function to decode the status of a replica sent by its node
+func decodeReplicaStatus(res *dnpb.ReplicateFileResponse) (replicaStatus, error) {
+	ranges, err := datanode.ParseByteRanges(res.ReceivedRanges)
+	if err != nil {
+		return replicaStatus{}, err
+	}
+
+	return replicaStatus{
+		token:           res.Token,
+		completed:       res.Completed,
+		checksum:        res.Checksum,
+		ranges:          ranges,
+		durableReplicas: int(res.DurableReplicas),
+	}, nil
+}
+
+// getReplicaStatus is a function to request the upload progress of a replica from its node,
+// a stream carrying no chunks only reports the status
+func getReplicaStatus(replica Replica) (replicaStatus, error) {
+	var status replicaStatus
+
+	err := withRetries(func() error {
+		chunkStream, err := openStream(replica.Address, &dnpb.FileMetadata{Parent: replica.Parent, ReplicaToken: replica.ID})
+		if err != nil {
+			return err
+		}
+
+		status, err = chunkStream.Close()
+		return err
+	})
+
+	return status, err
+}
+
+// fileMetadata is a function to build the metadata describing a file to the node receiving its replica
+func fileMetadata(fileInfo datanode.File, checksum string, pipeline []string) (*dnpb.FileMetadata, error) {
+	metadata := dnpb.FileMetadata{
+		Parent:   fileInfo.Parent,
+		Name:     fileInfo.Name,
+		Type:     fileInfo.Type,
+		Size:     fileInfo.Size,
+		Checksum: checksum,
+		Pipeline: pipeline,
+	}
+
+	switch fileInfo.Type {
+	case datanode.VideoFileType:
+		var videoMetadata datanode.VideoMetadata
+		err := json.Unmarshal([]byte(fileInfo.Extras), &videoMetadata)
+		if err != nil {
+			return nil, err
+		}
+
+		metadata.AssociatedModel = videoMetadata.AssociatedModel
+	case datanode.ModelFileType:
+		var modelExtras datanode.ModelExtras
+		err := json.Unmarshal([]byte(fileInfo.Extras), &modelExtras)
+		if err != nil {
+			return nil, err
+		}
+
+		metadata.ModelSize = modelExtras.ModelSize
+		metadata.ConfigSize = modelExtras.AssociatedConfigSize
+		metadata.CodeSize = modelExtras.AssociatedCodeSize
+	}
+
+	return &metadata, nil
+}
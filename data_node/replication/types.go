@@ -2,6 +2,7 @@ package replication
 
 // Replica is a model for replication mode info
 type Replica struct {
-	URL string //URL to send data to
-	ID  string //File ID to append to
+	Address string //Internal address of the node holding the replica
+	ID      string //File ID to append to
+	Parent  string //Token of the original file, used to remove the replica
 }
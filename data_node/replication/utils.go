@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/SayedAlesawy/Videra-Storage/config"
 	"github.com/hashicorp/go-retryablehttp"
 )
 
@@ -18,6 +19,20 @@ func newClient(maxRetries int, waitingTime int) *http.Client {
 	return clientretry.StandardClient()
 }
 
+// withRetries is a function to run a replication request, retrying it on failure
+// using the same policy as the http client
+func withRetries(request func() error) error {
+	config := config.ConfigurationManagerInstance("").DataNodeConfig()
+
+	err := request()
+	for attempt := 0; err != nil && attempt < config.ReplicationNumberOfRetries; attempt++ {
+		time.Sleep(time.Duration(config.ReplicationWaitingTime) * time.Second)
+		err = request()
+	}
+
+	return err
+}
+
 // encode A function to encode the data node data into json format
 func encodeReplicaNode(replica Replica) (string, error) {
 	encodedData, err := json.Marshal(replica)
@@ -0,0 +1,214 @@
+package replication
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/SayedAlesawy/Videra-Storage/config"
+	datanode "github.com/SayedAlesawy/Videra-Storage/data_node"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/dnpb"
+)
+
+// syncReplicationMode Replication mode in which chunks are streamed to the replica before being acknowledged
+var syncReplicationMode = "sync"
+
+// workerOnce Used to garauntee thread safety for singleton instances
+var workerOnce sync.Once
+
+// workerInstance A singleton instance of the replication worker object
+var workerInstance *Worker
+
+// Worker Ships the chunks not yet replicated to the replicas, using the persistent replication log,
+// so replicas catch up after transient failures and in async replication mode
+type Worker struct {
+	interval time.Duration //Frequency of shipping the chunks not yet replicated
+}
+
+// WorkerInstance A function to return a singleton replication worker instance
+func WorkerInstance() *Worker {
+	dataNodeConfig := config.ConfigurationManagerInstance("").DataNodeConfig()
+
+	workerOnce.Do(func() {
+		worker := Worker{
+			interval: time.Duration(dataNodeConfig.ReplicationWorkerInterval) * time.Second,
+		}
+
+		workerInstance = &worker
+	})
+
+	return workerInstance
+}
+
+// SyncMode Checks if chunks are replicated synchronously within the upload requests
+func SyncMode() bool {
+	return config.ConfigurationManagerInstance("").DataNodeConfig().ReplicationMode == syncReplicationMode
+}
+
+// Start A function to periodically ship the chunks not yet replicated
+func (worker *Worker) Start() {
+	for range time.Tick(worker.interval) {
+		var replicationLogs []datanode.ReplicationLog
+
+		dataNode := datanode.NodeInstance()
+		err := dataNode.DB.Connection.Where("data_node_id = ? AND verified_at IS NULL", dataNode.ID).Find(&replicationLogs).Error
+		if err != nil {
+			log.Println(replicationLogPrefix, "Unable to fetch replication logs", err)
+			continue
+		}
+
+		for _, replicationLog := range replicationLogs {
+			err := worker.catchUp(&replicationLog)
+			if err != nil {
+				log.Println(replicationLogPrefix, fmt.Sprintf("Replica of file %s is behind", replicationLog.Token), err)
+
+				replicationLog.Attempts++
+				replicationLog.LastError = err.Error()
+				dataNode.DB.Connection.Save(&replicationLog)
+			}
+		}
+	}
+}
+
+// catchUp A function to ship the chunks of a file missing from its replica,
+// and to verify the replica chain once the whole file is shipped
+func (worker *Worker) catchUp(replicationLog *datanode.ReplicationLog) error {
+	dataNode := datanode.NodeInstance()
+
+	var fileInfo datanode.File
+	notFound := dataNode.DB.Connection.Where("token = ?", replicationLog.Token).Find(&fileInfo).RecordNotFound()
+	if notFound {
+		return deleteReplicationLog(replicationLog.Token)
+	}
+
+	// the replica is the source of truth of what was shipped, chunks may have been written
+	// by synchronous replication or by a previous attempt that failed to get acknowledged
+	replica := replicaOf(*replicationLog, fileInfo)
+	status, err := getReplicaStatus(replica)
+	if err != nil {
+		return err
+	}
+
+	receivedRanges, err := datanode.DecodeByteRanges(fileInfo.ReceivedRanges)
+	if err != nil {
+		return err
+	}
+
+	shippedRanges := status.ranges
+	for _, pendingRange := range receivedRanges.Subtract(shippedRanges) {
+		err := worker.shipRange(fileInfo, replica, pendingRange)
+		if err != nil {
+			return err
+		}
+
+		shippedRanges = shippedRanges.Insert(pendingRange)
+		replicationLog.ShippedRanges = shippedRanges.Encode()
+		dataNode.DB.Connection.Save(replicationLog)
+	}
+
+	replicationLog.ShippedRanges = shippedRanges.Encode()
+	replicationLog.Attempts = 0
+	replicationLog.LastError = ""
+
+	if fileInfo.CompletedAt == nil || !shippedRanges.Covers(fileInfo.Size) {
+		return dataNode.DB.Connection.Save(replicationLog).Error
+	}
+
+	// the replica chain is verified once all of its nodes caught up
+	_, err = VerifyReplica(fileInfo)
+	return err
+}
+
+// shipRange A function to stream a range of a file to its replica, the range is split into
+// chunks of acceptable size, that don't span the model sub-files
+func (worker *Worker) shipRange(fileInfo datanode.File, replica Replica, byteRange datanode.ByteRange) error {
+	parts, err := getFileParts(fileInfo)
+	if err != nil {
+		return err
+	}
+
+	chunkStream, err := openStream(replica.Address, &dnpb.FileMetadata{Parent: replica.Parent, ReplicaToken: replica.ID})
+	if err != nil {
+		return err
+	}
+
+	var partStart int64
+	for _, part := range parts {
+		partEnd := partStart + part.size
+		start, end := byteRange.Start, byteRange.End
+		if start < partStart {
+			start = partStart
+		}
+		if end > partEnd {
+			end = partEnd
+		}
+
+		if start < end {
+			err := sendPart(chunkStream, part, partStart, start, end)
+			if err != nil {
+				chunkStream.Abort()
+				return err
+			}
+		}
+
+		partStart = partEnd
+	}
+
+	_, err = chunkStream.Close()
+	if err != nil {
+		return err
+	}
+
+	log.Println(replicationLogPrefix, fmt.Sprintf("Shipped range %d-%d of file %s to %s", byteRange.Start, byteRange.End, fileInfo.Token, replica.Address))
+	return nil
+}
+
+// createReplicationLog is a function to start the replication log of a file
+func createReplicationLog(token string, replica Replica) error {
+	return datanode.NodeInstance().DB.Connection.Create(&datanode.ReplicationLog{
+		Token:          token,
+		DataNodeID:     datanode.NodeInstance().ID,
+		ReplicaAddress: replica.Address,
+		ReplicaID:      replica.ID,
+	}).Error
+}
+
+// getReplicationLog is a function to get the replication log of a file
+func getReplicationLog(token string) (datanode.ReplicationLog, error) {
+	var replicationLog datanode.ReplicationLog
+	err := datanode.NodeInstance().DB.Connection.Where("token = ?", token).Find(&replicationLog).Error
+
+	return replicationLog, err
+}
+
+// deleteReplicationLog is a function to drop the replication log of a file
+func deleteReplicationLog(token string) error {
+	return datanode.NodeInstance().DB.Connection.Unscoped().Where("token = ?", token).Delete(&datanode.ReplicationLog{}).Error
+}
+
+// replicaOf is a function to get the replica a replication log ships to
+func replicaOf(replicationLog datanode.ReplicationLog, fileInfo datanode.File) Replica {
+	return Replica{Address: replicationLog.ReplicaAddress, ID: replicationLog.ReplicaID, Parent: fileInfo.Parent}
+}
+
+// recordVerification is a function to record the number of durable replicas of a file,
+// the replication log is closed once all the replicas down the chain are durable
+func recordVerification(replicationLog *datanode.ReplicationLog, fileInfo datanode.File, durableReplicas int) error {
+	db := datanode.NodeInstance().DB.Connection
+
+	err := db.Model(&fileInfo).Update("durable_replicas", durableReplicas).Error
+	if err != nil {
+		return err
+	}
+
+	if durableReplicas >= fileInfo.ReplicaCount {
+		now := time.Now()
+		replicationLog.VerifiedAt = &now
+
+		// Replication is finished
+		cleanUp(fileInfo.Token)
+	}
+
+	return db.Save(replicationLog).Error
+}
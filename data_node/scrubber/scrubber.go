@@ -0,0 +1,117 @@
+package scrubber
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/SayedAlesawy/Videra-Storage/config"
+	datanode "github.com/SayedAlesawy/Videra-Storage/data_node"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/upload"
+	"github.com/SayedAlesawy/Videra-Storage/name_node/nnpb"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+// logPrefix Used for hierarchical logging
+var logPrefix = "[Scrubber]"
+
+// scrubberOnce Used to garauntee thread safety for singleton instances
+var scrubberOnce sync.Once
+
+// scrubberInstance A singleton instance of the scrubber object
+var scrubberInstance *Scrubber
+
+// Scrubber Recomputes the checksums of the completed files on the data node from disk, and reports them
+// to the name node, which compares the copies of each file and repairs the diverged ones
+type Scrubber struct {
+	interval  time.Duration //Frequency of scrubbing a batch of files
+	batchSize int           //Number of files scrubbed per round
+}
+
+// Instance A function to return a singleton scrubber instance
+func Instance() *Scrubber {
+	dataNodeConfig := config.ConfigurationManagerInstance("").DataNodeConfig()
+
+	scrubberOnce.Do(func() {
+		scrubber := Scrubber{
+			interval:  time.Duration(dataNodeConfig.ScrubInterval) * time.Second,
+			batchSize: dataNodeConfig.ScrubBatchSize,
+		}
+
+		scrubberInstance = &scrubber
+	})
+
+	return scrubberInstance
+}
+
+// Start A function to periodically scrub a batch of files, the least recently scrubbed first,
+// so all the files of the data node are scrubbed in rolling rounds
+func (scrubber *Scrubber) Start() {
+	for range time.Tick(scrubber.interval) {
+		scrubber.scrub()
+	}
+}
+
+// scrub A function to recompute the checksums of a batch of completed files and report them to the name node
+func (scrubber *Scrubber) scrub() {
+	dataNode := datanode.NodeInstance()
+
+	var files []datanode.File
+	err := dataNode.DB.Connection.Where("data_node_id = ? AND completed_at IS NOT NULL", dataNode.ID).
+		Order("scrubbed_at IS NOT NULL, scrubbed_at").Limit(scrubber.batchSize).Find(&files).Error
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to fetch files to scrub", err)
+		return
+	}
+
+	if len(files) == 0 {
+		return
+	}
+
+	var checksums []*nnpb.FileChecksum
+	for _, file := range files {
+		checksum, err := scrubber.computeChecksum(file)
+		if errors.IsError(err) {
+			log.Println(logPrefix, fmt.Sprintf("Unable to scrub file %s", file.Token), err)
+			continue
+		}
+
+		if checksum != file.Checksum {
+			log.Println(logPrefix, fmt.Sprintf("File %s diverged from its checksum on completion, expected %s found %s", file.Token, file.Checksum, checksum))
+		}
+
+		checksums = append(checksums, &nnpb.FileChecksum{
+			Token:    file.Token,
+			Parent:   file.Parent,
+			Checksum: checksum,
+		})
+	}
+
+	diverged, err := dataNode.ReportChecksums(checksums)
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to report checksums to name node", err)
+		return
+	}
+
+	log.Println(logPrefix, fmt.Sprintf("Scrubbed %d files, %d diverged from their originals", len(checksums), diverged))
+}
+
+// computeChecksum A function to recompute the checksum of a file from disk, and record it along with the scrubbing time
+func (scrubber *Scrubber) computeChecksum(file datanode.File) (string, error) {
+	paths, err := upload.FilePaths(file)
+	if errors.IsError(err) {
+		return "", err
+	}
+
+	checksum, err := datanode.ComputeFileChecksum(paths...)
+	if errors.IsError(err) {
+		return "", err
+	}
+
+	// the update time is kept, as it's the modification time of the file as served on download
+	err = datanode.NodeInstance().DB.Connection.Model(&file).
+		UpdateColumns(map[string]interface{}{"scrubbed_at": time.Now(), "scrubbed_checksum": checksum}).Error
+
+	return checksum, err
+}
@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"fmt"
+	"mime"
+	"os"
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/SayedAlesawy/Videra-Storage/config"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+// filesFolderName Name of the folder that contains the uploaded files, relative to the working directory
+var filesFolderName = "files"
+
+// Names of the blobs stored inside a file folder, they never depend on client input
+var (
+	videoBlobName  = "video"
+	modelBlobName  = "model"
+	configBlobName = "config.conf"
+	codeBlobName   = "code_file.py"
+)
+
+// MaxFilenameLength Maximum length in bytes of an original filename kept as metadata
+var MaxFilenameLength = 255
+
+// FilesFolder Returns the folder that contains all uploaded files
+func FilesFolder() string {
+	wd, _ := os.Getwd()
+
+	return path.Join(wd, filesFolderName)
+}
+
+// FileFolder Returns the folder that contains the blobs of the file with the given token
+func FileFolder(token string) string {
+	return path.Join(FilesFolder(), token)
+}
+
+// VideoPath Returns the path of the video blob of the file with the given token
+func VideoPath(token string) string {
+	return path.Join(FileFolder(token), videoBlobName)
+}
+
+// ModelPath Returns the path of the model blob of the file with the given token
+func ModelPath(token string) string {
+	return path.Join(FileFolder(token), modelBlobName)
+}
+
+// ModelConfigPath Returns the path of the model config blob of the file with the given token
+func ModelConfigPath(token string) string {
+	return path.Join(FileFolder(token), configBlobName)
+}
+
+// ModelCodePath Returns the path of the model code blob of the file with the given token
+func ModelCodePath(token string) string {
+	return path.Join(FileFolder(token), codeBlobName)
+}
+
+// StreamFolder Returns the folder that contains the HLS segments of the video with the given parent token,
+// relative to the working directory, as served by the streaming server
+func StreamFolder(parent string) string {
+	dataNodeConfig := config.ConfigurationManagerInstance("").DataNodeConfig()
+
+	return path.Join(dataNodeConfig.StreamFolderName, parent)
+}
+
+// StreamPlaylistPath Returns the path of the HLS playlist of the video with the given parent token
+func StreamPlaylistPath(parent string) string {
+	dataNodeConfig := config.ConfigurationManagerInstance("").DataNodeConfig()
+
+	return path.Join(StreamFolder(parent), dataNodeConfig.StreamPlaylistName)
+}
+
+// ThumbnailFolder Returns the folder that contains the thumbnails, relative to the working directory
+func ThumbnailFolder() string {
+	return config.ConfigurationManagerInstance("").DataNodeConfig().ThumbnailFolderName
+}
+
+// ThumbnailPath Returns the path of the thumbnail of the video with the given parent token
+func ThumbnailPath(parent string) string {
+	return path.Join(ThumbnailFolder(), fmt.Sprintf("%s_thumbnail.jpg", parent))
+}
+
+// ValidateToken A function to validate that a token is safe to be used as a path element,
+// tokens are generated as lower case hex strings
+func ValidateToken(token string) error {
+	if token == "" {
+		return errors.New("Empty token")
+	}
+
+	for _, char := range token {
+		if !strings.ContainsRune("0123456789abcdef", char) {
+			return errors.New(fmt.Sprintf("Invalid token %q", token))
+		}
+	}
+
+	return nil
+}
+
+// SanitizeFilename A function to validate a client supplied filename before keeping it as metadata,
+// surrounding spaces are trimmed and names containing path separators or control characters are rejected
+func SanitizeFilename(filename string) (string, error) {
+	filename = strings.TrimSpace(filename)
+
+	if filename == "" || filename == "." || filename == ".." {
+		return "", errors.New("Invalid filename")
+	}
+
+	if len(filename) > MaxFilenameLength {
+		return "", errors.New(fmt.Sprintf("Filename exceeds %d bytes", MaxFilenameLength))
+	}
+
+	if !utf8.ValidString(filename) {
+		return "", errors.New("Filename is not valid UTF-8")
+	}
+
+	for _, char := range filename {
+		if char == '/' || char == '\\' {
+			return "", errors.New("Filename must not contain path separators")
+		}
+
+		if unicode.IsControl(char) {
+			return "", errors.New("Filename must not contain control characters")
+		}
+	}
+
+	return filename, nil
+}
+
+// ContentDisposition Returns the Content-Disposition header value used to download a file under its original name
+func ContentDisposition(filename string) string {
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": filename})
+	if disposition == "" {
+		return "attachment"
+	}
+
+	return disposition
+}
@@ -3,28 +3,23 @@ package stream
 import (
 	"fmt"
 	"log"
-	"os"
-	"path"
 	"strings"
 
 	"github.com/SayedAlesawy/Videra-Storage/config"
 	datanode "github.com/SayedAlesawy/Videra-Storage/data_node"
 	jobscheduler "github.com/SayedAlesawy/Videra-Storage/data_node/jobs_scheduler"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/storage"
 )
 
 var streamEncodingLoggerPrefix = "[Stream-Encoding]"
 
 // PrepareStreamingVideo transforms video into streamable format
 func PrepareStreamingVideo(videoInfo datanode.File) {
-	config := config.ConfigurationManagerInstance("").DataNodeConfig()
-
-	wd, _ := os.Getwd()
-	folderPath := path.Join(wd, config.StreamFolderName, videoInfo.Parent)
+	// paths are relative to the working directory to support streaming server
+	folderPath := storage.StreamFolder(videoInfo.Parent)
 	datanode.CreateFileDirectory(folderPath, 0744)
 
-	// removed the working directory part to support streaming server
-	folderPath = path.Join(config.StreamFolderName, videoInfo.Parent)
-	streamFilePath := path.Join(folderPath, config.StreamPlaylistName)
+	streamFilePath := storage.StreamPlaylistPath(videoInfo.Parent)
 
 	command := "ffmpeg"
 	args := prepareArgs(videoInfo.Path, folderPath)
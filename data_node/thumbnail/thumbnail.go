@@ -3,23 +3,21 @@ package thumbnail
 import (
 	"fmt"
 	"log"
-	"path"
 	"strings"
 
 	"github.com/SayedAlesawy/Videra-Storage/config"
 	datanode "github.com/SayedAlesawy/Videra-Storage/data_node"
 	jobscheduler "github.com/SayedAlesawy/Videra-Storage/data_node/jobs_scheduler"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/storage"
 )
 
 var thumbnailLoggerPrefix = "[Thumbnail]"
 
 // PrepareThumbnail generates thumbnail from video
 func PrepareThumbnail(videoInfo datanode.File) {
-	config := config.ConfigurationManagerInstance("").DataNodeConfig()
-	thumbnailFolder := config.ThumbnailFolderName
-	datanode.CreateFileDirectory(thumbnailFolder, 0744)
+	datanode.CreateFileDirectory(storage.ThumbnailFolder(), 0744)
 
-	outputFilePath := path.Join(thumbnailFolder, fmt.Sprintf("%s_thumbnail.jpg", videoInfo.Parent))
+	outputFilePath := storage.ThumbnailPath(videoInfo.Parent)
 
 	command := "ffmpeg"
 	args := prepareArgs(videoInfo.Path, outputFilePath)
@@ -1,6 +1,7 @@
 package datanode
 
 import (
+	"sync"
 	"time"
 
 	"github.com/SayedAlesawy/Videra-Storage/drivers/redis"
@@ -14,9 +15,14 @@ type DataNode struct {
 	Port                  string             //Port on which all external comm is done
 	InternalPort          string             //Port on which all internal comm is done
 	GPU                   bool               //Indicates if the data node has a GPU
+	Zone                  string             //Zone (failure domain) the data node is deployed in
+	Rack                  string             //Rack the data node is mounted on, within its zone
+	DiskClass             string             //Class of the disk holding the files
 	InternalReqTimeout    time.Duration      //Timeout for internal requests
 	RejoinClusterInterval time.Duration      //Frequency of the rejoin cluster request
 	NameNode              NameNodeData       //Houses the needed info about the current name node
+	NameNodes             []NameNodeData     //All known name nodes, failed over in order when the current one is unreachable
+	nameNodeLock          sync.RWMutex       //Guards the current name node
 	DB                    *database.Database //Database connection
 	Cache                 *redis.Client      //Used by the data node to access a persistent caching layer
 }
@@ -0,0 +1,71 @@
+package upload
+
+import (
+	"fmt"
+	"sync"
+
+	datanode "github.com/SayedAlesawy/Videra-Storage/data_node"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+// fileUploadState Tracks the chunks of a file that are currently being written
+type fileUploadState struct {
+	sync.Mutex                     //Guards the in flight ranges and the updates of the file's received ranges
+	inFlight   datanode.ByteRanges //Ranges reserved by chunks that are still being written
+}
+
+// fileUploadStates Maps each file token to its upload state
+var fileUploadStates sync.Map
+
+// uploadStateOf A function to get the upload state of a file, creating it if needed
+func uploadStateOf(token string) *fileUploadState {
+	state, _ := fileUploadStates.LoadOrStore(token, &fileUploadState{})
+
+	return state.(*fileUploadState)
+}
+
+// Forget A function to drop the upload state of a file once it's completed or removed
+func Forget(token string) {
+	fileUploadStates.Delete(token)
+}
+
+// Reserve A function to reserve a chunk range of a file, it fails if the chunk overlaps a received range
+// or a range being written, the persisted received ranges are returned in both cases
+func Reserve(token string, chunk datanode.ByteRange) (datanode.ByteRanges, error) {
+	state := uploadStateOf(token)
+	state.Lock()
+	defer state.Unlock()
+
+	var fileInfo datanode.File
+	err := datanode.NodeInstance().DB.Connection.Select("received_ranges").Where("token = ?", token).Find(&fileInfo).Error
+	if errors.IsError(err) {
+		return nil, err
+	}
+
+	receivedRanges, err := datanode.DecodeByteRanges(fileInfo.ReceivedRanges)
+	if errors.IsError(err) {
+		return nil, err
+	}
+
+	if receivedRanges.Overlaps(chunk) || state.inFlight.Overlaps(chunk) {
+		return receivedRanges, errors.New(fmt.Sprintf("Chunk %d-%d overlaps an already received chunk", chunk.Start, chunk.End))
+	}
+
+	state.inFlight = append(state.inFlight, chunk)
+
+	return receivedRanges, nil
+}
+
+// Release A function to release a reserved chunk range, the state of a completed file is already dropped
+func Release(token string, chunk datanode.ByteRange) {
+	value, ok := fileUploadStates.Load(token)
+	if !ok {
+		return
+	}
+
+	state := value.(*fileUploadState)
+	state.Lock()
+	defer state.Unlock()
+
+	state.inFlight = state.inFlight.Remove(chunk)
+}
@@ -0,0 +1,363 @@
+package upload
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/SayedAlesawy/Videra-Storage/config"
+	datanode "github.com/SayedAlesawy/Videra-Storage/data_node"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/ingest"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/storage"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/stream"
+	"github.com/SayedAlesawy/Videra-Storage/data_node/thumbnail"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+// for logging hierarchy
+var uploadLogPrefix = "[Upload]"
+
+// ModelUploadOrder represents the order in which model files will be uploaded
+var ModelUploadOrder = [...]string{"model", "config", "code"}
+
+// ErrFileChecksumMismatch Indicates that the whole file digest didn't match the expected one
+var ErrFileChecksumMismatch = errors.New("File checksum mismatch")
+
+// ErrChunkSpansParts Indicates that a model chunk lands in more than one of the model sub-files
+var ErrChunkSpansParts = errors.New("Chunk can't span multiple model files")
+
+// NewVideo A function to build the info record of a new video, blobs are stored under
+// server generated names and the original filename is only kept as metadata
+func NewVideo(token string, parent string, name string, size int64, expectedChecksum string, associatedModel string) datanode.File {
+	metadata := datanode.VideoMetadata{}
+	metadata.AssociatedModel = associatedModel
+	metadataJSON, _ := json.Marshal(metadata)
+
+	return datanode.File{
+		Token:            token,
+		Name:             name,
+		Type:             datanode.VideoFileType,
+		Path:             storage.VideoPath(token),
+		Size:             size,
+		Extras:           string(metadataJSON),
+		DataNodeID:       datanode.NodeInstance().ID,
+		Parent:           parent,
+		Offset:           0,
+		ExpectedChecksum: strings.ToLower(expectedChecksum),
+	}
+}
+
+// NewModel A function to build the info record of a new model, along with its config and code files
+func NewModel(token string, parent string, name string, modelSize int64, configSize int64, codeSize int64, expectedChecksum string) datanode.File {
+	extras := datanode.ModelExtras{
+		ModelSize:            modelSize,
+		AssociatedConfigPath: storage.ModelConfigPath(token),
+		AssociatedConfigSize: configSize,
+		AssociatedCodePath:   storage.ModelCodePath(token),
+		AssociatedCodeSize:   codeSize,
+	}
+	extrasBytes, _ := json.Marshal(extras)
+
+	return datanode.File{
+		Token:            token,
+		Name:             name,
+		Type:             datanode.ModelFileType,
+		Path:             storage.ModelPath(token),
+		Extras:           string(extrasBytes),
+		Size:             modelSize + configSize + codeSize,
+		DataNodeID:       datanode.NodeInstance().ID,
+		Parent:           parent,
+		Offset:           0,
+		ExpectedChecksum: strings.ToLower(expectedChecksum),
+	}
+}
+
+// Create A function to create the blobs of a new file on disk and insert its info record in the database
+func Create(fileInfo *datanode.File) error {
+	log.Println(uploadLogPrefix, "creating file with id", fileInfo.Token)
+
+	err := datanode.CreateFileDirectory(storage.FileFolder(fileInfo.Token), 0744)
+	if errors.IsError(err) {
+		return err
+	}
+
+	paths, err := FilePaths(*fileInfo)
+	if errors.IsError(err) {
+		return err
+	}
+
+	for _, path := range paths {
+		err := datanode.CreateFile(path)
+		if errors.IsError(err) {
+			return err
+		}
+	}
+
+	//Insert a file info record in the database
+	return datanode.NodeInstance().DB.Connection.Create(fileInfo).Error
+}
+
+// IsComplete A function to check if file upload was completed previously
+func IsComplete(fileInfo datanode.File) bool {
+	return fileInfo.CompletedAt != nil
+}
+
+// IsReplica checks if the given file is an original file or replica
+func IsReplica(fileInfo datanode.File) bool {
+	return fileInfo.Token != fileInfo.Parent
+}
+
+// ValidOffset A function to validate that a chunk lands inside the file
+func ValidOffset(fileInfo datanode.File, offset int64, chunkSize int64) bool {
+	return offset >= 0 && offset+chunkSize <= fileInfo.Size
+}
+
+// GetModelPart A function to get the model sub-file (model, config or code) in which
+// the given offset lands, along with its path and the offset relative to that sub-file
+func GetModelPart(fileInfo datanode.File, modelExtras datanode.ModelExtras, offset int64) (string, string, int64) {
+	// ****Model****/**Config**/*Code*/
+	if offset < modelExtras.ModelSize {
+		return ModelUploadOrder[0], fileInfo.Path, offset
+	}
+
+	// chunk is either belongs to config file or code file
+	if offset < modelExtras.ModelSize+modelExtras.AssociatedConfigSize {
+		return ModelUploadOrder[1], modelExtras.AssociatedConfigPath, offset - modelExtras.ModelSize
+	}
+
+	return ModelUploadOrder[2], modelExtras.AssociatedCodePath, offset - modelExtras.ModelSize - modelExtras.AssociatedConfigSize
+}
+
+// ChunkPath A function to get the path of the blob a chunk is written into, along with the offset relative to it,
+// in video file it's the same as the chunk offset, but in model it's relative to the sub-file being written,
+// for example offset maybe 500 but it will write at offset 0 of config file
+func ChunkPath(fileInfo datanode.File, offset int64, chunkSize int64) (string, int64, error) {
+	if fileInfo.Type != datanode.ModelFileType {
+		return fileInfo.Path, offset, nil
+	}
+
+	var modelExtras datanode.ModelExtras
+	err := json.Unmarshal([]byte(fileInfo.Extras), &modelExtras)
+	if errors.IsError(err) {
+		return "", 0, err
+	}
+
+	part, filePath, writeOffset := GetModelPart(fileInfo, modelExtras, offset)
+	if lastPart, _, _ := GetModelPart(fileInfo, modelExtras, offset+chunkSize-1); lastPart != part {
+		log.Println(uploadLogPrefix, fmt.Sprintf("Chunk at offset %d spans the %s and %s files", offset, part, lastPart))
+		return "", 0, ErrChunkSpansParts
+	}
+
+	return filePath, writeOffset, nil
+}
+
+// OffsetWriter Writes sequentially into a file starting at a given offset
+type OffsetWriter struct {
+	file   *os.File //File to write into
+	offset int64    //Offset at which the next write happens
+}
+
+// NewOffsetWriter A function to obtain a writer into a file starting at the given offset
+func NewOffsetWriter(file *os.File, offset int64) *OffsetWriter {
+	return &OffsetWriter{file: file, offset: offset}
+}
+
+// Write Writes the data at the current offset and advances it
+func (writer *OffsetWriter) Write(data []byte) (int, error) {
+	n, err := writer.file.WriteAt(data, writer.offset)
+	writer.offset += int64(n)
+
+	return n, err
+}
+
+// CommitChunk A function to record a written chunk in the file's received ranges,
+// the file is completed exactly once, by the chunk that makes the ranges cover the whole file
+func CommitChunk(token string, chunk datanode.ByteRange) (datanode.File, bool, error) {
+	uploadState := uploadStateOf(token)
+	uploadState.Lock()
+	defer uploadState.Unlock()
+
+	var fileInfo datanode.File
+	err := datanode.NodeInstance().DB.Connection.Where("token = ?", token).Find(&fileInfo).Error
+	if errors.IsError(err) {
+		return fileInfo, false, err
+	}
+
+	receivedRanges, err := datanode.DecodeByteRanges(fileInfo.ReceivedRanges)
+	if errors.IsError(err) {
+		return fileInfo, false, err
+	}
+
+	receivedRanges = receivedRanges.Insert(chunk)
+	fileInfo.ReceivedRanges = receivedRanges.Encode()
+	fileInfo.Offset = receivedRanges.ContiguousEnd()
+
+	completed := receivedRanges.Covers(fileInfo.Size) && !IsComplete(fileInfo)
+	if completed {
+		err := verifyFileChecksum(&fileInfo)
+		if errors.IsError(err) {
+			log.Println(uploadLogPrefix, err)
+
+			// the corrupted bytes can't be located, so the upload has to start over
+			fileInfo.ReceivedRanges = ""
+			fileInfo.Offset = 0
+			err = datanode.NodeInstance().DB.Connection.Save(&fileInfo).Error
+			if errors.IsError(err) {
+				return fileInfo, false, err
+			}
+
+			return fileInfo, false, ErrFileChecksumMismatch
+		}
+
+		now := time.Now()
+		fileInfo.CompletedAt = &now
+
+		if fileInfo.Type == datanode.VideoFileType {
+			var videoMetadata datanode.VideoMetadata
+			json.Unmarshal([]byte(fileInfo.Extras), &videoMetadata)
+			err := fetchVideoMetaData(fileInfo, &videoMetadata)
+			if errors.IsError(err) {
+				return fileInfo, false, err
+			}
+			metaData, _ := json.Marshal(videoMetadata)
+			fileInfo.Extras = string(metaData)
+		}
+	}
+
+	err = datanode.NodeInstance().DB.Connection.Save(&fileInfo).Error
+	if errors.IsError(err) {
+		return fileInfo, false, err
+	}
+
+	if completed {
+		Forget(token)
+	}
+
+	return fileInfo, completed, nil
+}
+
+// RefreshChecksum A function to recompute the whole file digest of a completed file whose bytes were rewritten
+func RefreshChecksum(token string) (datanode.File, error) {
+	uploadState := uploadStateOf(token)
+	uploadState.Lock()
+	defer uploadState.Unlock()
+
+	var fileInfo datanode.File
+	err := datanode.NodeInstance().DB.Connection.Where("token = ?", token).Find(&fileInfo).Error
+	if errors.IsError(err) {
+		return fileInfo, err
+	}
+
+	paths, err := FilePaths(fileInfo)
+	if errors.IsError(err) {
+		return fileInfo, err
+	}
+
+	fileInfo.Checksum, err = datanode.ComputeFileChecksum(paths...)
+	if errors.IsError(err) {
+		return fileInfo, err
+	}
+
+	err = datanode.NodeInstance().DB.Connection.Model(&fileInfo).Update("checksum", fileInfo.Checksum).Error
+
+	return fileInfo, err
+}
+
+// StartProcessing A function to start processing a completed file, original videos are ingested,
+// while replicas only prepare the thumbnail and the streaming segments
+func StartProcessing(fileInfo datanode.File) {
+	if fileInfo.Type != datanode.VideoFileType {
+		return
+	}
+
+	if !IsReplica(fileInfo) {
+		go ingest.StartJob(fileInfo)
+		return
+	}
+
+	go func() {
+		thumbnail.PrepareThumbnail(fileInfo)
+		stream.PrepareStreamingVideo(fileInfo)
+	}()
+}
+
+// FilePaths A function to get the paths of the blobs making up a file, in upload order
+func FilePaths(fileInfo datanode.File) ([]string, error) {
+	paths := []string{fileInfo.Path}
+
+	if fileInfo.Type == datanode.ModelFileType {
+		var modelExtras datanode.ModelExtras
+		err := json.Unmarshal([]byte(fileInfo.Extras), &modelExtras)
+		if errors.IsError(err) {
+			return nil, err
+		}
+
+		paths = append(paths, modelExtras.AssociatedConfigPath, modelExtras.AssociatedCodePath)
+	}
+
+	return paths, nil
+}
+
+// verifyFileChecksum A function to compute the whole file digest and verify it against the one sent on init
+func verifyFileChecksum(fileInfo *datanode.File) error {
+	paths, err := FilePaths(*fileInfo)
+	if errors.IsError(err) {
+		return err
+	}
+
+	checksum, err := datanode.ComputeFileChecksum(paths...)
+	if errors.IsError(err) {
+		return err
+	}
+
+	if fileInfo.ExpectedChecksum != "" && !strings.EqualFold(fileInfo.ExpectedChecksum, checksum) {
+		return errors.New(fmt.Sprintf("Checksum mismatch for file %s, expected %s found %s", fileInfo.Token, fileInfo.ExpectedChecksum, checksum))
+	}
+
+	fileInfo.Checksum = checksum
+
+	return nil
+}
+
+// fetchVideoMetaData is a function responsible of retrieving metadata from video file
+func fetchVideoMetaData(fileInfo datanode.File, extras *datanode.VideoMetadata) error {
+	dataNodeConfig := config.ConfigurationManagerInstance("").DataNodeConfig()
+
+	//temp file to save metadata
+	tempFile, err := ioutil.TempFile("", "*_metadata.txt")
+	if err != nil {
+		return err
+	}
+	tempFile.Close()
+	defer os.Remove(tempFile.Name()) //remove the temp file at the end
+
+	command := dataNodeConfig.MetadataCommand
+	script := dataNodeConfig.MetadataScriptPath
+	inputFile := fileInfo.Path
+	outputFile := tempFile.Name()
+
+	cmd := exec.Command(command, script, "-i", inputFile, "-o", outputFile)
+	err = cmd.Run()
+	if errors.IsError(err) {
+		return err
+	}
+	cmd.Wait()
+
+	content, err := ioutil.ReadFile(outputFile)
+	if errors.IsError(err) {
+		return err
+	}
+	err = json.Unmarshal(content, extras)
+	if errors.IsError(err) {
+		return err
+	}
+	if extras.FramesCount == 0 {
+		return errors.New("Error parsing file")
+	}
+	return nil
+}
@@ -2,8 +2,16 @@ package datanode
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"log"
+	"net"
 	"os"
+	"path"
+	"strings"
+	"syscall"
 
 	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
 )
@@ -27,6 +35,51 @@ func CreateFile(filepath string) error {
 	return nil
 }
 
+// RemoveFile A function to remove a file folder from disk along with its HLS segments,
+// thumbnail and info record
+func (dataNode *DataNode) RemoveFile(fileInfo File) error {
+	err := os.RemoveAll(path.Dir(fileInfo.Path))
+	if errors.IsError(err) {
+		return err
+	}
+
+	if fileInfo.HLSPath != "" {
+		err := os.RemoveAll(path.Dir(fileInfo.HLSPath))
+		if errors.IsError(err) {
+			return err
+		}
+	}
+
+	if fileInfo.ThumbnailPath != "" {
+		err := os.Remove(fileInfo.ThumbnailPath)
+		if errors.IsError(err) && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return dataNode.DB.Connection.Unscoped().Delete(&fileInfo).Error
+}
+
+// ComputeFileChecksum computes the SHA-256 digest (hex) of the concatenation of the given files
+func ComputeFileChecksum(filePaths ...string) (string, error) {
+	hash := sha256.New()
+
+	for _, filePath := range filePaths {
+		file, err := os.Open(filePath)
+		if errors.IsError(err) {
+			return "", err
+		}
+
+		_, err = io.Copy(hash, file)
+		file.Close()
+		if errors.IsError(err) {
+			return "", err
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
 // GenerateRandomString generates random string with length 2*n
 func GenerateRandomString(n int) string {
 	b := make([]byte, n)
@@ -34,11 +87,49 @@ func GenerateRandomString(n int) string {
 	return fmt.Sprintf("%x", b)
 }
 
-// getNameNodeAddress A function to get the name node address
+// getNameNodeAddress A function to get the address of the current name node
 func (dataNode *DataNode) getNameNodeAddress() string {
+	dataNode.nameNodeLock.RLock()
+	defer dataNode.nameNodeLock.RUnlock()
+
 	return fmt.Sprintf("%s:%s", dataNode.NameNode.IP, dataNode.NameNode.Port)
 }
 
+// failOverNameNode A function to switch to the leader name node if its address is known,
+// otherwise to the name node following the current one in the list of name nodes
+func (dataNode *DataNode) failOverNameNode(leaderAddress string) {
+	dataNode.nameNodeLock.Lock()
+	defer dataNode.nameNodeLock.Unlock()
+
+	if ip, port, err := net.SplitHostPort(leaderAddress); !errors.IsError(err) && ip != "" && port != "" {
+		dataNode.NameNode = NameNodeData{IP: ip, Port: port}
+	} else {
+		next := 0
+		for idx, nameNode := range dataNode.NameNodes {
+			if nameNode == dataNode.NameNode {
+				next = (idx + 1) % len(dataNode.NameNodes)
+			}
+		}
+		dataNode.NameNode = dataNode.NameNodes[next]
+	}
+
+	log.Println(logPrefix, fmt.Sprintf("Failing over to name node on %s:%s", dataNode.NameNode.IP, dataNode.NameNode.Port))
+}
+
+// parseNameNodes A function to parse the comma separated internal addresses of the name nodes, skipping malformed ones
+func parseNameNodes(addresses string) []NameNodeData {
+	var nameNodes []NameNodeData
+
+	for _, address := range strings.Split(addresses, ",") {
+		ip, port, err := net.SplitHostPort(strings.TrimSpace(address))
+		if !errors.IsError(err) {
+			nameNodes = append(nameNodes, NameNodeData{IP: ip, Port: port})
+		}
+	}
+
+	return nameNodes
+}
+
 // checkGPUStatus A function to check the GPU status of a data node
 func checkGPUStatus(status string) bool {
 	if status == "true" {
@@ -47,3 +138,26 @@ func checkGPUStatus(status string) bool {
 
 	return false
 }
+
+// DiskUsage A function to get the free and total bytes of the disk holding the given path,
+// a path that doesn't exist yet is resolved to its closest existing parent
+func DiskUsage(diskPath string) (uint64, uint64, error) {
+	var stat syscall.Statfs_t
+
+	for {
+		err := syscall.Statfs(diskPath, &stat)
+		if err == nil {
+			break
+		}
+
+		parent := path.Dir(diskPath)
+		if !os.IsNotExist(err) || parent == diskPath {
+			return 0, 0, err
+		}
+		diskPath = parent
+	}
+
+	blockSize := uint64(stat.Bsize)
+
+	return stat.Bavail * blockSize, stat.Blocks * blockSize, nil
+}
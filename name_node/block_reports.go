@@ -0,0 +1,249 @@
+package namenode
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+const (
+	//DiscrepancyMissing represents a copy recorded for a data node but not found on its disk
+	DiscrepancyMissing string = "missing"
+	//DiscrepancyExtra represents a copy found on the disk of a data node but not recorded for it
+	DiscrepancyExtra string = "extra"
+	//DiscrepancyStale represents a copy whose record disagrees with what's on the disk of its data node
+	DiscrepancyStale string = "stale"
+)
+
+// Discrepancy Represents a mismatch between the block report of a data node and the files recorded for it
+type Discrepancy struct {
+	DataNodeID string    `json:"data_node_id"` //ID of the data node that sent the block report
+	Token      string    `json:"token"`        //Token of the file copy
+	Parent     string    `json:"parent"`       //Token of the original file, empty if the copy isn't recorded
+	Kind       string    `json:"kind"`         //Kind of the discrepancy (missing, extra, stale)
+	Detail     string    `json:"detail"`       //Description of how the record and the disk disagree
+	DetectedAt time.Time `json:"detected_at"`  //Time at which the block report was received
+}
+
+// FileLocation Represents a copy of a file as last reported by the data node holding it
+type FileLocation struct {
+	DataNodeID string    `json:"data_node_id"` //ID of the data node holding the copy
+	Token      string    `json:"token"`        //Token of the file copy
+	Size       int64     `json:"size"`         //Size of the copy on disk in bytes
+	Completed  bool      `json:"completed"`    //Indicates if the copy completed uploading
+	Stream     bool      `json:"stream"`       //Indicates if the HLS playlist of the file is on disk
+	Thumbnail  bool      `json:"thumbnail"`    //Indicates if the thumbnail of the file is on disk
+	ReportedAt time.Time `json:"reported_at"`  //Time at which the copy was last reported
+}
+
+// ProcessBlockReport A function to process the block report of a data node, listing the copies found on its disk,
+// it replaces the inventory of the node, updates the location index, and returns the discrepancies
+// between the report and the files recorded for the node
+func (nameNode *NameNode) ProcessBlockReport(id string, report []InventoryEntry) ([]Discrepancy, error) {
+	previous, err := nameNode.GetInventory(id)
+	if errors.IsError(err) {
+		return nil, err
+	}
+
+	err = nameNode.SaveInventory(id, report)
+	if errors.IsError(err) {
+		return nil, err
+	}
+
+	now := time.Now()
+	nameNode.updateLocations(id, previous, report, now)
+
+	records, err := nameNode.GetFileCopiesHeldBy(id)
+	if errors.IsError(err) {
+		return nil, err
+	}
+
+	discrepancies := findDiscrepancies(id, records, report, now)
+	if len(discrepancies) != 0 {
+		log.Println(logPrefix, fmt.Sprintf("Block report of data node %s has %d discrepancies", id, len(discrepancies)))
+	}
+
+	encodedDiscrepancies, err := json.Marshal(discrepancies)
+	if errors.IsError(err) {
+		return nil, err
+	}
+
+	err = nameNode.insertIntoHash(nameNode.discrepanciesKey, id, string(encodedDiscrepancies))
+
+	return discrepancies, err
+}
+
+// GetDiscrepancies A function to get the discrepancies found in the last block report of each data node, by node ID
+func (nameNode *NameNode) GetDiscrepancies() map[string][]Discrepancy {
+	discrepancies := make(map[string][]Discrepancy)
+
+	encodedReports, err := nameNode.getAllFromHash(nameNode.discrepanciesKey)
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to fetch block report discrepancies from redis")
+
+		return discrepancies
+	}
+
+	for id, encodedDiscrepancies := range encodedReports {
+		var nodeDiscrepancies []Discrepancy
+
+		err := json.Unmarshal([]byte(encodedDiscrepancies), &nodeDiscrepancies)
+		if errors.IsError(err) {
+			log.Println(logPrefix, "Unable to decode block report discrepancies", encodedDiscrepancies)
+
+			continue
+		}
+
+		if len(nodeDiscrepancies) != 0 {
+			discrepancies[id] = nodeDiscrepancies
+		}
+	}
+
+	return discrepancies
+}
+
+// GetFileLocations A function to get the copies of a file reported by the data nodes, ordered by data node ID
+func (nameNode *NameNode) GetFileLocations(token string) ([]FileLocation, error) {
+	locations := []FileLocation{}
+
+	encodedLocations, err := nameNode.getAllFromHash(nameNode.getLocationsKey(token))
+	if errors.IsError(err) {
+		return locations, err
+	}
+
+	for _, encodedLocation := range encodedLocations {
+		var location FileLocation
+
+		err := json.Unmarshal([]byte(encodedLocation), &location)
+		if errors.IsError(err) {
+			log.Println(logPrefix, "Unable to decode file location", encodedLocation)
+
+			continue
+		}
+
+		locations = append(locations, location)
+	}
+
+	sort.Slice(locations, func(i, j int) bool {
+		return locations[i].DataNodeID < locations[j].DataNodeID
+	})
+
+	return locations, nil
+}
+
+// updateLocations A function to record the data node as a location of the files in its block report,
+// and remove it from the files it no longer reports
+func (nameNode *NameNode) updateLocations(id string, previous []InventoryEntry, report []InventoryEntry, reportedAt time.Time) {
+	reported := make(map[string]bool)
+
+	for _, entry := range report {
+		reported[locatedFile(entry)] = true
+
+		encodedLocation, err := json.Marshal(FileLocation{
+			DataNodeID: id,
+			Token:      entry.Token,
+			Size:       entry.Size,
+			Completed:  entry.Completed,
+			Stream:     entry.Stream,
+			Thumbnail:  entry.Thumbnail,
+			ReportedAt: reportedAt,
+		})
+		if errors.IsError(err) {
+			log.Println(logPrefix, "Unable to marshal file location", err)
+			continue
+		}
+
+		err = nameNode.insertIntoHash(nameNode.getLocationsKey(locatedFile(entry)), id, string(encodedLocation))
+		if errors.IsError(err) {
+			log.Println(logPrefix, fmt.Sprintf("Unable to record location of file %s on data node %s", entry.Token, id), err)
+		}
+	}
+
+	for _, entry := range previous {
+		if reported[locatedFile(entry)] {
+			continue
+		}
+
+		err := nameNode.deleteFromHash(nameNode.getLocationsKey(locatedFile(entry)), id)
+		if errors.IsError(err) {
+			log.Println(logPrefix, fmt.Sprintf("Unable to remove location of file %s on data node %s", entry.Token, id), err)
+		}
+	}
+}
+
+// getLocationsKey A function to get the key of the redis hash tracking the data nodes holding a file
+func (nameNode *NameNode) getLocationsKey(token string) string {
+	return fmt.Sprintf("%s:%s", nameNode.locationsKeyPrefix, token)
+}
+
+// locatedFile A function to get the token a reported copy is indexed by, which is its original,
+// copies that aren't recorded have no known original, so they're indexed by their own token
+func locatedFile(entry InventoryEntry) string {
+	if entry.Parent != "" {
+		return entry.Parent
+	}
+
+	return entry.Token
+}
+
+// findDiscrepancies A function to compare the copies recorded for a data node against its block report,
+// a completed copy is stale if its size on disk, its checksum as of the last scrub, or its stream or thumbnail
+// on disk disagree with its record
+func findDiscrepancies(id string, records []FileInfo, report []InventoryEntry, detectedAt time.Time) []Discrepancy {
+	discrepancies := []Discrepancy{}
+	add := func(token string, parent string, kind string, detail string) {
+		discrepancies = append(discrepancies, Discrepancy{
+			DataNodeID: id,
+			Token:      token,
+			Parent:     parent,
+			Kind:       kind,
+			Detail:     detail,
+			DetectedAt: detectedAt,
+		})
+	}
+
+	reported := make(map[string]InventoryEntry)
+	for _, entry := range report {
+		reported[entry.Token] = entry
+	}
+
+	recorded := make(map[string]bool)
+	for _, record := range records {
+		recorded[record.Token] = true
+
+		entry, found := reported[record.Token]
+		if !found {
+			add(record.Token, record.Parent, DiscrepancyMissing, "Recorded copy is not on disk")
+			continue
+		}
+
+		if record.CompletedAt == nil {
+			continue
+		}
+
+		// a copy that isn't completed on disk has a size that differs from the recorded one
+		switch {
+		case entry.Size != record.Size:
+			add(record.Token, record.Parent, DiscrepancyStale,
+				fmt.Sprintf("Recorded size is %d bytes, size on disk is %d bytes", record.Size, entry.Size))
+		case entry.Checksum != "" && record.Checksum != "" && entry.Checksum != record.Checksum:
+			add(record.Token, record.Parent, DiscrepancyStale, "Recorded checksum differs from the one computed by the last scrub")
+		case record.HLSPath != "" && !entry.Stream:
+			add(record.Token, record.Parent, DiscrepancyStale, "Recorded HLS stream is not on disk")
+		case record.ThumbnailPath != "" && !entry.Thumbnail:
+			add(record.Token, record.Parent, DiscrepancyStale, "Recorded thumbnail is not on disk")
+		}
+	}
+
+	for _, entry := range report {
+		if !recorded[entry.Token] {
+			add(entry.Token, entry.Parent, DiscrepancyExtra, "Copy on disk is not recorded for the data node")
+		}
+	}
+
+	return discrepancies
+}
@@ -27,6 +27,20 @@ func (nameNode *NameNode) InsertDataNodeData(dataNodeData DataNodeData) bool {
 	return true
 }
 
+// UpdateDataNodeData A function to update the data of a tracked data node, which is read again right before writing,
+// so the fields updated in the meantime by others are kept, a data node that's no longer tracked isn't inserted back,
+// the second return value is false if the data node isn't tracked or couldn't be written
+func (nameNode *NameNode) UpdateDataNodeData(id string, update func(dataNodeData *DataNodeData)) (DataNodeData, bool) {
+	dataNodeData, found := nameNode.GetDataNodeData(id)
+	if !found {
+		return dataNodeData, false
+	}
+
+	update(&dataNodeData)
+
+	return dataNodeData, nameNode.InsertDataNodeData(dataNodeData)
+}
+
 // RemoveDataNodeData A function to remove data node data from active nodes hash
 func (nameNode *NameNode) RemoveDataNodeData(dataNodeData DataNodeData) {
 	err := nameNode.deleteFromHash(nameNode.dataNodesTrackingKey, dataNodeData.ID)
@@ -61,6 +75,24 @@ func (nameNode *NameNode) GetAllDataNodeData() []DataNodeData {
 	return dataNodes
 }
 
+// GetDataNodeData A function to get the data of a data node from active hash,
+// the second return value is false if the data node isn't tracked
+func (nameNode *NameNode) GetDataNodeData(id string) (DataNodeData, bool) {
+	node, err := nameNode.getFromHash(nameNode.dataNodesTrackingKey, id)
+	if errors.IsError(err) {
+		return DataNodeData{}, false
+	}
+
+	decodedNode, err := nameNode.decodeDataNodeData(node)
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to decode data node data", node)
+
+		return DataNodeData{}, false
+	}
+
+	return decodedNode, true
+}
+
 // insertIntoHash A function to insert a new entry in a redis hash
 func (nameNode *NameNode) insertIntoHash(key string, field string, value string) error {
 	return nameNode.cache.HSet(key, field, value).Err()
@@ -75,3 +107,13 @@ func (nameNode *NameNode) deleteFromHash(key string, field ...string) error {
 func (nameNode *NameNode) getAllFromHash(key string) (map[string]string, error) {
 	return nameNode.cache.HGetAll(key).Result()
 }
+
+// getFromHash A function to get a field from a redis hash
+func (nameNode *NameNode) getFromHash(key string, field string) (string, error) {
+	return nameNode.cache.HGet(key, field).Result()
+}
+
+// incrementHashField A function to increment a counter field in a redis hash
+func (nameNode *NameNode) incrementHashField(key string, field string, increment int64) error {
+	return nameNode.cache.HIncrBy(key, field, increment).Err()
+}
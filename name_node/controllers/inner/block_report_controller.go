@@ -0,0 +1,68 @@
+package inner
+
+import (
+	context "context"
+	"fmt"
+	"log"
+
+	namenode "github.com/SayedAlesawy/Videra-Storage/name_node"
+	"github.com/SayedAlesawy/Videra-Storage/name_node/nnpb"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+// ReportBlocks Handles the periodic block report of a data node, updating the location index
+// and detecting the copies that are missing, extra or stale compared to the files recorded for the node,
+// the files deleted while the node was offline are deleted from it
+func (server *Server) ReportBlocks(ctx context.Context, req *nnpb.BlockReportRequest) (*nnpb.BlockReportResponse, error) {
+	log.Println(logPrefix, fmt.Sprintf("Received block report of %d copies from node: %s", len(req.Inventory), req.DataNodeID))
+
+	nameNode := namenode.NodeInstance()
+	if !nameNode.IsLeader() {
+		res := nnpb.BlockReportResponse{Status: nnpb.BlockReportResponse_NOT_LEADER}
+		if leader, err := nameNode.GetLeader(); !errors.IsError(err) {
+			res.LeaderAddress = leader.InternalAddress()
+		}
+
+		return &res, nil
+	}
+
+	dataNode, found := nameNode.GetDataNodeData(req.DataNodeID)
+	if !found {
+		return &nnpb.BlockReportResponse{Status: nnpb.BlockReportResponse_UNKNOWN_NODE}, nil
+	}
+
+	var inventory []namenode.InventoryEntry
+	for _, entry := range req.Inventory {
+		inventory = append(inventory, namenode.InventoryEntry{
+			Token:     entry.Token,
+			Parent:    entry.Parent,
+			Checksum:  entry.Checksum,
+			Size:      entry.Size,
+			Completed: entry.Completed,
+			Stream:    entry.Stream,
+			Thumbnail: entry.Thumbnail,
+		})
+	}
+
+	discrepancies, err := nameNode.ProcessBlockReport(req.DataNodeID, inventory)
+	if errors.IsError(err) {
+		log.Println(logPrefix, fmt.Sprintf("Unable to process block report of node: %s", req.DataNodeID), err)
+		return &nnpb.BlockReportResponse{Status: nnpb.BlockReportResponse_FAILURE}, nil
+	}
+
+	go nameNode.ProcessPendingDeletions(dataNode)
+
+	res := nnpb.BlockReportResponse{Status: nnpb.BlockReportResponse_SUCCESS}
+	for _, discrepancy := range discrepancies {
+		switch discrepancy.Kind {
+		case namenode.DiscrepancyMissing:
+			res.MissingCount++
+		case namenode.DiscrepancyExtra:
+			res.ExtraCount++
+		case namenode.DiscrepancyStale:
+			res.StaleCount++
+		}
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,40 @@
+package inner
+
+import (
+	context "context"
+
+	namenode "github.com/SayedAlesawy/Videra-Storage/name_node"
+	"github.com/SayedAlesawy/Videra-Storage/name_node/nnpb"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+// Heartbeat Handles the heartbeat of a data node, recording its capacity and load,
+// a data node the name node doesn't track is asked to join the cluster again
+func (server *Server) Heartbeat(ctx context.Context, req *nnpb.HeartbeatRequest) (*nnpb.HeartbeatResponse, error) {
+	nameNode := namenode.NodeInstance()
+	if !nameNode.IsLeader() {
+		res := nnpb.HeartbeatResponse{Status: nnpb.HeartbeatResponse_NOT_LEADER}
+		if leader, err := nameNode.GetLeader(); !errors.IsError(err) {
+			res.LeaderAddress = leader.InternalAddress()
+		}
+
+		return &res, nil
+	}
+
+	known := nameNode.RecordHeartbeat(req.DataNodeID, req.Healthy, namenode.NodeLoad{
+		FreeDisk:        req.FreeDisk,
+		UsedDisk:        req.UsedDisk,
+		TotalDisk:       req.TotalDisk,
+		FileCount:       req.FileCount,
+		RunningJobs:     int(req.RunningJobs),
+		QueuedJobs:      int(req.QueuedJobs),
+		InFlightUploads: int(req.InFlightUploads),
+		GPU:             req.GPU,
+		GPUAvailable:    req.GPUAvailable,
+	})
+	if !known {
+		return &nnpb.HeartbeatResponse{Status: nnpb.HeartbeatResponse_UNKNOWN_NODE}, nil
+	}
+
+	return &nnpb.HeartbeatResponse{Status: nnpb.HeartbeatResponse_ACKNOWLEDGED}, nil
+}
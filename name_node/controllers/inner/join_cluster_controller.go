@@ -7,18 +7,58 @@ import (
 
 	namenode "github.com/SayedAlesawy/Videra-Storage/name_node"
 	"github.com/SayedAlesawy/Videra-Storage/name_node/nnpb"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
 )
 
-// JoinCluster Handles the join cluster request
+// JoinCluster Handles the join cluster request, the data node sends a block report of the file copies on its disk,
+// which replaces the one recorded for it, so a data node re-registering brings its inventory up to date,
+// and gets the files deleted while it was offline deleted
 func (server *Server) JoinCluster(ctx context.Context, req *nnpb.JoinClusterRequest) (*nnpb.JoinClusterResponse, error) {
 	log.Println(logPrefix, fmt.Sprintf("Received join cluster from node: %s on %s:%s", req.ID, req.IP, req.InternalPort))
 
+	nameNode := namenode.NodeInstance()
+	if !nameNode.IsLeader() {
+		res := nnpb.JoinClusterResponse{Status: nnpb.JoinClusterResponse_NOT_LEADER}
+		if leader, err := nameNode.GetLeader(); !errors.IsError(err) {
+			res.LeaderAddress = leader.InternalAddress()
+		}
+
+		return &res, nil
+	}
+
 	dataNodeData := namenode.NewDataNodeData(req.ID, req.IP, req.InternalPort, req.Port, req.GPU)
+	dataNodeData.Zone = req.Zone
+	dataNodeData.Rack = req.Rack
+	dataNodeData.DiskClass = req.DiskClass
+	dataNodeData.FreeDisk = req.FreeDisk
+	dataNodeData.TotalDisk = req.TotalDisk
+	dataNodeData.FileCount = int64(len(req.Inventory))
+
+	var inventory []namenode.InventoryEntry
+	for _, entry := range req.Inventory {
+		inventory = append(inventory, namenode.InventoryEntry{
+			Token:     entry.Token,
+			Parent:    entry.Parent,
+			Checksum:  entry.Checksum,
+			Size:      entry.Size,
+			Completed: entry.Completed,
+			Stream:    entry.Stream,
+			Thumbnail: entry.Thumbnail,
+		})
+	}
+
+	_, err := nameNode.ProcessBlockReport(req.ID, inventory)
+	if errors.IsError(err) {
+		log.Println(logPrefix, fmt.Sprintf("Unable to process block report of node: %s", req.ID), err)
+		return &nnpb.JoinClusterResponse{Status: nnpb.JoinClusterResponse_FAILURE}, nil
+	}
 
-	ok := namenode.NodeInstance().InsertDataNodeData(dataNodeData)
+	ok := nameNode.InsertDataNodeData(dataNodeData)
 	var status nnpb.JoinClusterResponse_JoinStatus
 	if ok {
 		status = nnpb.JoinClusterResponse_SUCCESS
+		// the files deleted while the node was offline are deleted from it once it serves again
+		go nameNode.ProcessPendingDeletions(dataNodeData)
 	} else {
 		status = nnpb.JoinClusterResponse_FAILURE
 	}
@@ -0,0 +1,43 @@
+package inner
+
+import (
+	context "context"
+	"fmt"
+	"log"
+
+	namenode "github.com/SayedAlesawy/Videra-Storage/name_node"
+	"github.com/SayedAlesawy/Videra-Storage/name_node/nnpb"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+// ReportChecksums Handles the checksums reported by the scrubber of a data node,
+// comparing them against the originals and repairing the diverged copies
+func (server *Server) ReportChecksums(ctx context.Context, req *nnpb.ReportChecksumsRequest) (*nnpb.ReportChecksumsResponse, error) {
+	log.Println(logPrefix, fmt.Sprintf("Received %d checksums from node: %s", len(req.Checksums), req.DataNodeID))
+
+	nameNode := namenode.NodeInstance()
+	if !nameNode.IsLeader() {
+		res := nnpb.ReportChecksumsResponse{Status: nnpb.ReportChecksumsResponse_NOT_LEADER}
+		if leader, err := nameNode.GetLeader(); !errors.IsError(err) {
+			res.LeaderAddress = leader.InternalAddress()
+		}
+
+		return &res, nil
+	}
+
+	var checksums []namenode.ReportedChecksum
+	for _, checksum := range req.Checksums {
+		checksums = append(checksums, namenode.ReportedChecksum{
+			Token:    checksum.Token,
+			Parent:   checksum.Parent,
+			Checksum: checksum.Checksum,
+		})
+	}
+
+	diverged := nameNode.CheckChecksums(req.DataNodeID, checksums)
+
+	return &nnpb.ReportChecksumsResponse{
+		Status:        nnpb.ReportChecksumsResponse_SUCCESS,
+		MismatchCount: int32(diverged),
+	}, nil
+}
@@ -0,0 +1,246 @@
+package outer
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+
+	namenode "github.com/SayedAlesawy/Videra-Storage/name_node"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+	"github.com/SayedAlesawy/Videra-Storage/utils/requests"
+	"github.com/julienschmidt/httprouter"
+)
+
+// ReplicationJobsHandler Handles the admin request listing the re-replication jobs and their progress,
+// jobs can be filtered by status through the status query param
+func (server *Server) ReplicationJobsHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	status := r.URL.Query().Get("status")
+
+	jobs := []namenode.ReplicationJob{}
+	for _, job := range namenode.NodeInstance().GetReplicationJobs() {
+		if status == "" || job.Status == status {
+			jobs = append(jobs, job)
+		}
+	}
+
+	resp, err := json.Marshal(map[string]interface{}{"jobs": jobs})
+	if errors.IsError(err) {
+		log.Println(logPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	w.Write(resp)
+}
+
+// scrubReportEntry Represents a diverged copy in the scrub report, along with the progress of its repair
+type scrubReportEntry struct {
+	namenode.ScrubResult
+	RepairStatus string `json:"repair_status,omitempty"` //Status of the re-replication job restoring the copy
+}
+
+// ScrubReportHandler Handles the admin request reporting the scrubbing counters and the diverged copies,
+// along with the progress of their repairs
+func (server *Server) ScrubReportHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	nameNode := namenode.NodeInstance()
+
+	jobs := make(map[string]namenode.ReplicationJob)
+	for _, job := range nameNode.GetReplicationJobs() {
+		jobs[job.ID] = job
+	}
+
+	diverged := []scrubReportEntry{}
+	for _, result := range nameNode.GetScrubResults() {
+		entry := scrubReportEntry{ScrubResult: result}
+		if job, ok := jobs[result.RepairJobID]; ok {
+			entry.RepairStatus = job.Status
+		}
+
+		diverged = append(diverged, entry)
+	}
+
+	resp, err := json.Marshal(map[string]interface{}{
+		"metrics":  nameNode.GetScrubMetrics(),
+		"diverged": diverged,
+	})
+	if errors.IsError(err) {
+		log.Println(logPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	w.Write(resp)
+}
+
+// DrainNodeHandler Handles the admin request to start draining a data node, it stops receiving uploads
+// and replicas while its files are migrated, then it's decommissioned
+func (server *Server) DrainNodeHandler(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	nodeState, err := namenode.NodeInstance().DrainDataNode(params.ByName("id"))
+	if err == namenode.ErrDataNodeOffline {
+		log.Println(logPrefix, r.RemoteAddr, err, params.ByName("id"))
+		requests.HandleRequestError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if errors.IsError(err) {
+		log.Println(logPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	server.writeNodeState(w, r, nodeState)
+}
+
+// ActivateNodeHandler Handles the admin request to bring a draining or decommissioned data node back to service
+func (server *Server) ActivateNodeHandler(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	nodeState, err := namenode.NodeInstance().ActivateDataNode(params.ByName("id"))
+	if errors.IsError(err) {
+		log.Println(logPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	server.writeNodeState(w, r, nodeState)
+}
+
+// DecommissionsHandler Handles the admin request listing the draining and decommissioned data nodes and their progress
+func (server *Server) DecommissionsHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	nodes := []namenode.NodeState{}
+	for _, nodeState := range namenode.NodeInstance().GetNodeStates() {
+		nodes = append(nodes, nodeState)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID < nodes[j].ID
+	})
+
+	resp, err := json.Marshal(map[string]interface{}{"nodes": nodes})
+	if errors.IsError(err) {
+		log.Println(logPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	w.Write(resp)
+}
+
+// writeNodeState is a function to respond with the state of a data node
+func (server *Server) writeNodeState(w http.ResponseWriter, r *http.Request, nodeState namenode.NodeState) {
+	resp, err := json.Marshal(nodeState)
+	if errors.IsError(err) {
+		log.Println(logPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	w.Write(resp)
+}
+
+// NodesHandler Handles the admin request listing the data nodes known to the cluster, along with their capacity,
+// load and the health of the files they hold
+func (server *Server) NodesHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	nodes, err := namenode.NodeInstance().GetNodeInventory()
+	if errors.IsError(err) {
+		log.Println(logPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	resp, err := json.Marshal(map[string]interface{}{"nodes": nodes})
+	if errors.IsError(err) {
+		log.Println(logPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	w.Write(resp)
+}
+
+// NodeHandler Handles the admin request describing a data node, along with its under-replicated files,
+// diverged copies and re-replication jobs
+func (server *Server) NodeHandler(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	details, found, err := namenode.NodeInstance().GetNodeDetails(params.ByName("id"))
+	if errors.IsError(err) {
+		log.Println(logPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+	if !found {
+		requests.HandleRequestError(w, http.StatusNotFound, "Data node not found")
+		return
+	}
+
+	resp, err := json.Marshal(details)
+	if errors.IsError(err) {
+		log.Println(logPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	w.Write(resp)
+}
+
+// DiscrepanciesHandler Handles the admin request listing the discrepancies found in the last block report
+// of each data node, they can be filtered by data node and kind through the node and kind query params
+func (server *Server) DiscrepanciesHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	node := r.URL.Query().Get("node")
+	kind := r.URL.Query().Get("kind")
+
+	discrepancies := []namenode.Discrepancy{}
+	for id, nodeDiscrepancies := range namenode.NodeInstance().GetDiscrepancies() {
+		if node != "" && id != node {
+			continue
+		}
+
+		for _, discrepancy := range nodeDiscrepancies {
+			if kind == "" || discrepancy.Kind == kind {
+				discrepancies = append(discrepancies, discrepancy)
+			}
+		}
+	}
+
+	sort.Slice(discrepancies, func(i, j int) bool {
+		if discrepancies[i].DataNodeID != discrepancies[j].DataNodeID {
+			return discrepancies[i].DataNodeID < discrepancies[j].DataNodeID
+		}
+
+		return discrepancies[i].Token < discrepancies[j].Token
+	})
+
+	resp, err := json.Marshal(map[string]interface{}{"discrepancies": discrepancies})
+	if errors.IsError(err) {
+		log.Println(logPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	w.Write(resp)
+}
+
+// FileLocationsHandler Handles the admin request listing the data nodes holding a copy of a file,
+// as found in their block reports
+func (server *Server) FileLocationsHandler(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+	locations, err := namenode.NodeInstance().GetFileLocations(params.ByName("token"))
+	if errors.IsError(err) {
+		log.Println(logPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	resp, err := json.Marshal(map[string]interface{}{"locations": locations})
+	if errors.IsError(err) {
+		log.Println(logPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
+		return
+	}
+
+	w.Header().Set("content-type", "application/json")
+	w.Write(resp)
+}
@@ -0,0 +1,159 @@
+package outer
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	namenode "github.com/SayedAlesawy/Videra-Storage/name_node"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+	"github.com/SayedAlesawy/Videra-Storage/utils/requests"
+	"github.com/julienschmidt/httprouter"
+)
+
+var deleteLogPrefix = "[Delete-Controller]"
+
+// deleteResult Represents the result payload of the delete endpoint
+type deleteResult struct {
+	Deleted []string `json:"deleted"`           //Tokens of the deleted files
+	Pending []string `json:"pending,omitempty"` //Tokens of the deleted files whose copies on offline data nodes are deleted once they're back
+}
+
+// DeleteRequestHandler Handles client's request to delete a video or a model from the cluster
+func (server *Server) DeleteRequestHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
+	log.Println(deleteLogPrefix, "Received delete request")
+
+	w.Header().Set("content-type", "application/json")
+
+	err := requests.ValidateQuery(r.URL.Query(), "token")
+	if errors.IsError(err) {
+		log.Println(deleteLogPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusBadRequest, err.Error())
+
+		return
+	}
+
+	token := r.URL.Query().Get("token")
+	cascade := r.URL.Query().Get("cascade") == "true"
+	nameNode := namenode.NodeInstance()
+
+	copies, err := nameNode.GetFileCopies(token)
+	if errors.IsError(err) {
+		log.Println(deleteLogPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
+
+		return
+	}
+
+	if len(copies) == 0 {
+		log.Println(deleteLogPrefix, r.RemoteAddr, fmt.Sprintf("File with token: %s is not found", token))
+		requests.HandleRequestError(w, http.StatusNotFound, fmt.Sprintf("File with token: %s is not found", token))
+
+		return
+	}
+
+	var result deleteResult
+
+	if copies[0].Type == namenode.ModelFileType {
+		videos, err := nameNode.GetModelVideos(token)
+		if errors.IsError(err) {
+			log.Println(deleteLogPrefix, r.RemoteAddr, err)
+			requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
+
+			return
+		}
+
+		if len(videos) != 0 && !cascade {
+			log.Println(deleteLogPrefix, r.RemoteAddr, fmt.Sprintf("Model %s is still referenced by %d videos", token, len(videos)))
+			requests.HandleRequestError(w, http.StatusConflict,
+				fmt.Sprintf("Model is still referenced by %d videos, use cascade=true to delete them", len(videos)))
+
+			return
+		}
+
+		for _, video := range videos {
+			pending := false
+			videoCopies, err := nameNode.GetFileCopies(video)
+			if !errors.IsError(err) {
+				pending, err = deleteFile(nameNode, video, videoCopies)
+			}
+
+			if errors.IsError(err) {
+				log.Println(deleteLogPrefix, r.RemoteAddr, err)
+				requests.HandleRequestError(w, http.StatusBadGateway, err.Error())
+
+				return
+			}
+
+			result.Deleted = append(result.Deleted, video)
+			if pending {
+				result.Pending = append(result.Pending, video)
+			}
+		}
+	}
+
+	pending, err := deleteFile(nameNode, token, copies)
+	if errors.IsError(err) {
+		log.Println(deleteLogPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusBadGateway, err.Error())
+
+		return
+	}
+
+	result.Deleted = append(result.Deleted, token)
+	if pending {
+		result.Pending = append(result.Pending, token)
+	}
+
+	resp, err := json.Marshal(result)
+	if errors.IsError(err) {
+		log.Println(deleteLogPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusInternalServerError, err.Error())
+
+		return
+	}
+
+	log.Println(deleteLogPrefix, r.RemoteAddr, "Deleted files", result.Deleted)
+	if len(result.Pending) != 0 {
+		w.WriteHeader(http.StatusAccepted)
+	}
+	w.Write(resp)
+}
+
+// deleteFile A function to delete a file from every data node holding a copy of it, along with its clips,
+// the deletion from the offline data nodes is recorded to be performed once they're back, in which case
+// the file is reported as pending
+func deleteFile(nameNode *namenode.NameNode, token string, copies []namenode.FileInfo) (bool, error) {
+	dataNodes := make(map[string]namenode.DataNodeData)
+	for _, dataNode := range nameNode.GetAllDataNodeData() {
+		dataNodes[dataNode.ID] = dataNode
+	}
+
+	pending := false
+	requestedNodes := make(map[string]bool)
+	for _, fileCopy := range copies {
+		if requestedNodes[fileCopy.DataNodeID] {
+			continue
+		}
+		requestedNodes[fileCopy.DataNodeID] = true
+
+		dataNode, ok := dataNodes[fileCopy.DataNodeID]
+		if !ok {
+			err := nameNode.RecordPendingDeletion(fileCopy.DataNodeID, token)
+			if errors.IsError(err) {
+				return pending, err
+			}
+
+			pending = true
+			continue
+		}
+
+		err := nameNode.DeleteFileFromDataNode(dataNode, token)
+		if errors.IsError(err) {
+			return pending, err
+		}
+	}
+
+	return pending, nameNode.DeleteClips(token)
+}
@@ -1,17 +1,21 @@
 package outer
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"strconv"
 
+	"github.com/SayedAlesawy/Videra-Storage/config"
 	namenode "github.com/SayedAlesawy/Videra-Storage/name_node"
 	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
 	"github.com/julienschmidt/httprouter"
 )
 
-// ReplicationAddressesHandler is a handler responsible for providing data node addresses for replication
+// ReplicationAddressesHandler is a handler responsible for providing data node addresses for replication,
+// it responds with an ordered pipeline of the internal addresses of the nodes that should hold the replicas
 func (server *Server) ReplicationAddressesHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	body, err := ioutil.ReadAll(r.Body)
 	if errors.IsError(err) {
@@ -21,9 +25,36 @@ func (server *Server) ReplicationAddressesHandler(w http.ResponseWriter, r *http
 		return
 	}
 
-	// Get available node for replication
-	chosenDataNode, err := server.getReplicationNode(string(body))
-	// There's no available nodes
+	factor := config.ConfigurationManagerInstance("").NameNodeConfig().ReplicationFactor
+	if r.URL.Query().Get("factor") != "" {
+		factor, err = strconv.Atoi(r.URL.Query().Get("factor"))
+		if errors.IsError(err) || factor < 1 {
+			log.Println(logPrefix, r.RemoteAddr, "Invalid replication factor", r.URL.Query().Get("factor"))
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Invalid replication factor"))
+			return
+		}
+	}
+
+	var size int64
+	if r.URL.Query().Get("size") != "" {
+		size, err = strconv.ParseInt(r.URL.Query().Get("size"), 10, 64)
+		if errors.IsError(err) || size < 0 {
+			log.Println(logPrefix, r.RemoteAddr, "Invalid file size", r.URL.Query().Get("size"))
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("Invalid file size"))
+			return
+		}
+	}
+
+	// Get available nodes for replication
+	var chosenDataNodes []namenode.DataNodeData
+	if r.URL.Query().Get("type") == namenode.ModelFileType {
+		chosenDataNodes, err = server.getModelReplicationPipeline(string(body))
+	} else {
+		chosenDataNodes, err = server.getReplicationPipeline(string(body), factor-1, size)
+	}
+	// There's no enough available nodes
 	if errors.IsError(err) {
 		log.Println(logPrefix, r.RemoteAddr, err)
 		w.WriteHeader(http.StatusServiceUnavailable)
@@ -31,33 +62,47 @@ func (server *Server) ReplicationAddressesHandler(w http.ResponseWriter, r *http
 		return
 	}
 
-	chosenNodeURL := server.getDataNodeUploadURL(chosenDataNode.IP, chosenDataNode.Port)
-	log.Println(logPrefix, r.RemoteAddr, fmt.Sprintf("routed replication to node %s with endpoint %s", chosenDataNode.ID, chosenNodeURL))
-	w.Write([]byte(chosenNodeURL))
+	pipeline := []string{}
+	for _, chosenDataNode := range chosenDataNodes {
+		pipeline = append(pipeline, namenode.GetInternalAddress(chosenDataNode.IP, chosenDataNode.InternalPort))
+	}
+
+	resp, err := json.Marshal(pipeline)
+	if errors.IsError(err) {
+		log.Println(logPrefix, r.RemoteAddr, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte("Internal Server Error"))
+		return
+	}
+
+	log.Println(logPrefix, r.RemoteAddr, fmt.Sprintf("routed replication of node %s to pipeline %v", string(body), pipeline))
+	w.Header().Set("content-type", "application/json")
+	w.Write(resp)
 }
 
-// getReplicationNode is a function to get available data node for replication
-func (server *Server) getReplicationNode(nodeID string) (namenode.DataNodeData, error) {
+// getReplicationPipeline is a function to get the given number of distinct healthy data nodes
+// with enough free disk for replicating a file of the given size
+func (server *Server) getReplicationPipeline(nodeID string, count int, size int64) ([]namenode.DataNodeData, error) {
 	nameNode := namenode.NodeInstance()
-	var chosenDataNode namenode.DataNodeData
 
-	dataNodesData := nameNode.GetAllDataNodeData()
-
-	// There's no available nodes
-	if len(dataNodesData) <= 1 {
-		return chosenDataNode, errors.New("No datanodes available")
+	// for some reason, the requester node is not available
+	if getNodeByID(nameNode.GetAllDataNodeData(), nodeID) == -1 {
+		return nil, errors.New("Invalid Node ID")
 	}
 
-	hostIdx := getNodeByID(dataNodesData, nodeID)
+	return nameNode.PlaceReplicas(nodeID, nil, count, size)
+}
+
+// getModelReplicationPipeline is a function to get the data nodes a model is replicated to
+func (server *Server) getModelReplicationPipeline(nodeID string) ([]namenode.DataNodeData, error) {
+	nameNode := namenode.NodeInstance()
 
 	// for some reason, the requester node is not available
-	if hostIdx == -1 {
-		return chosenDataNode, errors.New("Invalid Node ID")
+	if getNodeByID(nameNode.GetAllDataNodeData(), nodeID) == -1 {
+		return nil, errors.New("Invalid Node ID")
 	}
 
-	// Request is routed to the node next to data node
-	chosenDataNode = dataNodesData[(hostIdx+1)%len(dataNodesData)]
-	return chosenDataNode, nil
+	return nameNode.PlaceModelReplicas(nodeID), nil
 }
 
 // getNodeByID gets index of node with id
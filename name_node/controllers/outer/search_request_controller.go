@@ -1,13 +1,18 @@
 package outer
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/SayedAlesawy/Videra-Storage/config"
 	namenode "github.com/SayedAlesawy/Videra-Storage/name_node"
+	"github.com/SayedAlesawy/Videra-Storage/name_node/query"
 	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
 	"github.com/SayedAlesawy/Videra-Storage/utils/requests"
 	"github.com/julienschmidt/httprouter"
@@ -15,24 +20,76 @@ import (
 
 var scLogPrefix = "[Search-Controller]"
 
+// defaultSearchLimit Number of videos in a page of search results, unless requested otherwise
+var defaultSearchLimit = 20
+
+// maxSearchLimit Maximum number of videos in a page of search results
+var maxSearchLimit = 100
+
+// searchSortColumns Maps the sort options of the search endpoint to the columns of the search query
+var searchSortColumns = map[string]string{
+	"uploaded_at": "uploaded_at",
+	"duration":    "duration",
+	"clips":       "clip_count",
+	"confidence":  "best_confidence",
+}
+
 // searchResult Represents the result payload of the search endpoint
 type searchResult struct {
-	DataNodeID    string `json:"-"`
-	Name          string `json:"name"`
-	Token         string `json:"token"`
-	ThumbnailPath string `json:"thumbnail"`
+	DataNodeID     string    `json:"-"`
+	Name           string    `json:"name"`
+	Token          string    `json:"token"`
+	ThumbnailPath  string    `json:"thumbnail"`
+	UploadedAt     time.Time `json:"uploaded_at"`     //Time at which the video was uploaded
+	Duration       float64   `json:"duration"`        //Length of the video in seconds
+	ClipCount      int       `json:"clip_count"`      //Number of clips of the video matching the search
+	BestConfidence float64   `json:"best_confidence"` //Highest confidence among the matching clips
+}
+
+// searchResponse Represents the envelope of a page of search results
+type searchResponse struct {
+	Total      int            `json:"total"`       //Number of videos matching the search
+	NextCursor string         `json:"next_cursor"` //Cursor of the next page, empty on the last page
+	Results    []searchResult `json:"results"`     //Videos in the page
+}
+
+// searchQuery Represents the filters, ordering and page of a search request
+type searchQuery struct {
+	Expr   query.Expr //Expression over the tags of the clips matching the videos
+	Range  *timeRange //Time range the matching clips are restricted to, if any
+	Sort   string     //Column the videos are ordered by
+	Order  string     //Direction of the ordering (ASC, DESC)
+	Offset int        //Number of videos skipped before the page
+	Limit  int        //Maximum number of videos in the page, 0 for all of them
 }
 
-// SearchRequestHandler Handles client's search request
+// SearchRequestHandler Handles client's search request, videos are matched by a single tag or by a query
+// over the tags of their clips, the results are paged and sorted, and wrapped in an envelope
+// unless the array format of the original frontend is requested
 func (server *Server) SearchRequestHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 	log.Println(scLogPrefix, "Received search request")
 
 	w.Header().Set("content-type", "application/json")
 
-	expectedParams := []string{"tag"}
-	optionalParams := []string{"start", "end"}
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = config.ConfigurationManagerInstance("").NameNodeConfig().SearchResponseFormat
+	}
+	if format != "envelope" && format != "array" {
+		requests.HandleRequestError(w, http.StatusBadRequest, "Format must be envelope or array")
+
+		return
+	}
 
-	err := requests.ValidateQuery(r.URL.Query(), expectedParams...)
+	search, err := parseSearchQuery(r, format == "array")
+	if queryErr, ok := err.(*query.Error); ok {
+		log.Println(scLogPrefix, r.RemoteAddr, err)
+		resp, _ := json.Marshal(queryErr)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write(resp)
+
+		return
+	}
 	if errors.IsError(err) {
 		log.Println(scLogPrefix, r.RemoteAddr, err)
 		requests.HandleRequestError(w, http.StatusBadRequest, err.Error())
@@ -40,72 +97,303 @@ func (server *Server) SearchRequestHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	var results []searchResult
+	total, results, err := retrieveVideos(search)
+	if errors.IsError(err) {
+		log.Println(scLogPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusInternalServerError, "Internal server error")
+
+		return
+	}
+
+	updateThumbnailURL(results)
 
-	tag := r.URL.Query().Get("tag")
+	var resp []byte
+	if format == "array" {
+		resp, err = json.Marshal(results)
+	} else {
+		envelope := searchResponse{Total: total, Results: results}
+		if search.Limit != 0 && search.Offset+len(results) < total {
+			envelope.NextCursor = encodeSearchCursor(search.Offset + len(results))
+		}
 
-	err = requests.ValidateQuery(r.URL.Query(), optionalParams...)
-	if !errors.IsError(err) {
-		start, startErr := strconv.ParseUint(r.URL.Query().Get("start"), 10, 64)
-		end, endErr := strconv.ParseUint(r.URL.Query().Get("end"), 10, 64)
+		resp, err = json.Marshal(envelope)
+	}
+	if errors.IsError(err) {
+		log.Println(scLogPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusInternalServerError, err.Error())
 
-		if errors.IsError(startErr) || errors.IsError(endErr) {
-			log.Println(scLogPrefix, r.RemoteAddr, "Error while parsing start or end times")
-			requests.HandleRequestError(w, http.StatusBadRequest, "Error while parsing start or end times")
+		return
+	}
 
-			return
+	w.Write(resp)
+}
+
+// parseSearchQuery A function to parse the filters, ordering and page of a search request, videos are matched
+// by either a tag or a query (q), pages are requested either by limit and cursor or by page and page_size,
+// the array format returns all the videos unless paged, invalid queries are reported as query errors
+func parseSearchQuery(r *http.Request, arrayFormat bool) (searchQuery, error) {
+	params := r.URL.Query()
+	search := searchQuery{
+		Sort:  searchSortColumns["uploaded_at"],
+		Order: "DESC",
+	}
+
+	switch {
+	case params.Get("q") != "" && params.Get("tag") != "":
+		return search, errors.New("tag and q query params can't be combined")
+	case params.Get("q") != "":
+		expr, err := query.Parse(params.Get("q"))
+		if errors.IsError(err) {
+			return search, err
 		}
 
-		if start > end {
-			log.Println(scLogPrefix, r.RemoteAddr, "Malformed request, start can't be > end")
-			requests.HandleRequestError(w, http.StatusBadRequest, "Start time can't be greater than end time")
+		search.Expr = expr
+	case params.Get("tag") != "":
+		search.Expr = query.Tag{Name: params.Get("tag")}
+	default:
+		return search, errors.New("tag or q query param not provided")
+	}
+
+	clipsRange, err := parseTimeRange(params)
+	if errors.IsError(err) {
+		return search, err
+	}
+	search.Range = clipsRange
 
-			return
+	if sort := params.Get("sort"); sort != "" {
+		column, ok := searchSortColumns[sort]
+		if !ok {
+			return search, errors.New("Sort must be one of uploaded_at, duration, clips, confidence")
 		}
 
-		results = retrieveVideos(tag, start, end)
-	} else {
-		results = retrieveVideos(tag)
+		search.Sort = column
 	}
 
-	updateThumbnailURL(results)
-	resp, err := json.Marshal(results)
+	switch strings.ToLower(params.Get("order")) {
+	case "", "desc":
+	case "asc":
+		search.Order = "ASC"
+	default:
+		return search, errors.New("Order must be asc or desc")
+	}
+
+	if params.Get("page") != "" || params.Get("page_size") != "" {
+		if params.Get("cursor") != "" || params.Get("limit") != "" {
+			return search, errors.New("Page and page_size can't be combined with cursor and limit")
+		}
+
+		page, err := parsePositiveParam(params.Get("page"), 1)
+		if errors.IsError(err) {
+			return search, errors.New("Page must be a positive integer")
+		}
+
+		search.Limit, err = parsePositiveParam(params.Get("page_size"), defaultSearchLimit)
+		if errors.IsError(err) || search.Limit > maxSearchLimit {
+			return search, errors.New(fmt.Sprintf("Page size must be between 1 and %d", maxSearchLimit))
+		}
+
+		search.Offset = (page - 1) * search.Limit
+
+		return search, nil
+	}
+
+	if arrayFormat && params.Get("limit") == "" && params.Get("cursor") == "" {
+		return search, nil
+	}
+
+	limit, err := parsePositiveParam(params.Get("limit"), defaultSearchLimit)
+	if errors.IsError(err) || limit > maxSearchLimit {
+		return search, errors.New(fmt.Sprintf("Limit must be between 1 and %d", maxSearchLimit))
+	}
+	search.Limit = limit
+
+	if cursor := params.Get("cursor"); cursor != "" {
+		search.Offset, err = decodeSearchCursor(cursor)
+		if errors.IsError(err) {
+			return search, errors.New("Malformed cursor")
+		}
+	}
+
+	return search, nil
+}
+
+// parsePositiveParam A function to parse a positive integer query param, falling back to a default if it's missing
+func parsePositiveParam(value string, fallback int) (int, error) {
+	if value == "" {
+		return fallback, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
 	if errors.IsError(err) {
-		log.Println(scLogPrefix, r.RemoteAddr, err)
-		requests.HandleRequestError(w, http.StatusInternalServerError, err.Error())
+		return 0, err
+	}
+	if parsed < 1 {
+		return 0, errors.New("Value must be positive")
+	}
 
-		return
+	return parsed, nil
+}
+
+// encodeSearchCursor A function to encode the offset of the next page into an opaque cursor
+func encodeSearchCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+// decodeSearchCursor A function to decode the offset of a page from its cursor
+func decodeSearchCursor(cursor string) (int, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if errors.IsError(err) {
+		return 0, err
 	}
 
-	w.Write(resp)
+	offset, err := strconv.Atoi(string(decoded))
+	if errors.IsError(err) {
+		return 0, err
+	}
+	if offset < 0 {
+		return 0, errors.New("Negative offset")
+	}
+
+	return offset, nil
 }
 
-// retrieveVideos A function to query the clips table for matching records
-func retrieveVideos(params ...interface{}) []searchResult {
-	var results []searchResult
-	if len(params) == 1 {
-		namenode.NodeInstance().DB.Connection.Raw(`
-		SELECT files.parent AS token, files.name, files.thumbnail_path, files.ID, files.data_node_id
-		FROM files INNER JOIN 
-		(
-			SELECT DISTINCT(token) FROM clips WHERE tag = ?
-		) AS videos 
-		ON files.parent =  videos.token 
-		WHERE files.parent != files.token`,
-			params[0]).Scan(&results)
-	} else {
-		namenode.NodeInstance().DB.Connection.Raw(`
-		SELECT files.parent AS token, files.name, files.thumbnail_path, files.data_node_id AS DataNodeID
-		FROM files INNER JOIN 
-		(
-			SELECT DISTINCT(token) FROM clips WHERE tag = ? and start_time >= ? and start_time <= ?
-		) AS videos 
-		ON files.parent =  videos.token 
-		WHERE files.parent != files.token`,
-			params[0], params[1], params[2]).Scan(&results)
-	}
-
-	return results
+// clipsFilter A function to build the condition selecting the clips matching a search, along with its arguments,
+// which are the clips of the matched videos having one of the tags not negated by the query, or all of them
+// if the query only negates tags
+func (search searchQuery) clipsFilter() (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if tags := query.MatchingTags(search.Expr); len(tags) != 0 {
+		conditions = append(conditions, "clips.tag IN (?)")
+		args = append(args, tags)
+	}
+
+	if timeCondition, timeArgs := search.timeFilter("clips"); timeCondition != "" {
+		conditions = append(conditions, timeCondition)
+		args = append(args, timeArgs...)
+	}
+
+	videos, videoArgs := query.Compile(search.Expr, "clips.token", search.timeFilter)
+	conditions = append(conditions, videos)
+	args = append(args, videoArgs...)
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// timeFilter A function to build the condition restricting the clips of the given alias to the time range of the search
+func (search searchQuery) timeFilter(alias string) (string, []interface{}) {
+	if search.Range == nil {
+		return "", nil
+	}
+
+	return search.Range.condition(alias)
+}
+
+// retrieveVideos A function to query the clips table for the videos matching a search, it returns
+// the number of matching videos along with the requested page of them, ordered by token on ties
+func retrieveVideos(search searchQuery) (int, []searchResult, error) {
+	db := namenode.NodeInstance().DB.Connection
+	filter, args := search.clipsFilter()
+
+	var count struct {
+		Total int
+	}
+
+	err := db.Raw(fmt.Sprintf(`
+	SELECT COUNT(DISTINCT files.parent) AS total
+	FROM files INNER JOIN
+	(
+		SELECT DISTINCT(token) FROM clips WHERE %s
+	) AS videos
+	ON files.parent = videos.token
+	WHERE files.parent != files.token`, filter), args...).Scan(&count).Error
+	if errors.IsError(err) {
+		return 0, nil, err
+	}
+
+	page := fmt.Sprintf(`
+	SELECT videos.token, videos.clip_count, videos.best_confidence,
+		MIN(files.created_at) AS uploaded_at,
+		MAX(CASE WHEN JSON_VALID(files.extras) THEN CAST(JSON_EXTRACT(files.extras, '$.duration') AS DECIMAL(12,3)) ELSE 0 END) AS duration
+	FROM files INNER JOIN
+	(
+		SELECT token, COUNT(*) AS clip_count, COALESCE(MAX(confidence), 0) AS best_confidence FROM clips WHERE %s GROUP BY token
+	) AS videos
+	ON files.parent = videos.token
+	WHERE files.parent != files.token
+	GROUP BY videos.token, videos.clip_count, videos.best_confidence
+	ORDER BY %s %s, videos.token ASC`, filter, search.Sort, search.Order)
+	if search.Limit != 0 {
+		page += " LIMIT ? OFFSET ?"
+		args = append(args, search.Limit, search.Offset)
+	}
+
+	results := []searchResult{}
+	err = db.Raw(page, args...).Scan(&results).Error
+	if errors.IsError(err) {
+		return 0, nil, err
+	}
+
+	err = fillVideoDetails(results)
+
+	return count.Total, results, err
+}
+
+// fillVideoDetails A function to fill the name and thumbnail of the videos, out of their copies,
+// preferring a copy with a thumbnail held by an online data node
+func fillVideoDetails(results []searchResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+
+	tokens := make([]string, len(results))
+	for i, result := range results {
+		tokens[i] = result.Token
+	}
+
+	var copies []searchResult
+	err := namenode.NodeInstance().DB.Connection.Raw(`
+	SELECT files.parent AS token, files.name, files.thumbnail_path, files.data_node_id
+	FROM files
+	WHERE files.parent IN (?) AND files.parent != files.token
+	ORDER BY files.id`, tokens).Scan(&copies).Error
+	if errors.IsError(err) {
+		return err
+	}
+
+	online := make(map[string]bool)
+	for _, dataNode := range namenode.NodeInstance().GetAllDataNodeData() {
+		online[dataNode.ID] = true
+	}
+
+	rank := func(fileCopy searchResult) int {
+		rank := 0
+		if fileCopy.ThumbnailPath != "" {
+			rank += 2
+		}
+		if online[fileCopy.DataNodeID] {
+			rank++
+		}
+
+		return rank
+	}
+
+	best := make(map[string]searchResult)
+	for _, fileCopy := range copies {
+		if current, found := best[fileCopy.Token]; !found || rank(fileCopy) > rank(current) {
+			best[fileCopy.Token] = fileCopy
+		}
+	}
+
+	for i := range results {
+		fileCopy := best[results[i].Token]
+		results[i].Name = fileCopy.Name
+		results[i].ThumbnailPath = fileCopy.ThumbnailPath
+		results[i].DataNodeID = fileCopy.DataNodeID
+	}
+
+	return nil
 }
 
 // updateThumbnailURL updates thumbnail url based on datanode url
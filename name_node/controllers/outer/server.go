@@ -7,6 +7,9 @@ import (
 	"sync"
 
 	"github.com/SayedAlesawy/Videra-Storage/config"
+	namenode "github.com/SayedAlesawy/Videra-Storage/name_node"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+	"github.com/SayedAlesawy/Videra-Storage/utils/requests"
 	"github.com/julienschmidt/httprouter"
 )
 
@@ -45,11 +48,22 @@ func ServerInstance() *Server {
 func (server *Server) Start() {
 	router := httprouter.New()
 
-	router.GET("/upload", server.UploadRequestHandler)
+	// requests updating the cluster state are served by the leader, reads are served by any name node
+	router.GET("/upload", server.leaderOnly(server.UploadRequestHandler))
 	router.GET("/replication", server.ReplicationAddressesHandler)
 	router.GET("/search", server.SearchRequestHandler)
 	router.GET("/stream", server.StreamRequestHandler)
 	router.GET("/tags", server.TagsRequestHandler)
+	router.DELETE("/files", server.leaderOnly(server.DeleteRequestHandler))
+	router.GET("/admin/replication-jobs", server.ReplicationJobsHandler)
+	router.GET("/admin/scrub-report", server.ScrubReportHandler)
+	router.GET("/admin/decommissions", server.DecommissionsHandler)
+	router.GET("/admin/nodes", server.NodesHandler)
+	router.GET("/admin/nodes/:id", server.NodeHandler)
+	router.GET("/admin/discrepancies", server.DiscrepanciesHandler)
+	router.GET("/admin/files/:token/locations", server.FileLocationsHandler)
+	router.POST("/admin/nodes/:id/drain", server.leaderOnly(server.DrainNodeHandler))
+	router.POST("/admin/nodes/:id/activate", server.leaderOnly(server.ActivateNodeHandler))
 
 	address := server.getAddress()
 
@@ -57,6 +71,27 @@ func (server *Server) Start() {
 	log.Fatal(http.ListenAndServe(address, router))
 }
 
+// leaderOnly A function to wrap a handler served by the leader only, followers redirect the request to the leader
+func (server *Server) leaderOnly(handle httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, params httprouter.Params) {
+		nameNode := namenode.NodeInstance()
+		if nameNode.IsLeader() {
+			handle(w, r, params)
+			return
+		}
+
+		leader, err := nameNode.GetLeader()
+		if errors.IsError(err) {
+			log.Println(logPrefix, r.RemoteAddr, "Unable to find the leader name node", err)
+			requests.HandleRequestError(w, http.StatusServiceUnavailable, "Service Unavailable")
+			return
+		}
+
+		// 307 keeps the method and body of the request
+		http.Redirect(w, r, leader.URL()+r.URL.RequestURI(), http.StatusTemporaryRedirect)
+	}
+}
+
 // getAddress A function to get the address on which the external controller listens
 func (server *Server) getAddress() string {
 	return fmt.Sprintf("%s:%s", server.IP, server.Port)
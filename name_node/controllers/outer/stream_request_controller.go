@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"log"
 	"net/http"
-	"strconv"
 
 	namenode "github.com/SayedAlesawy/Videra-Storage/name_node"
 	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
@@ -36,7 +35,6 @@ func (server *Server) StreamRequestHandler(w http.ResponseWriter, r *http.Reques
 	w.Header().Set("content-type", "application/json")
 
 	expectedParams := []string{"token", "tag"}
-	optionalParams := []string{"start", "end"}
 
 	err := requests.ValidateQuery(r.URL.Query(), expectedParams...)
 	if errors.IsError(err) {
@@ -51,31 +49,17 @@ func (server *Server) StreamRequestHandler(w http.ResponseWriter, r *http.Reques
 	token := r.URL.Query().Get("token")
 	tag := r.URL.Query().Get("tag")
 
-	err = requests.ValidateQuery(r.URL.Query(), optionalParams...)
-	if !errors.IsError(err) {
-		start, startErr := strconv.ParseUint(r.URL.Query().Get("start"), 10, 64)
-		end, endErr := strconv.ParseUint(r.URL.Query().Get("end"), 10, 64)
-
-		if errors.IsError(startErr) || errors.IsError(endErr) {
-			log.Println(streamControllerLogPrefix, r.RemoteAddr, "Error while parsing start or end times")
-			requests.HandleRequestError(w, http.StatusBadRequest, "Error while parsing start or end times")
-
-			return
-		}
-
-		if start > end {
-			log.Println(streamControllerLogPrefix, r.RemoteAddr, "Malformed request, start can't be > end")
-			requests.HandleRequestError(w, http.StatusBadRequest, "Start time can't be greater than end time")
+	clipsRange, err := parseTimeRange(r.URL.Query())
+	if errors.IsError(err) {
+		log.Println(streamControllerLogPrefix, r.RemoteAddr, err)
+		requests.HandleRequestError(w, http.StatusBadRequest, err.Error())
 
-			return
-		}
-		result.Progress = retrieveIngestionStatus(token)
-		result.Clips = retrieveClips(token, tag, start, end)
-	} else {
-		result.Progress = retrieveIngestionStatus(token)
-		result.Clips = retrieveClips(token, tag)
+		return
 	}
 
+	result.Progress = retrieveIngestionStatus(token)
+	result.Clips = retrieveClips(token, tag, clipsRange)
+
 	videoInfo := retrieveVideoInfo(token)
 	result.VideoLink = getVideoURL(videoInfo.VideoLink, videoInfo.DataNodeID)
 
@@ -109,15 +93,16 @@ func retrieveIngestionStatus(token string) int {
 	return 0
 }
 
-// retrieveClips A function to query the clips table for matching records
-func retrieveClips(params ...interface{}) []clipResultInfo {
+// retrieveClips A function to query the clips table for matching records, restricted to the time range if any
+func retrieveClips(token string, tag string, clipsRange *timeRange) []clipResultInfo {
 	var clips []clipResultInfo
 
-	if len(params) == 2 {
-		namenode.NodeInstance().DB.Connection.Raw("SELECT DISTINCT start_time, end_time FROM clips WHERE token = ? and tag = ? ORDER BY start_time", params[0], params[1]).Scan(&clips)
+	if clipsRange == nil {
+		namenode.NodeInstance().DB.Connection.Raw("SELECT DISTINCT start_time, end_time FROM clips WHERE token = ? and tag = ? ORDER BY start_time", token, tag).Scan(&clips)
 	} else {
-		namenode.NodeInstance().DB.Connection.Raw("SELECT DISTINCT start_time, end_time FROM clips WHERE token = ? and tag = ? and start_time >= ? and start_time <= ? ORDER BY start_time",
-			params[0], params[1], params[2], params[3]).Scan(&clips)
+		condition, args := clipsRange.condition("clips")
+		namenode.NodeInstance().DB.Connection.Raw(fmt.Sprintf("SELECT DISTINCT start_time, end_time FROM clips WHERE token = ? and tag = ? and %s ORDER BY start_time", condition),
+			append([]interface{}{token, tag}, args...)...).Scan(&clips)
 	}
 
 	return clips
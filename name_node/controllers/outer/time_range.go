@@ -0,0 +1,75 @@
+package outer
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+const (
+	//RangeModeOverlaps matches the clips overlapping the time range, even partially
+	RangeModeOverlaps string = "overlaps"
+	//RangeModeContainedWithin matches the clips starting and ending within the time range
+	RangeModeContainedWithin string = "contained_within"
+	//RangeModeStartsWithin matches the clips starting within the time range, wherever they end
+	RangeModeStartsWithin string = "starts_within"
+)
+
+// timeRange Represents the time range the clips of a request are restricted to, bounds are inclusive
+type timeRange struct {
+	Mode  string //How the clips relate to the range (overlaps, contained_within, starts_within)
+	Start uint64 //Start of the range (secs from start of the video)
+	End   uint64 //End of the range (secs from start of the video)
+}
+
+// parseTimeRange A function to parse the optional time range of a request from the start, end and range_mode params,
+// overlapping is the default mode, nil is returned if the request has no time range
+func parseTimeRange(params url.Values) (*timeRange, error) {
+	if params.Get("start") == "" || params.Get("end") == "" {
+		if params.Get("range_mode") != "" {
+			return nil, errors.New("range_mode requires start and end")
+		}
+
+		return nil, nil
+	}
+
+	start, startErr := strconv.ParseUint(params.Get("start"), 10, 64)
+	end, endErr := strconv.ParseUint(params.Get("end"), 10, 64)
+
+	if errors.IsError(startErr) || errors.IsError(endErr) {
+		return nil, errors.New("Error while parsing start or end times")
+	}
+
+	if start > end {
+		return nil, errors.New("Start time can't be greater than end time")
+	}
+
+	mode := params.Get("range_mode")
+	switch mode {
+	case "":
+		mode = RangeModeOverlaps
+	case RangeModeOverlaps, RangeModeContainedWithin, RangeModeStartsWithin:
+	default:
+		return nil, errors.New("range_mode must be one of overlaps, contained_within, starts_within")
+	}
+
+	return &timeRange{Mode: mode, Start: start, End: end}, nil
+}
+
+// condition A function to build the condition restricting the clips of the given alias to the time range,
+// each mode bounds start_time by the end of the range, so the lookups are range scans of the clips indexes
+func (clipsRange timeRange) condition(alias string) (string, []interface{}) {
+	switch clipsRange.Mode {
+	case RangeModeContainedWithin:
+		return fmt.Sprintf("%s.start_time >= ? AND %s.start_time <= ? AND %s.end_time <= ?", alias, alias, alias),
+			[]interface{}{clipsRange.Start, clipsRange.End, clipsRange.End}
+	case RangeModeStartsWithin:
+		return fmt.Sprintf("%s.start_time >= ? AND %s.start_time <= ?", alias, alias),
+			[]interface{}{clipsRange.Start, clipsRange.End}
+	default:
+		return fmt.Sprintf("%s.start_time <= ? AND %s.end_time >= ?", alias, alias),
+			[]interface{}{clipsRange.End, clipsRange.Start}
+	}
+}
@@ -11,13 +11,21 @@ import (
 	"github.com/julienschmidt/httprouter"
 )
 
-// UploadRequestHandler Handles client's request to retrieve data node url for uploading
+// UploadRequestHandler Handles client's request to retrieve data node url for uploading,
+// the optional type and model query parameters describe the upload to the routing policy
 func (server *Server) UploadRequestHandler(w http.ResponseWriter, r *http.Request, _ httprouter.Params) {
 
 	nameNode := namenode.NodeInstance()
 
-	// Get node with minimum number of clients requests
-	chosenDataNode, err := server.getAvailableDataNode(nameNode)
+	model := r.URL.Query().Get("model")
+	routingRequest := namenode.RoutingRequest{
+		FileType:     r.URL.Query().Get("type"),
+		Model:        model,
+		ModelHolders: server.getModelHolders(nameNode, model),
+	}
+
+	// Get node chosen by the routing policy
+	chosenDataNode, err := nameNode.RouteUpload(routingRequest)
 	// There's no available nodes
 	if errors.IsError(err) {
 		log.Println(logPrefix, r.RemoteAddr, err)
@@ -26,10 +34,13 @@ func (server *Server) UploadRequestHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// update request count for further selection
-	chosenDataNode.RequestCount++
-	chosenDataNode.LastRequestTime = time.Now()
-	nameNode.InsertDataNodeData(chosenDataNode)
+	// update request count for further selection, the upload counts towards the load
+	// of the node until its next health check reports it
+	nameNode.UpdateDataNodeData(chosenDataNode.ID, func(dataNodeData *namenode.DataNodeData) {
+		dataNodeData.RequestCount++
+		dataNodeData.InFlightUploads++
+		dataNodeData.LastRequestTime = time.Now()
+	})
 
 	chosenNodeURL := server.getDataNodeUploadURL(chosenDataNode.IP, chosenDataNode.Port)
 	log.Println(logPrefix, r.RemoteAddr, fmt.Sprintf("routed to node %s with endpoint %s", chosenDataNode.ID, chosenNodeURL))
@@ -37,38 +48,26 @@ func (server *Server) UploadRequestHandler(w http.ResponseWriter, r *http.Reques
 	w.Write([]byte(chosenNodeURL))
 }
 
-// getAvailableDataNode is a function to get available data node
-// it tries to choose a data node with minimum load
-func (server *Server) getAvailableDataNode(nameNode *namenode.NameNode) (namenode.DataNodeData, error) {
-	dataNodes := nameNode.GetAllDataNodeData()
-	// There's no available nodes
-	if len(dataNodes) == 0 {
-		return namenode.DataNodeData{}, errors.New("No datanodes available")
+// getModelHolders is a function to get the IDs of the data nodes holding a completed copy of a model
+func (server *Server) getModelHolders(nameNode *namenode.NameNode, model string) map[string]bool {
+	holders := make(map[string]bool)
+	if model == "" {
+		return holders
 	}
 
-	var chosenDataNode namenode.DataNodeData
-	hasChoosenNode := false
-
-	for _, dataNode := range dataNodes {
-		if !dataNode.GPU {
-			continue
-		}
-
-		if hasChoosenNode {
-			if dataNode.LastRequestTime.Before(chosenDataNode.LastRequestTime) {
-				chosenDataNode = dataNode
-			}
-		} else {
-			chosenDataNode = dataNode
-			hasChoosenNode = true
-		}
+	copies, err := nameNode.GetFileCopies(model)
+	if errors.IsError(err) {
+		log.Println(logPrefix, fmt.Sprintf("Unable to fetch copies of model %s", model), err)
+		return holders
 	}
 
-	if !hasChoosenNode {
-		return namenode.DataNodeData{}, errors.New("Can't find a machine with GPU")
+	for _, fileCopy := range copies {
+		if fileCopy.Type == namenode.ModelFileType && fileCopy.CompletedAt != nil {
+			holders[fileCopy.DataNodeID] = true
+		}
 	}
 
-	return chosenDataNode, nil
+	return holders
 }
 
 // getAddress A function to get the address on which the internal controller listens
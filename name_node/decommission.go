@@ -0,0 +1,203 @@
+package namenode
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+const (
+	//NodeStateActive represents a data node taking new uploads and replicas
+	NodeStateActive string = "active"
+	//NodeStateDraining represents a data node whose files are being migrated to other data nodes
+	NodeStateDraining string = "draining"
+	//NodeStateDecommissioned represents a data node whose files are all held elsewhere, ready to be taken out
+	NodeStateDecommissioned string = "decommissioned"
+)
+
+// ErrDataNodeOffline Indicates that a data node to be drained isn't online
+var ErrDataNodeOffline = errors.New("Data node is not online")
+
+// NodeState Represents the decommissioning progress of a data node, active nodes have no stored state
+type NodeState struct {
+	ID               string     `json:"id"`                          //ID of the data node
+	State            string     `json:"state"`                       //State of the data node (active, draining, decommissioned)
+	FilesTotal       int        `json:"files_total"`                 //Number of files held by the data node when last checked
+	FilesRemaining   int        `json:"files_remaining"`             //Number of files not yet fully replicated on other data nodes
+	PendingWork      int        `json:"pending_work"`                //Number of jobs and uploads in progress on the data node
+	StartedAt        time.Time  `json:"started_at"`                  //Time at which the data node started draining
+	UpdatedAt        time.Time  `json:"updated_at"`                  //Time at which the state was last updated
+	DecommissionedAt *time.Time `json:"decommissioned_at,omitempty"` //Time at which the data node was decommissioned
+}
+
+// isLeaving A function to check if a data node is leaving the cluster, so it takes no new uploads or replicas
+func (nodeState NodeState) isLeaving() bool {
+	return nodeState.State == NodeStateDraining || nodeState.State == NodeStateDecommissioned
+}
+
+// DrainDataNode A function to start draining an online data node, it stops receiving uploads and replicas,
+// while its files are migrated to other data nodes
+func (nameNode *NameNode) DrainDataNode(id string) (NodeState, error) {
+	nodeState := nameNode.GetNodeState(id)
+	if nodeState.isLeaving() {
+		return nodeState, nil
+	}
+
+	live := false
+	for _, dataNode := range nameNode.GetAllDataNodeData() {
+		live = live || dataNode.ID == id
+	}
+	if !live {
+		return nodeState, ErrDataNodeOffline
+	}
+
+	log.Println(logPrefix, fmt.Sprintf("Draining data node %s", id))
+
+	nodeState.State = NodeStateDraining
+	nodeState.StartedAt = time.Now()
+
+	return nodeState, nameNode.saveNodeState(nodeState)
+}
+
+// ActivateDataNode A function to bring a draining or decommissioned data node back to taking uploads and replicas
+func (nameNode *NameNode) ActivateDataNode(id string) (NodeState, error) {
+	log.Println(logPrefix, fmt.Sprintf("Activating data node %s", id))
+
+	err := nameNode.deleteFromHash(nameNode.nodeStatesKey, id)
+
+	return NodeState{ID: id, State: NodeStateActive}, err
+}
+
+// RunDecommissions A function to periodically migrate the files off the draining data nodes,
+// a node is decommissioned once all its files are fully replicated elsewhere and it has no work in progress,
+// only done by the leader
+func (nameNode *NameNode) RunDecommissions() {
+	for range time.Tick(nameNode.DecommissionInterval) {
+		if !nameNode.IsLeader() {
+			continue
+		}
+
+		for _, nodeState := range nameNode.GetNodeStates() {
+			if nodeState.State == NodeStateDraining {
+				nameNode.drainDataNode(nodeState)
+			}
+		}
+	}
+}
+
+// drainDataNode A function to schedule the migration of the files of a draining data node, and check its progress
+func (nameNode *NameNode) drainDataNode(nodeState NodeState) {
+	liveNodes := make(map[string]DataNodeData)
+	for _, dataNode := range nameNode.GetAllDataNodeData() {
+		liveNodes[dataNode.ID] = dataNode
+	}
+
+	// the files of an offline node are restored by re-replication, draining resumes once it's back
+	dataNode, live := liveNodes[nodeState.ID]
+	if !live {
+		return
+	}
+
+	tokens, err := nameNode.GetFilesHeldBy(nodeState.ID)
+	if errors.IsError(err) {
+		log.Println(logPrefix, fmt.Sprintf("Unable to fetch files held by data node %s", nodeState.ID), err)
+		return
+	}
+
+	states := nameNode.GetNodeStates()
+	remaining := 0
+	for _, token := range tokens {
+		holders, err := nameNode.getFileHolders(token, liveNodes, states)
+		if errors.IsError(err) {
+			log.Println(logPrefix, fmt.Sprintf("Unable to fetch copies of file %s", token), err)
+			remaining++
+			continue
+		}
+
+		if len(holders.staying) < holders.desiredCopies {
+			remaining++
+			nameNode.scheduleFileReReplication(token)
+		}
+	}
+
+	nodeState.FilesTotal = len(tokens)
+	nodeState.FilesRemaining = remaining
+	nodeState.PendingWork = dataNode.Load()
+
+	if remaining == 0 && nodeState.PendingWork == 0 {
+		log.Println(logPrefix, fmt.Sprintf("Data node %s is decommissioned", nodeState.ID))
+
+		now := time.Now()
+		nodeState.State = NodeStateDecommissioned
+		nodeState.DecommissionedAt = &now
+	}
+
+	// the node may have been activated while its files were checked
+	if !nameNode.GetNodeState(nodeState.ID).isLeaving() {
+		return
+	}
+
+	err = nameNode.saveNodeState(nodeState)
+	if errors.IsError(err) {
+		log.Println(logPrefix, fmt.Sprintf("Unable to insert into redis hash: %s for data node: %s", nameNode.nodeStatesKey, nodeState.ID))
+	}
+}
+
+// GetNodeState A function to get the decommissioning state of a data node
+func (nameNode *NameNode) GetNodeState(id string) NodeState {
+	nodeState := NodeState{ID: id, State: NodeStateActive}
+
+	encodedState, err := nameNode.getFromHash(nameNode.nodeStatesKey, id)
+	if errors.IsError(err) {
+		return nodeState
+	}
+
+	err = json.Unmarshal([]byte(encodedState), &nodeState)
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to decode data node state", encodedState)
+	}
+
+	return nodeState
+}
+
+// GetNodeStates A function to get the states of the draining and decommissioned data nodes by ID
+func (nameNode *NameNode) GetNodeStates() map[string]NodeState {
+	states := make(map[string]NodeState)
+
+	encodedStates, err := nameNode.getAllFromHash(nameNode.nodeStatesKey)
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to fetch data node states from redis")
+
+		return states
+	}
+
+	for id, encodedState := range encodedStates {
+		var nodeState NodeState
+
+		err := json.Unmarshal([]byte(encodedState), &nodeState)
+		if errors.IsError(err) {
+			log.Println(logPrefix, "Unable to decode data node state", encodedState)
+
+			continue
+		}
+
+		states[id] = nodeState
+	}
+
+	return states
+}
+
+// saveNodeState A function to insert or update the state of a data node
+func (nameNode *NameNode) saveNodeState(nodeState NodeState) error {
+	nodeState.UpdatedAt = time.Now()
+
+	encodedState, err := json.Marshal(nodeState)
+	if errors.IsError(err) {
+		return err
+	}
+
+	return nameNode.insertIntoHash(nameNode.nodeStatesKey, nodeState.ID, string(encodedState))
+}
@@ -0,0 +1,107 @@
+package namenode
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+// PendingDeletion Represents the copies of a deleted file held by a data node that was offline when the file was deleted
+type PendingDeletion struct {
+	Token      string    `json:"token"`        //Token of the deleted file
+	DataNodeID string    `json:"data_node_id"` //ID of the offline data node holding copies of the file
+	CreatedAt  time.Time `json:"created_at"`   //Time at which the file was deleted
+}
+
+// RecordPendingDeletion A function to record the deletion of a file from an offline data node, the deletion
+// is performed once the data node joins the cluster again or sends a block report
+func (nameNode *NameNode) RecordPendingDeletion(dataNodeID string, token string) error {
+	deletion := PendingDeletion{
+		Token:      token,
+		DataNodeID: dataNodeID,
+		CreatedAt:  time.Now(),
+	}
+
+	encodedDeletion, err := json.Marshal(deletion)
+	if errors.IsError(err) {
+		return err
+	}
+
+	log.Println(logPrefix, fmt.Sprintf("Deletion of file %s from offline data node %s is pending", token, dataNodeID))
+
+	return nameNode.insertIntoHash(nameNode.pendingDeletionsKey, pendingDeletionField(dataNodeID, token), string(encodedDeletion))
+}
+
+// ProcessPendingDeletions A function to delete the files deleted while the data node was offline,
+// a deletion that fails stays pending, and is retried with the next block report of the data node
+func (nameNode *NameNode) ProcessPendingDeletions(dataNode DataNodeData) {
+	for _, deletion := range nameNode.GetPendingDeletions() {
+		if deletion.DataNodeID != dataNode.ID {
+			continue
+		}
+
+		err := nameNode.DeleteFileFromDataNode(dataNode, deletion.Token)
+		if errors.IsError(err) {
+			log.Println(logPrefix, fmt.Sprintf("Unable to delete file %s from data node %s", deletion.Token, dataNode.ID), err)
+			continue
+		}
+
+		log.Println(logPrefix, fmt.Sprintf("Deleted file %s from data node %s, deleted while it was offline", deletion.Token, dataNode.ID))
+
+		err = nameNode.deleteFromHash(nameNode.pendingDeletionsKey, pendingDeletionField(deletion.DataNodeID, deletion.Token))
+		if errors.IsError(err) {
+			log.Println(logPrefix, fmt.Sprintf("Unable to delete from redis hash: %s for file: %s", nameNode.pendingDeletionsKey, deletion.Token))
+		}
+	}
+}
+
+// GetPendingDeletions A function to get the deletions waiting for offline data nodes, ordered by their time
+func (nameNode *NameNode) GetPendingDeletions() []PendingDeletion {
+	var deletions []PendingDeletion
+
+	encodedDeletions, err := nameNode.getAllFromHash(nameNode.pendingDeletionsKey)
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to fetch pending deletions from redis")
+
+		return deletions
+	}
+
+	for _, encodedDeletion := range encodedDeletions {
+		var deletion PendingDeletion
+
+		err := json.Unmarshal([]byte(encodedDeletion), &deletion)
+		if errors.IsError(err) {
+			log.Println(logPrefix, "Unable to decode pending deletion", encodedDeletion)
+
+			continue
+		}
+
+		deletions = append(deletions, deletion)
+	}
+
+	sort.Slice(deletions, func(i, j int) bool {
+		return deletions[i].CreatedAt.Before(deletions[j].CreatedAt)
+	})
+
+	return deletions
+}
+
+// isPendingDeletion A function to check if a file was deleted while some of its holders were offline
+func (nameNode *NameNode) isPendingDeletion(token string) bool {
+	for _, deletion := range nameNode.GetPendingDeletions() {
+		if deletion.Token == token {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pendingDeletionField A function to get the field of the deletion of a file from a data node in the redis hash
+func pendingDeletionField(dataNodeID string, token string) string {
+	return fmt.Sprintf("%s:%s", dataNodeID, token)
+}
@@ -9,8 +9,14 @@ import (
 func main() {
 	nameNode := namenode.NodeInstance()
 
+	go nameNode.RunLeaderElection()
+
 	go nameNode.PingDataNodes()
 
+	go nameNode.RunReplicationJobs()
+
+	go nameNode.RunDecommissions()
+
 	go inner.ServerInstance().Start()
 
 	outer.ServerInstance().Start()
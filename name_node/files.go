@@ -0,0 +1,169 @@
+package namenode
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SayedAlesawy/Videra-Storage/data_node/dnpb"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+	"google.golang.org/grpc"
+)
+
+const (
+	//VideoFileType represents video type
+	VideoFileType string = "video"
+	//ModelFileType represents model type
+	ModelFileType string = "model"
+)
+
+// FileInfo Represents the info of a file copy as stored by the data nodes
+type FileInfo struct {
+	Token         string     //Token of the file copy
+	Parent        string     //Token of the original file
+	Type          string     //Type of the file (video, model)
+	Size          int64      //Size of the file in bytes
+	DataNodeID    string     //ID of the data node holding the copy
+	ReplicaCount  int        //Number of replicas the copy was forwarded to when uploaded
+	Checksum      string     //SHA-256 digest of the copy computed on completion (hex)
+	CompletedAt   *time.Time //Indicates if the copy completed uploading
+	HLSPath       string     //Path to the HLS playlist of the copy, in case of a streamable video
+	ThumbnailPath string     //Path to the thumbnail of the copy, in case of a video
+}
+
+// GetFileCopies A function to get all copies of a file (the original and its replicas)
+func (nameNode *NameNode) GetFileCopies(token string) ([]FileInfo, error) {
+	var copies []FileInfo
+
+	err := nameNode.DB.Connection.Raw(`
+	SELECT token, parent, type, size, data_node_id, replica_count, checksum, completed_at
+	FROM files
+	WHERE files.parent = ?`, token).Scan(&copies).Error
+
+	return copies, err
+}
+
+// GetAllFileCopies A function to get the copies of all files, ordered by the token of their original
+func (nameNode *NameNode) GetAllFileCopies() ([]FileInfo, error) {
+	var copies []FileInfo
+
+	err := nameNode.DB.Connection.Raw(`
+	SELECT token, parent, type, size, data_node_id, replica_count, checksum, completed_at
+	FROM files
+	ORDER BY parent`).Scan(&copies).Error
+
+	return copies, err
+}
+
+// GetFileCopiesHeldBy A function to get the copies of files recorded for a data node
+func (nameNode *NameNode) GetFileCopiesHeldBy(dataNodeID string) ([]FileInfo, error) {
+	var copies []FileInfo
+
+	err := nameNode.DB.Connection.Raw(`
+	SELECT token, parent, type, size, data_node_id, replica_count, checksum, completed_at, hls_path, thumbnail_path
+	FROM files
+	WHERE files.data_node_id = ?`, dataNodeID).Scan(&copies).Error
+
+	return copies, err
+}
+
+// GetFilesHeldBy A function to get the tokens of the original files having a completed copy on a data node
+func (nameNode *NameNode) GetFilesHeldBy(dataNodeID string) ([]string, error) {
+	var files []struct {
+		Parent string
+	}
+
+	err := nameNode.DB.Connection.Raw(`
+	SELECT DISTINCT parent
+	FROM files
+	WHERE files.data_node_id = ? AND files.completed_at IS NOT NULL`, dataNodeID).Scan(&files).Error
+
+	var tokens []string
+	for _, file := range files {
+		tokens = append(tokens, file.Parent)
+	}
+
+	return tokens, err
+}
+
+// GetModelVideos A function to get the tokens of the videos associated with a model
+func (nameNode *NameNode) GetModelVideos(modelToken string) ([]string, error) {
+	var videos []struct {
+		Parent string
+	}
+
+	err := nameNode.DB.Connection.Raw(`
+	SELECT DISTINCT parent
+	FROM files
+	WHERE files.type = ? AND files.extras LIKE ?`,
+		VideoFileType, fmt.Sprintf("%%\"associated_model\":\"%s\"%%", modelToken)).Scan(&videos).Error
+
+	var tokens []string
+	for _, video := range videos {
+		tokens = append(tokens, video.Parent)
+	}
+
+	return tokens, err
+}
+
+// DeleteClips A function to delete all clips of a video
+func (nameNode *NameNode) DeleteClips(token string) error {
+	return nameNode.DB.Connection.Unscoped().Where("token = ?", token).Delete(&Clip{}).Error
+}
+
+// DeleteFileFromDataNode A function to request a data node to delete all of its copies of a file
+func (nameNode *NameNode) DeleteFileFromDataNode(dataNode DataNodeData, token string) error {
+	address := nameNode.getDataNodeInternalAddress(dataNode)
+
+	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	if errors.IsError(err) {
+		return err
+	}
+	defer conn.Close()
+
+	client := dnpb.NewDataNodeInternalRoutesClient(conn)
+	req := dnpb.DeleteFileRequest{Token: token}
+
+	ctx, cancel := context.WithTimeout(context.Background(), nameNode.InteralReqTimeout)
+	defer cancel()
+
+	resp, err := client.DeleteFile(ctx, &req)
+	if errors.IsError(err) {
+		return err
+	}
+
+	if resp.Status != dnpb.DeleteFileResponse_SUCCESS {
+		return errors.New(fmt.Sprintf("Data node %s failed to delete file %s", dataNode.ID, token))
+	}
+
+	return nil
+}
+
+// CopyFileToDataNode A function to request a data node to copy its completed copy of a file to another data node,
+// it returns the token of the new copy
+func (nameNode *NameNode) CopyFileToDataNode(dataNode DataNodeData, token string, target DataNodeData) (string, error) {
+	address := nameNode.getDataNodeInternalAddress(dataNode)
+
+	conn, err := grpc.Dial(address, grpc.WithInsecure())
+	if errors.IsError(err) {
+		return "", err
+	}
+	defer conn.Close()
+
+	client := dnpb.NewDataNodeInternalRoutesClient(conn)
+	req := dnpb.CopyFileRequest{Token: token, TargetAddress: nameNode.getDataNodeInternalAddress(target)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), nameNode.reReplicationTimeout)
+	defer cancel()
+
+	resp, err := client.CopyFile(ctx, &req)
+	if errors.IsError(err) {
+		return "", err
+	}
+
+	if resp.Status != dnpb.CopyFileResponse_SUCCESS {
+		return "", errors.New(fmt.Sprintf("Data node %s failed to copy file %s", dataNode.ID, token))
+	}
+
+	return resp.NewToken, nil
+}
@@ -11,9 +11,13 @@ import (
 	"google.golang.org/grpc"
 )
 
-// PingDataNodes A function to ping all currently conneced data nodes for health checking
+// PingDataNodes A function to ping all currently conneced data nodes for health checking, only done by the leader
 func (nameNode *NameNode) PingDataNodes() {
 	for range time.Tick(nameNode.HealthCheckInterval) {
+		if !nameNode.IsLeader() {
+			continue
+		}
+
 		for _, dataNode := range nameNode.GetAllDataNodeData() {
 			nameNode.pingDataNode(dataNode)
 		}
@@ -38,14 +42,19 @@ func (nameNode *NameNode) pingDataNode(dataNode DataNodeData) {
 	defer cancel()
 
 	healthCheckResp, err := client.HealthCheck(ctx, &req)
+	if !errors.IsError(err) && healthCheckResp.Status == dnpb.HealthCheckResponse_UNHEALTHY {
+		err = errors.New("Data node reported itself unhealthy")
+	}
 	if errors.IsError(err) {
 		if dataNode.Latency > nameNode.dataNodeOfflineThreshold {
 			log.Println(fmt.Sprintf("%s Data node on address: %s is OFFLINE", logPrefix, address))
 
 			nameNode.RemoveDataNodeData(dataNode)
+			go nameNode.ScheduleReReplication(dataNode)
 		} else {
-			dataNode.Latency++
-			nameNode.InsertDataNodeData(dataNode)
+			nameNode.UpdateDataNodeData(dataNode.ID, func(dataNodeData *DataNodeData) {
+				dataNodeData.Latency++
+			})
 
 			log.Println(logPrefix, fmt.Sprintf("Data node on address: %s missed a ping", address))
 		}
@@ -54,4 +63,20 @@ func (nameNode *NameNode) pingDataNode(dataNode DataNodeData) {
 	}
 
 	log.Println(logPrefix, fmt.Sprintf("Data node on address: %s is:", address), healthCheckResp.Status)
+
+	// the data of the node was read at the start of the round, so only its load is written back
+	load := NodeLoad{
+		FreeDisk:        healthCheckResp.FreeDisk,
+		UsedDisk:        healthCheckResp.UsedDisk,
+		TotalDisk:       healthCheckResp.TotalDisk,
+		FileCount:       healthCheckResp.FileCount,
+		RunningJobs:     int(healthCheckResp.RunningJobs),
+		QueuedJobs:      int(healthCheckResp.QueuedJobs),
+		InFlightUploads: int(healthCheckResp.InFlightUploads),
+		GPU:             healthCheckResp.GPU,
+		GPUAvailable:    healthCheckResp.GPUAvailable,
+	}
+	nameNode.UpdateDataNodeData(dataNode.ID, func(dataNodeData *DataNodeData) {
+		dataNodeData.updateLoad(load)
+	})
 }
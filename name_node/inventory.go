@@ -0,0 +1,181 @@
+package namenode
+
+import (
+	"sort"
+
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+// NodeFileStats Represents the counts of the file copies held by a data node
+type NodeFileStats struct {
+	Copies          int `json:"copies"`           //Number of file copies held by the data node
+	Originals       int `json:"originals"`        //Number of copies uploaded by clients
+	Replicas        int `json:"replicas"`         //Number of copies replicated from other data nodes
+	Incomplete      int `json:"incomplete"`       //Number of copies still being uploaded
+	UnderReplicated int `json:"under_replicated"` //Number of files held whose online copies are fewer than intended
+	Diverged        int `json:"diverged"`         //Number of copies found by the scrubber to diverge from their originals
+}
+
+// NodeInventory Represents a data node known to the cluster, along with the health of the files it holds
+type NodeInventory struct {
+	DataNodeData
+	Online bool          `json:"online"` //Indicates if the data node is tracked as online
+	State  string        `json:"state"`  //Decommissioning state of the data node (active, draining, decommissioned)
+	Files  NodeFileStats `json:"files"`  //Counts of the file copies held by the data node
+}
+
+// NodeDetails Represents a data node along with the files and jobs needing attention
+type NodeDetails struct {
+	NodeInventory
+	UnderReplicatedFiles []string         `json:"under_replicated_files"` //Tokens of the files held whose online copies are fewer than intended
+	DivergedCopies       []ScrubResult    `json:"diverged_copies"`        //Copies held found by the scrubber to diverge from their originals
+	ReplicationJobs      []ReplicationJob `json:"replication_jobs"`       //Re-replication jobs copying files from or to the data node
+	Discrepancies        []Discrepancy    `json:"discrepancies"`          //Copies found by the last block report to be missing, extra or stale
+	Decommission         *NodeState       `json:"decommission,omitempty"` //Decommissioning progress of the data node, if leaving
+}
+
+// GetNodeInventory A function to get all the data nodes known to the cluster ordered by ID, which are the online
+// nodes, the draining or decommissioned ones, and the offline ones still holding copies of files
+func (nameNode *NameNode) GetNodeInventory() ([]NodeInventory, error) {
+	inventory, _, err := nameNode.buildInventory()
+	if errors.IsError(err) {
+		return nil, err
+	}
+
+	nodes := []NodeInventory{}
+	for _, node := range inventory {
+		nodes = append(nodes, node)
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].ID < nodes[j].ID
+	})
+
+	return nodes, nil
+}
+
+// GetNodeDetails A function to get a data node known to the cluster, along with the files and jobs needing attention,
+// the second return value is false if the node isn't known
+func (nameNode *NameNode) GetNodeDetails(id string) (NodeDetails, bool, error) {
+	inventory, underReplicated, err := nameNode.buildInventory()
+	if errors.IsError(err) {
+		return NodeDetails{}, false, err
+	}
+
+	node, found := inventory[id]
+	if !found {
+		return NodeDetails{}, false, nil
+	}
+
+	details := NodeDetails{
+		NodeInventory:        node,
+		UnderReplicatedFiles: underReplicated[id],
+		DivergedCopies:       []ScrubResult{},
+		ReplicationJobs:      []ReplicationJob{},
+		Discrepancies:        nameNode.GetDiscrepancies()[id],
+	}
+	if details.UnderReplicatedFiles == nil {
+		details.UnderReplicatedFiles = []string{}
+	}
+	if details.Discrepancies == nil {
+		details.Discrepancies = []Discrepancy{}
+	}
+
+	for _, result := range nameNode.GetScrubResults() {
+		if result.DataNodeID == id && result.unresolved() {
+			details.DivergedCopies = append(details.DivergedCopies, result)
+		}
+	}
+
+	for _, job := range nameNode.GetReplicationJobs() {
+		if job.SourceNodeID == id || job.TargetNodeID == id {
+			details.ReplicationJobs = append(details.ReplicationJobs, job)
+		}
+	}
+
+	if nodeState := nameNode.GetNodeState(id); nodeState.isLeaving() {
+		details.Decommission = &nodeState
+	}
+
+	return details, true, nil
+}
+
+// buildInventory is a function to gather the data nodes known to the cluster by ID, along with the tokens
+// of the under-replicated files held by each of them
+func (nameNode *NameNode) buildInventory() (map[string]NodeInventory, map[string][]string, error) {
+	inventory := make(map[string]NodeInventory)
+	underReplicated := make(map[string][]string)
+
+	liveNodes := make(map[string]DataNodeData)
+	for _, dataNode := range nameNode.GetAllDataNodeData() {
+		liveNodes[dataNode.ID] = dataNode
+		inventory[dataNode.ID] = NodeInventory{DataNodeData: dataNode, Online: true, State: NodeStateActive}
+	}
+
+	states := nameNode.GetNodeStates()
+	for id, nodeState := range states {
+		node := nodeOf(inventory, id)
+		node.State = nodeState.State
+		inventory[id] = node
+	}
+
+	copies, err := nameNode.GetAllFileCopies()
+	if errors.IsError(err) {
+		return nil, nil, err
+	}
+
+	expectations := nameNode.getFileExpectations()
+
+	// copies are ordered by their original, so the copies of each file are contiguous
+	for start := 0; start < len(copies); {
+		end := start
+		for end < len(copies) && copies[end].Parent == copies[start].Parent {
+			end++
+		}
+
+		fileCopies := copies[start:end]
+		expectation, found := expectationOf(fileCopies)
+		if !found {
+			expectation = expectations[fileCopies[0].Parent]
+		}
+		holders := holdersOf(fileCopies, liveNodes, states, expectation)
+		underReplicatedFile := len(holders.staying) < holders.desiredCopies
+
+		for _, fileCopy := range fileCopies {
+			node := nodeOf(inventory, fileCopy.DataNodeID)
+			node.Files.Copies++
+			if fileCopy.Token == fileCopy.Parent {
+				node.Files.Originals++
+			} else {
+				node.Files.Replicas++
+			}
+			if fileCopy.CompletedAt == nil {
+				node.Files.Incomplete++
+			} else if underReplicatedFile {
+				node.Files.UnderReplicated++
+				underReplicated[fileCopy.DataNodeID] = append(underReplicated[fileCopy.DataNodeID], fileCopy.Parent)
+			}
+			inventory[fileCopy.DataNodeID] = node
+		}
+
+		start = end
+	}
+
+	for _, result := range nameNode.GetScrubResults() {
+		if node, found := inventory[result.DataNodeID]; found && result.unresolved() {
+			node.Files.Diverged++
+			inventory[result.DataNodeID] = node
+		}
+	}
+
+	return inventory, underReplicated, nil
+}
+
+// nodeOf is a function to get a data node from the inventory, a node that isn't there is offline and active
+func nodeOf(inventory map[string]NodeInventory, id string) NodeInventory {
+	if node, found := inventory[id]; found {
+		return node
+	}
+
+	return NodeInventory{DataNodeData: DataNodeData{ID: id}, State: NodeStateActive}
+}
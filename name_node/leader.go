@@ -0,0 +1,102 @@
+package namenode
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+// renewLeaseScript Extends the lease only if it's still held by the renewing name node, atomically
+var renewLeaseScript = `
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`
+
+// LeaderInfo Represents the name node holding the leader lease
+type LeaderInfo struct {
+	ID           string `json:"id"`            //Unique ID of the leader name node
+	IP           string `json:"ip"`            //IP of the leader name node host
+	Port         string `json:"port"`          //Port on which the leader communicates with clients
+	InternalPort string `json:"internal_port"` //Port on which the leader communicates with the data nodes
+}
+
+// InternalAddress A function to get the address on which the leader communicates with the data nodes
+func (leader LeaderInfo) InternalAddress() string {
+	return GetInternalAddress(leader.IP, leader.InternalPort)
+}
+
+// URL A function to get the base URL on which the leader serves clients
+func (leader LeaderInfo) URL() string {
+	return fmt.Sprintf("http://%s:%s", leader.IP, leader.Port)
+}
+
+// RunLeaderElection A function to periodically renew the leader lease while holding it, or campaign for it otherwise,
+// the lease lives in redis, so the leader is replaced by a follower once it stops renewing it
+func (nameNode *NameNode) RunLeaderElection() {
+	nameNode.campaign()
+
+	for range time.Tick(nameNode.LeaderRenewInterval) {
+		nameNode.campaign()
+	}
+}
+
+// IsLeader A function to check if the name node currently holds the leader lease
+func (nameNode *NameNode) IsLeader() bool {
+	return atomic.LoadInt32(&nameNode.leader) == 1
+}
+
+// GetLeader A function to get the name node holding the leader lease
+func (nameNode *NameNode) GetLeader() (LeaderInfo, error) {
+	var leader LeaderInfo
+
+	encodedLeader, err := nameNode.cache.Get(nameNode.leaderLeaseKey).Result()
+	if errors.IsError(err) {
+		return leader, err
+	}
+
+	err = json.Unmarshal([]byte(encodedLeader), &leader)
+
+	return leader, err
+}
+
+// campaign A function to renew the leader lease if held by the name node, or acquire it if it's free
+func (nameNode *NameNode) campaign() {
+	encodedLeader, err := json.Marshal(LeaderInfo{
+		ID:           nameNode.ID,
+		IP:           nameNode.IP,
+		Port:         nameNode.Port,
+		InternalPort: nameNode.InternalPort,
+	})
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to marshal leader info", err)
+		return
+	}
+
+	if nameNode.IsLeader() {
+		renewed, err := nameNode.cache.Eval(renewLeaseScript, []string{nameNode.leaderLeaseKey},
+			string(encodedLeader), nameNode.leaderLeaseTTL.Nanoseconds()/int64(time.Millisecond)).Result()
+		if !errors.IsError(err) && renewed == int64(1) {
+			return
+		}
+
+		// the lease may have expired, so the name node can't be sure it's still the only leader
+		log.Println(logPrefix, fmt.Sprintf("Name node %s lost the leader lease", nameNode.ID), err)
+		atomic.StoreInt32(&nameNode.leader, 0)
+	}
+
+	acquired, err := nameNode.cache.SetNX(nameNode.leaderLeaseKey, string(encodedLeader), nameNode.leaderLeaseTTL).Result()
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to campaign for the leader lease", err)
+		return
+	}
+
+	if acquired {
+		log.Println(logPrefix, fmt.Sprintf("Name node %s is the leader", nameNode.ID))
+		atomic.StoreInt32(&nameNode.leader, 1)
+	}
+}
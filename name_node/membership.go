@@ -0,0 +1,92 @@
+package namenode
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+// InventoryEntry Represents a file copy held by a data node, as reported by the data node itself
+type InventoryEntry struct {
+	Token     string `json:"token"`     //Token of the file copy
+	Parent    string `json:"parent"`    //Token of the original file
+	Checksum  string `json:"checksum"`  //SHA-256 digest of the copy computed from disk by the last scrub (hex), empty if never scrubbed
+	Size      int64  `json:"size"`      //Size of the file in bytes
+	Completed bool   `json:"completed"` //Indicates if all the chunks of the copy were received
+	Stream    bool   `json:"stream"`    //Indicates if the HLS playlist of the file is on disk
+	Thumbnail bool   `json:"thumbnail"` //Indicates if the thumbnail of the file is on disk
+}
+
+// RecordHeartbeat A function to record the capacity and load sent in the heartbeat of a data node,
+// it returns false if the data node isn't registered, so it should join the cluster again
+func (nameNode *NameNode) RecordHeartbeat(id string, healthy bool, load NodeLoad) bool {
+	// an unhealthy node is kept counting missed pings, so it's taken out if it doesn't recover
+	if !healthy {
+		_, found := nameNode.GetDataNodeData(id)
+		return found
+	}
+
+	_, found := nameNode.UpdateDataNodeData(id, func(dataNodeData *DataNodeData) {
+		dataNodeData.updateLoad(load)
+	})
+
+	return found
+}
+
+// SaveInventory A function to replace the file copies recorded for a data node by the ones it reported on registration
+func (nameNode *NameNode) SaveInventory(id string, inventory []InventoryEntry) error {
+	key := nameNode.getInventoryKey(id)
+
+	err := nameNode.cache.Del(key).Err()
+	if errors.IsError(err) {
+		return err
+	}
+
+	if len(inventory) == 0 {
+		return nil
+	}
+
+	fields := make(map[string]string)
+	for _, entry := range inventory {
+		encodedEntry, err := json.Marshal(entry)
+		if errors.IsError(err) {
+			return err
+		}
+
+		fields[entry.Token] = string(encodedEntry)
+	}
+
+	return nameNode.cache.HMSet(key, fields).Err()
+}
+
+// GetInventory A function to get the file copies recorded for a data node
+func (nameNode *NameNode) GetInventory(id string) ([]InventoryEntry, error) {
+	var inventory []InventoryEntry
+
+	encodedEntries, err := nameNode.getAllFromHash(nameNode.getInventoryKey(id))
+	if errors.IsError(err) {
+		return inventory, err
+	}
+
+	for _, encodedEntry := range encodedEntries {
+		var entry InventoryEntry
+
+		err := json.Unmarshal([]byte(encodedEntry), &entry)
+		if errors.IsError(err) {
+			log.Println(logPrefix, "Unable to decode inventory entry", encodedEntry)
+
+			continue
+		}
+
+		inventory = append(inventory, entry)
+	}
+
+	return inventory, nil
+}
+
+// getInventoryKey A function to get the key of the redis hash tracking the file copies held by a data node
+func (nameNode *NameNode) getInventoryKey(id string) string {
+	return fmt.Sprintf("%s:%s", nameNode.inventoryKeyPrefix, id)
+}
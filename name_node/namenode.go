@@ -30,17 +30,43 @@ func NodeInstance() *NameNode {
 
 	nameNodeOnce.Do(func() {
 		nameNode := NameNode{
+			ID:                       nameNodeConfig.ID,
 			IP:                       nameNodeConfig.IP,
 			InternalPort:             nameNodeConfig.InternalRequestsPort,
+			Port:                     nameNodeConfig.Port,
 			dataNodesTrackingKey:     nameNodeConfig.DataNodesTrackingKey,
 			dataNodeOfflineThreshold: nameNodeConfig.DataNodeOfflineThreshold,
 			InteralReqTimeout:        time.Duration(nameNodeConfig.InternalReqTimeout) * time.Second,
 			HealthCheckInterval:      time.Duration(nameNodeConfig.HealthCheckInterval) * time.Second,
+			replicationJobsKey:       nameNodeConfig.ReplicationJobsKey,
+			ReReplicationInterval:    time.Duration(nameNodeConfig.ReReplicationInterval) * time.Second,
+			reReplicationRetries:     nameNodeConfig.ReReplicationRetries,
+			reReplicationTimeout:     time.Duration(nameNodeConfig.ReReplicationTimeout) * time.Second,
+			replicationJobsRetention: time.Duration(nameNodeConfig.ReplicationJobsRetention) * time.Second,
+			scrubChecksumsKey:        nameNodeConfig.ScrubChecksumsKey,
+			scrubResultsKey:          nameNodeConfig.ScrubResultsKey,
+			scrubMetricsKey:          nameNodeConfig.ScrubMetricsKey,
+			fileExpectationsKey:      nameNodeConfig.FileExpectationsKey,
+			nodeStatesKey:            nameNodeConfig.NodeStatesKey,
+			DecommissionInterval:     time.Duration(nameNodeConfig.DecommissionInterval) * time.Second,
+			leaderLeaseKey:           nameNodeConfig.LeaderLeaseKey,
+			inventoryKeyPrefix:       nameNodeConfig.InventoryKeyPrefix,
+			locationsKeyPrefix:       nameNodeConfig.LocationsKeyPrefix,
+			discrepanciesKey:         nameNodeConfig.DiscrepanciesKey,
+			pendingDeletionsKey:      nameNodeConfig.PendingDeletionsKey,
+			leaderLeaseTTL:           time.Duration(nameNodeConfig.LeaderLeaseTTL) * time.Second,
+			LeaderRenewInterval:      time.Duration(nameNodeConfig.LeaderRenewInterval) * time.Second,
 			cache:                    cacheInstance,
 			DB:                       database.DBInstance(nameNodeConfig.StorageDBName),
 		}
 
+		if nameNode.ID == "" {
+			nameNode.ID = GetInternalAddress(nameNode.IP, nameNode.InternalPort)
+		}
+
 		nameNode.DB.Connection.AutoMigrate(&Clip{})
+		// the clips of a video are looked up by tag and time range when streaming
+		nameNode.DB.Connection.Model(&Clip{}).AddIndex("idx_clips_token_tag_start_end", "token", "tag", "start_time", "end_time")
 
 		nameNodeInstance = &nameNode
 	})
@@ -27,18 +27,21 @@ const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 type JoinClusterResponse_JoinStatus int32
 
 const (
-	JoinClusterResponse_SUCCESS JoinClusterResponse_JoinStatus = 0
-	JoinClusterResponse_FAILURE JoinClusterResponse_JoinStatus = 1
+	JoinClusterResponse_SUCCESS    JoinClusterResponse_JoinStatus = 0
+	JoinClusterResponse_FAILURE    JoinClusterResponse_JoinStatus = 1
+	JoinClusterResponse_NOT_LEADER JoinClusterResponse_JoinStatus = 2
 )
 
 var JoinClusterResponse_JoinStatus_name = map[int32]string{
 	0: "SUCCESS",
 	1: "FAILURE",
+	2: "NOT_LEADER",
 }
 
 var JoinClusterResponse_JoinStatus_value = map[string]int32{
-	"SUCCESS": 0,
-	"FAILURE": 1,
+	"SUCCESS":    0,
+	"FAILURE":    1,
+	"NOT_LEADER": 2,
 }
 
 func (x JoinClusterResponse_JoinStatus) String() string {
@@ -46,18 +49,111 @@ func (x JoinClusterResponse_JoinStatus) String() string {
 }
 
 func (JoinClusterResponse_JoinStatus) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_38481b258f86a690, []int{1, 0}
+	return fileDescriptor_38481b258f86a690, []int{2, 0}
+}
+
+type ReportChecksumsResponse_ReportStatus int32
+
+const (
+	ReportChecksumsResponse_SUCCESS    ReportChecksumsResponse_ReportStatus = 0
+	ReportChecksumsResponse_FAILURE    ReportChecksumsResponse_ReportStatus = 1
+	ReportChecksumsResponse_NOT_LEADER ReportChecksumsResponse_ReportStatus = 2
+)
+
+var ReportChecksumsResponse_ReportStatus_name = map[int32]string{
+	0: "SUCCESS",
+	1: "FAILURE",
+	2: "NOT_LEADER",
+}
+
+var ReportChecksumsResponse_ReportStatus_value = map[string]int32{
+	"SUCCESS":    0,
+	"FAILURE":    1,
+	"NOT_LEADER": 2,
+}
+
+func (x ReportChecksumsResponse_ReportStatus) String() string {
+	return proto.EnumName(ReportChecksumsResponse_ReportStatus_name, int32(x))
+}
+
+func (ReportChecksumsResponse_ReportStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_38481b258f86a690, []int{5, 0}
+}
+
+type HeartbeatResponse_HeartbeatStatus int32
+
+const (
+	HeartbeatResponse_ACKNOWLEDGED HeartbeatResponse_HeartbeatStatus = 0
+	HeartbeatResponse_UNKNOWN_NODE HeartbeatResponse_HeartbeatStatus = 1
+	HeartbeatResponse_NOT_LEADER   HeartbeatResponse_HeartbeatStatus = 2
+)
+
+var HeartbeatResponse_HeartbeatStatus_name = map[int32]string{
+	0: "ACKNOWLEDGED",
+	1: "UNKNOWN_NODE",
+	2: "NOT_LEADER",
+}
+
+var HeartbeatResponse_HeartbeatStatus_value = map[string]int32{
+	"ACKNOWLEDGED": 0,
+	"UNKNOWN_NODE": 1,
+	"NOT_LEADER":   2,
+}
+
+func (x HeartbeatResponse_HeartbeatStatus) String() string {
+	return proto.EnumName(HeartbeatResponse_HeartbeatStatus_name, int32(x))
+}
+
+func (HeartbeatResponse_HeartbeatStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_38481b258f86a690, []int{7, 0}
+}
+
+type BlockReportResponse_ReportStatus int32
+
+const (
+	BlockReportResponse_SUCCESS      BlockReportResponse_ReportStatus = 0
+	BlockReportResponse_FAILURE      BlockReportResponse_ReportStatus = 1
+	BlockReportResponse_NOT_LEADER   BlockReportResponse_ReportStatus = 2
+	BlockReportResponse_UNKNOWN_NODE BlockReportResponse_ReportStatus = 3
+)
+
+var BlockReportResponse_ReportStatus_name = map[int32]string{
+	0: "SUCCESS",
+	1: "FAILURE",
+	2: "NOT_LEADER",
+	3: "UNKNOWN_NODE",
+}
+
+var BlockReportResponse_ReportStatus_value = map[string]int32{
+	"SUCCESS":      0,
+	"FAILURE":      1,
+	"NOT_LEADER":   2,
+	"UNKNOWN_NODE": 3,
+}
+
+func (x BlockReportResponse_ReportStatus) String() string {
+	return proto.EnumName(BlockReportResponse_ReportStatus_name, int32(x))
+}
+
+func (BlockReportResponse_ReportStatus) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_38481b258f86a690, []int{9, 0}
 }
 
 type JoinClusterRequest struct {
-	ID                   string   `protobuf:"bytes,1,opt,name=ID,json=iD,proto3" json:"ID,omitempty"`
-	IP                   string   `protobuf:"bytes,2,opt,name=IP,json=iP,proto3" json:"IP,omitempty"`
-	Port                 string   `protobuf:"bytes,3,opt,name=Port,json=port,proto3" json:"Port,omitempty"`
-	InternalPort         string   `protobuf:"bytes,4,opt,name=InternalPort,json=internalPort,proto3" json:"InternalPort,omitempty"`
-	GPU                  bool     `protobuf:"varint,5,opt,name=GPU,json=gPU,proto3" json:"GPU,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	ID                   string            `protobuf:"bytes,1,opt,name=ID,json=iD,proto3" json:"ID,omitempty"`
+	IP                   string            `protobuf:"bytes,2,opt,name=IP,json=iP,proto3" json:"IP,omitempty"`
+	Port                 string            `protobuf:"bytes,3,opt,name=Port,json=port,proto3" json:"Port,omitempty"`
+	InternalPort         string            `protobuf:"bytes,4,opt,name=InternalPort,json=internalPort,proto3" json:"InternalPort,omitempty"`
+	GPU                  bool              `protobuf:"varint,5,opt,name=GPU,json=gPU,proto3" json:"GPU,omitempty"`
+	Zone                 string            `protobuf:"bytes,6,opt,name=Zone,json=zone,proto3" json:"Zone,omitempty"`
+	Rack                 string            `protobuf:"bytes,7,opt,name=Rack,json=rack,proto3" json:"Rack,omitempty"`
+	DiskClass            string            `protobuf:"bytes,8,opt,name=DiskClass,json=diskClass,proto3" json:"DiskClass,omitempty"`
+	FreeDisk             uint64            `protobuf:"varint,9,opt,name=FreeDisk,json=freeDisk,proto3" json:"FreeDisk,omitempty"`
+	TotalDisk            uint64            `protobuf:"varint,10,opt,name=TotalDisk,json=totalDisk,proto3" json:"TotalDisk,omitempty"`
+	Inventory            []*InventoryEntry `protobuf:"bytes,11,rep,name=Inventory,json=inventory,proto3" json:"Inventory,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *JoinClusterRequest) Reset()         { *m = JoinClusterRequest{} }
@@ -120,8 +216,138 @@ func (m *JoinClusterRequest) GetGPU() bool {
 	return false
 }
 
+func (m *JoinClusterRequest) GetZone() string {
+	if m != nil {
+		return m.Zone
+	}
+	return ""
+}
+
+func (m *JoinClusterRequest) GetRack() string {
+	if m != nil {
+		return m.Rack
+	}
+	return ""
+}
+
+func (m *JoinClusterRequest) GetDiskClass() string {
+	if m != nil {
+		return m.DiskClass
+	}
+	return ""
+}
+
+func (m *JoinClusterRequest) GetFreeDisk() uint64 {
+	if m != nil {
+		return m.FreeDisk
+	}
+	return 0
+}
+
+func (m *JoinClusterRequest) GetTotalDisk() uint64 {
+	if m != nil {
+		return m.TotalDisk
+	}
+	return 0
+}
+
+func (m *JoinClusterRequest) GetInventory() []*InventoryEntry {
+	if m != nil {
+		return m.Inventory
+	}
+	return nil
+}
+
+type InventoryEntry struct {
+	Token                string   `protobuf:"bytes,1,opt,name=Token,json=token,proto3" json:"Token,omitempty"`
+	Parent               string   `protobuf:"bytes,2,opt,name=Parent,json=parent,proto3" json:"Parent,omitempty"`
+	Checksum             string   `protobuf:"bytes,3,opt,name=Checksum,json=checksum,proto3" json:"Checksum,omitempty"`
+	Size                 int64    `protobuf:"varint,4,opt,name=Size,json=size,proto3" json:"Size,omitempty"`
+	Completed            bool     `protobuf:"varint,5,opt,name=Completed,json=completed,proto3" json:"Completed,omitempty"`
+	Stream               bool     `protobuf:"varint,6,opt,name=Stream,json=stream,proto3" json:"Stream,omitempty"`
+	Thumbnail            bool     `protobuf:"varint,7,opt,name=Thumbnail,json=thumbnail,proto3" json:"Thumbnail,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *InventoryEntry) Reset()         { *m = InventoryEntry{} }
+func (m *InventoryEntry) String() string { return proto.CompactTextString(m) }
+func (*InventoryEntry) ProtoMessage()    {}
+func (*InventoryEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_38481b258f86a690, []int{1}
+}
+
+func (m *InventoryEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_InventoryEntry.Unmarshal(m, b)
+}
+func (m *InventoryEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_InventoryEntry.Marshal(b, m, deterministic)
+}
+func (m *InventoryEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_InventoryEntry.Merge(m, src)
+}
+func (m *InventoryEntry) XXX_Size() int {
+	return xxx_messageInfo_InventoryEntry.Size(m)
+}
+func (m *InventoryEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_InventoryEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_InventoryEntry proto.InternalMessageInfo
+
+func (m *InventoryEntry) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *InventoryEntry) GetParent() string {
+	if m != nil {
+		return m.Parent
+	}
+	return ""
+}
+
+func (m *InventoryEntry) GetChecksum() string {
+	if m != nil {
+		return m.Checksum
+	}
+	return ""
+}
+
+func (m *InventoryEntry) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *InventoryEntry) GetCompleted() bool {
+	if m != nil {
+		return m.Completed
+	}
+	return false
+}
+
+func (m *InventoryEntry) GetStream() bool {
+	if m != nil {
+		return m.Stream
+	}
+	return false
+}
+
+func (m *InventoryEntry) GetThumbnail() bool {
+	if m != nil {
+		return m.Thumbnail
+	}
+	return false
+}
+
 type JoinClusterResponse struct {
 	Status               JoinClusterResponse_JoinStatus `protobuf:"varint,1,opt,name=Status,json=status,proto3,enum=nnpb.JoinClusterResponse_JoinStatus" json:"Status,omitempty"`
+	LeaderAddress        string                         `protobuf:"bytes,2,opt,name=LeaderAddress,json=leaderAddress,proto3" json:"LeaderAddress,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}                       `json:"-"`
 	XXX_unrecognized     []byte                         `json:"-"`
 	XXX_sizecache        int32                          `json:"-"`
@@ -131,7 +357,7 @@ func (m *JoinClusterResponse) Reset()         { *m = JoinClusterResponse{} }
 func (m *JoinClusterResponse) String() string { return proto.CompactTextString(m) }
 func (*JoinClusterResponse) ProtoMessage()    {}
 func (*JoinClusterResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_38481b258f86a690, []int{1}
+	return fileDescriptor_38481b258f86a690, []int{2}
 }
 
 func (m *JoinClusterResponse) XXX_Unmarshal(b []byte) error {
@@ -159,10 +385,469 @@ func (m *JoinClusterResponse) GetStatus() JoinClusterResponse_JoinStatus {
 	return JoinClusterResponse_SUCCESS
 }
 
+func (m *JoinClusterResponse) GetLeaderAddress() string {
+	if m != nil {
+		return m.LeaderAddress
+	}
+	return ""
+}
+
+type FileChecksum struct {
+	Token                string   `protobuf:"bytes,1,opt,name=Token,json=token,proto3" json:"Token,omitempty"`
+	Parent               string   `protobuf:"bytes,2,opt,name=Parent,json=parent,proto3" json:"Parent,omitempty"`
+	Checksum             string   `protobuf:"bytes,3,opt,name=Checksum,json=checksum,proto3" json:"Checksum,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FileChecksum) Reset()         { *m = FileChecksum{} }
+func (m *FileChecksum) String() string { return proto.CompactTextString(m) }
+func (*FileChecksum) ProtoMessage()    {}
+func (*FileChecksum) Descriptor() ([]byte, []int) {
+	return fileDescriptor_38481b258f86a690, []int{3}
+}
+
+func (m *FileChecksum) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FileChecksum.Unmarshal(m, b)
+}
+func (m *FileChecksum) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FileChecksum.Marshal(b, m, deterministic)
+}
+func (m *FileChecksum) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FileChecksum.Merge(m, src)
+}
+func (m *FileChecksum) XXX_Size() int {
+	return xxx_messageInfo_FileChecksum.Size(m)
+}
+func (m *FileChecksum) XXX_DiscardUnknown() {
+	xxx_messageInfo_FileChecksum.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FileChecksum proto.InternalMessageInfo
+
+func (m *FileChecksum) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *FileChecksum) GetParent() string {
+	if m != nil {
+		return m.Parent
+	}
+	return ""
+}
+
+func (m *FileChecksum) GetChecksum() string {
+	if m != nil {
+		return m.Checksum
+	}
+	return ""
+}
+
+type ReportChecksumsRequest struct {
+	DataNodeID           string          `protobuf:"bytes,1,opt,name=DataNodeID,json=dataNodeID,proto3" json:"DataNodeID,omitempty"`
+	Checksums            []*FileChecksum `protobuf:"bytes,2,rep,name=Checksums,json=checksums,proto3" json:"Checksums,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *ReportChecksumsRequest) Reset()         { *m = ReportChecksumsRequest{} }
+func (m *ReportChecksumsRequest) String() string { return proto.CompactTextString(m) }
+func (*ReportChecksumsRequest) ProtoMessage()    {}
+func (*ReportChecksumsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_38481b258f86a690, []int{4}
+}
+
+func (m *ReportChecksumsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReportChecksumsRequest.Unmarshal(m, b)
+}
+func (m *ReportChecksumsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReportChecksumsRequest.Marshal(b, m, deterministic)
+}
+func (m *ReportChecksumsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReportChecksumsRequest.Merge(m, src)
+}
+func (m *ReportChecksumsRequest) XXX_Size() int {
+	return xxx_messageInfo_ReportChecksumsRequest.Size(m)
+}
+func (m *ReportChecksumsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReportChecksumsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReportChecksumsRequest proto.InternalMessageInfo
+
+func (m *ReportChecksumsRequest) GetDataNodeID() string {
+	if m != nil {
+		return m.DataNodeID
+	}
+	return ""
+}
+
+func (m *ReportChecksumsRequest) GetChecksums() []*FileChecksum {
+	if m != nil {
+		return m.Checksums
+	}
+	return nil
+}
+
+type ReportChecksumsResponse struct {
+	Status               ReportChecksumsResponse_ReportStatus `protobuf:"varint,1,opt,name=Status,json=status,proto3,enum=nnpb.ReportChecksumsResponse_ReportStatus" json:"Status,omitempty"`
+	MismatchCount        int32                                `protobuf:"varint,2,opt,name=MismatchCount,json=mismatchCount,proto3" json:"MismatchCount,omitempty"`
+	LeaderAddress        string                               `protobuf:"bytes,3,opt,name=LeaderAddress,json=leaderAddress,proto3" json:"LeaderAddress,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                             `json:"-"`
+	XXX_unrecognized     []byte                               `json:"-"`
+	XXX_sizecache        int32                                `json:"-"`
+}
+
+func (m *ReportChecksumsResponse) Reset()         { *m = ReportChecksumsResponse{} }
+func (m *ReportChecksumsResponse) String() string { return proto.CompactTextString(m) }
+func (*ReportChecksumsResponse) ProtoMessage()    {}
+func (*ReportChecksumsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_38481b258f86a690, []int{5}
+}
+
+func (m *ReportChecksumsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReportChecksumsResponse.Unmarshal(m, b)
+}
+func (m *ReportChecksumsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReportChecksumsResponse.Marshal(b, m, deterministic)
+}
+func (m *ReportChecksumsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReportChecksumsResponse.Merge(m, src)
+}
+func (m *ReportChecksumsResponse) XXX_Size() int {
+	return xxx_messageInfo_ReportChecksumsResponse.Size(m)
+}
+func (m *ReportChecksumsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReportChecksumsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReportChecksumsResponse proto.InternalMessageInfo
+
+func (m *ReportChecksumsResponse) GetStatus() ReportChecksumsResponse_ReportStatus {
+	if m != nil {
+		return m.Status
+	}
+	return ReportChecksumsResponse_SUCCESS
+}
+
+func (m *ReportChecksumsResponse) GetMismatchCount() int32 {
+	if m != nil {
+		return m.MismatchCount
+	}
+	return 0
+}
+
+func (m *ReportChecksumsResponse) GetLeaderAddress() string {
+	if m != nil {
+		return m.LeaderAddress
+	}
+	return ""
+}
+
+type HeartbeatRequest struct {
+	DataNodeID           string   `protobuf:"bytes,1,opt,name=DataNodeID,json=dataNodeID,proto3" json:"DataNodeID,omitempty"`
+	Healthy              bool     `protobuf:"varint,2,opt,name=Healthy,json=healthy,proto3" json:"Healthy,omitempty"`
+	FreeDisk             uint64   `protobuf:"varint,3,opt,name=FreeDisk,json=freeDisk,proto3" json:"FreeDisk,omitempty"`
+	UsedDisk             uint64   `protobuf:"varint,4,opt,name=UsedDisk,json=usedDisk,proto3" json:"UsedDisk,omitempty"`
+	TotalDisk            uint64   `protobuf:"varint,5,opt,name=TotalDisk,json=totalDisk,proto3" json:"TotalDisk,omitempty"`
+	FileCount            int64    `protobuf:"varint,6,opt,name=FileCount,json=fileCount,proto3" json:"FileCount,omitempty"`
+	RunningJobs          int32    `protobuf:"varint,7,opt,name=RunningJobs,json=runningJobs,proto3" json:"RunningJobs,omitempty"`
+	QueuedJobs           int32    `protobuf:"varint,8,opt,name=QueuedJobs,json=queuedJobs,proto3" json:"QueuedJobs,omitempty"`
+	InFlightUploads      int32    `protobuf:"varint,9,opt,name=InFlightUploads,json=inFlightUploads,proto3" json:"InFlightUploads,omitempty"`
+	GPU                  bool     `protobuf:"varint,10,opt,name=GPU,json=gPU,proto3" json:"GPU,omitempty"`
+	GPUAvailable         bool     `protobuf:"varint,11,opt,name=GPUAvailable,json=gPUAvailable,proto3" json:"GPUAvailable,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *HeartbeatRequest) Reset()         { *m = HeartbeatRequest{} }
+func (m *HeartbeatRequest) String() string { return proto.CompactTextString(m) }
+func (*HeartbeatRequest) ProtoMessage()    {}
+func (*HeartbeatRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_38481b258f86a690, []int{6}
+}
+
+func (m *HeartbeatRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HeartbeatRequest.Unmarshal(m, b)
+}
+func (m *HeartbeatRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HeartbeatRequest.Marshal(b, m, deterministic)
+}
+func (m *HeartbeatRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HeartbeatRequest.Merge(m, src)
+}
+func (m *HeartbeatRequest) XXX_Size() int {
+	return xxx_messageInfo_HeartbeatRequest.Size(m)
+}
+func (m *HeartbeatRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_HeartbeatRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HeartbeatRequest proto.InternalMessageInfo
+
+func (m *HeartbeatRequest) GetDataNodeID() string {
+	if m != nil {
+		return m.DataNodeID
+	}
+	return ""
+}
+
+func (m *HeartbeatRequest) GetHealthy() bool {
+	if m != nil {
+		return m.Healthy
+	}
+	return false
+}
+
+func (m *HeartbeatRequest) GetFreeDisk() uint64 {
+	if m != nil {
+		return m.FreeDisk
+	}
+	return 0
+}
+
+func (m *HeartbeatRequest) GetUsedDisk() uint64 {
+	if m != nil {
+		return m.UsedDisk
+	}
+	return 0
+}
+
+func (m *HeartbeatRequest) GetTotalDisk() uint64 {
+	if m != nil {
+		return m.TotalDisk
+	}
+	return 0
+}
+
+func (m *HeartbeatRequest) GetFileCount() int64 {
+	if m != nil {
+		return m.FileCount
+	}
+	return 0
+}
+
+func (m *HeartbeatRequest) GetRunningJobs() int32 {
+	if m != nil {
+		return m.RunningJobs
+	}
+	return 0
+}
+
+func (m *HeartbeatRequest) GetQueuedJobs() int32 {
+	if m != nil {
+		return m.QueuedJobs
+	}
+	return 0
+}
+
+func (m *HeartbeatRequest) GetInFlightUploads() int32 {
+	if m != nil {
+		return m.InFlightUploads
+	}
+	return 0
+}
+
+func (m *HeartbeatRequest) GetGPU() bool {
+	if m != nil {
+		return m.GPU
+	}
+	return false
+}
+
+func (m *HeartbeatRequest) GetGPUAvailable() bool {
+	if m != nil {
+		return m.GPUAvailable
+	}
+	return false
+}
+
+type HeartbeatResponse struct {
+	Status               HeartbeatResponse_HeartbeatStatus `protobuf:"varint,1,opt,name=Status,json=status,proto3,enum=nnpb.HeartbeatResponse_HeartbeatStatus" json:"Status,omitempty"`
+	LeaderAddress        string                            `protobuf:"bytes,2,opt,name=LeaderAddress,json=leaderAddress,proto3" json:"LeaderAddress,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                          `json:"-"`
+	XXX_unrecognized     []byte                            `json:"-"`
+	XXX_sizecache        int32                             `json:"-"`
+}
+
+func (m *HeartbeatResponse) Reset()         { *m = HeartbeatResponse{} }
+func (m *HeartbeatResponse) String() string { return proto.CompactTextString(m) }
+func (*HeartbeatResponse) ProtoMessage()    {}
+func (*HeartbeatResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_38481b258f86a690, []int{7}
+}
+
+func (m *HeartbeatResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_HeartbeatResponse.Unmarshal(m, b)
+}
+func (m *HeartbeatResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_HeartbeatResponse.Marshal(b, m, deterministic)
+}
+func (m *HeartbeatResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_HeartbeatResponse.Merge(m, src)
+}
+func (m *HeartbeatResponse) XXX_Size() int {
+	return xxx_messageInfo_HeartbeatResponse.Size(m)
+}
+func (m *HeartbeatResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_HeartbeatResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_HeartbeatResponse proto.InternalMessageInfo
+
+func (m *HeartbeatResponse) GetStatus() HeartbeatResponse_HeartbeatStatus {
+	if m != nil {
+		return m.Status
+	}
+	return HeartbeatResponse_ACKNOWLEDGED
+}
+
+func (m *HeartbeatResponse) GetLeaderAddress() string {
+	if m != nil {
+		return m.LeaderAddress
+	}
+	return ""
+}
+
+type BlockReportRequest struct {
+	DataNodeID           string            `protobuf:"bytes,1,opt,name=DataNodeID,json=dataNodeID,proto3" json:"DataNodeID,omitempty"`
+	Inventory            []*InventoryEntry `protobuf:"bytes,2,rep,name=Inventory,json=inventory,proto3" json:"Inventory,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *BlockReportRequest) Reset()         { *m = BlockReportRequest{} }
+func (m *BlockReportRequest) String() string { return proto.CompactTextString(m) }
+func (*BlockReportRequest) ProtoMessage()    {}
+func (*BlockReportRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_38481b258f86a690, []int{8}
+}
+
+func (m *BlockReportRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockReportRequest.Unmarshal(m, b)
+}
+func (m *BlockReportRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockReportRequest.Marshal(b, m, deterministic)
+}
+func (m *BlockReportRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockReportRequest.Merge(m, src)
+}
+func (m *BlockReportRequest) XXX_Size() int {
+	return xxx_messageInfo_BlockReportRequest.Size(m)
+}
+func (m *BlockReportRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockReportRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockReportRequest proto.InternalMessageInfo
+
+func (m *BlockReportRequest) GetDataNodeID() string {
+	if m != nil {
+		return m.DataNodeID
+	}
+	return ""
+}
+
+func (m *BlockReportRequest) GetInventory() []*InventoryEntry {
+	if m != nil {
+		return m.Inventory
+	}
+	return nil
+}
+
+type BlockReportResponse struct {
+	Status               BlockReportResponse_ReportStatus `protobuf:"varint,1,opt,name=Status,json=status,proto3,enum=nnpb.BlockReportResponse_ReportStatus" json:"Status,omitempty"`
+	LeaderAddress        string                           `protobuf:"bytes,2,opt,name=LeaderAddress,json=leaderAddress,proto3" json:"LeaderAddress,omitempty"`
+	MissingCount         int32                            `protobuf:"varint,3,opt,name=MissingCount,json=missingCount,proto3" json:"MissingCount,omitempty"`
+	ExtraCount           int32                            `protobuf:"varint,4,opt,name=ExtraCount,json=extraCount,proto3" json:"ExtraCount,omitempty"`
+	StaleCount           int32                            `protobuf:"varint,5,opt,name=StaleCount,json=staleCount,proto3" json:"StaleCount,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                         `json:"-"`
+	XXX_unrecognized     []byte                           `json:"-"`
+	XXX_sizecache        int32                            `json:"-"`
+}
+
+func (m *BlockReportResponse) Reset()         { *m = BlockReportResponse{} }
+func (m *BlockReportResponse) String() string { return proto.CompactTextString(m) }
+func (*BlockReportResponse) ProtoMessage()    {}
+func (*BlockReportResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_38481b258f86a690, []int{9}
+}
+
+func (m *BlockReportResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockReportResponse.Unmarshal(m, b)
+}
+func (m *BlockReportResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockReportResponse.Marshal(b, m, deterministic)
+}
+func (m *BlockReportResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockReportResponse.Merge(m, src)
+}
+func (m *BlockReportResponse) XXX_Size() int {
+	return xxx_messageInfo_BlockReportResponse.Size(m)
+}
+func (m *BlockReportResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockReportResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockReportResponse proto.InternalMessageInfo
+
+func (m *BlockReportResponse) GetStatus() BlockReportResponse_ReportStatus {
+	if m != nil {
+		return m.Status
+	}
+	return BlockReportResponse_SUCCESS
+}
+
+func (m *BlockReportResponse) GetLeaderAddress() string {
+	if m != nil {
+		return m.LeaderAddress
+	}
+	return ""
+}
+
+func (m *BlockReportResponse) GetMissingCount() int32 {
+	if m != nil {
+		return m.MissingCount
+	}
+	return 0
+}
+
+func (m *BlockReportResponse) GetExtraCount() int32 {
+	if m != nil {
+		return m.ExtraCount
+	}
+	return 0
+}
+
+func (m *BlockReportResponse) GetStaleCount() int32 {
+	if m != nil {
+		return m.StaleCount
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("nnpb.JoinClusterResponse_JoinStatus", JoinClusterResponse_JoinStatus_name, JoinClusterResponse_JoinStatus_value)
+	proto.RegisterEnum("nnpb.ReportChecksumsResponse_ReportStatus", ReportChecksumsResponse_ReportStatus_name, ReportChecksumsResponse_ReportStatus_value)
+	proto.RegisterEnum("nnpb.HeartbeatResponse_HeartbeatStatus", HeartbeatResponse_HeartbeatStatus_name, HeartbeatResponse_HeartbeatStatus_value)
+	proto.RegisterEnum("nnpb.BlockReportResponse_ReportStatus", BlockReportResponse_ReportStatus_name, BlockReportResponse_ReportStatus_value)
 	proto.RegisterType((*JoinClusterRequest)(nil), "nnpb.JoinClusterRequest")
+	proto.RegisterType((*InventoryEntry)(nil), "nnpb.InventoryEntry")
 	proto.RegisterType((*JoinClusterResponse)(nil), "nnpb.JoinClusterResponse")
+	proto.RegisterType((*FileChecksum)(nil), "nnpb.FileChecksum")
+	proto.RegisterType((*ReportChecksumsRequest)(nil), "nnpb.ReportChecksumsRequest")
+	proto.RegisterType((*ReportChecksumsResponse)(nil), "nnpb.ReportChecksumsResponse")
+	proto.RegisterType((*HeartbeatRequest)(nil), "nnpb.HeartbeatRequest")
+	proto.RegisterType((*HeartbeatResponse)(nil), "nnpb.HeartbeatResponse")
+	proto.RegisterType((*BlockReportRequest)(nil), "nnpb.BlockReportRequest")
+	proto.RegisterType((*BlockReportResponse)(nil), "nnpb.BlockReportResponse")
 }
 
 func init() {
@@ -170,24 +855,67 @@ func init() {
 }
 
 var fileDescriptor_38481b258f86a690 = []byte{
-	// 257 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x90, 0xcf, 0x4b, 0xfb, 0x40,
-	0x10, 0xc5, 0xbf, 0xf9, 0xf1, 0x8d, 0x3a, 0x2d, 0x25, 0x8e, 0x20, 0xab, 0xa7, 0x12, 0x44, 0x7a,
-	0xca, 0xa1, 0x5e, 0xbd, 0x68, 0x5a, 0x25, 0x22, 0x65, 0xd9, 0x90, 0x9b, 0x20, 0x29, 0x2e, 0x12,
-	0xa8, 0xbb, 0x71, 0x77, 0x72, 0x12, 0xfc, 0xdb, 0x25, 0xa3, 0x62, 0x85, 0x7a, 0x1a, 0xde, 0x67,
-	0x1e, 0xbc, 0xc7, 0x83, 0x43, 0x63, 0xba, 0xf5, 0xa3, 0xb3, 0x3d, 0x69, 0x9f, 0x77, 0xce, 0x92,
-	0xc5, 0x78, 0x40, 0xd9, 0x3b, 0xe0, 0x9d, 0x6d, 0x4d, 0xb1, 0xe9, 0x3d, 0x69, 0xa7, 0xf4, 0x6b,
-	0xaf, 0x3d, 0xe1, 0x04, 0xc2, 0x72, 0x21, 0x82, 0x69, 0x30, 0x3b, 0x50, 0x61, 0xbb, 0x60, 0x2d,
-	0x45, 0xf8, 0xa5, 0x25, 0x22, 0xc4, 0xd2, 0x3a, 0x12, 0x11, 0x93, 0xb8, 0xb3, 0x8e, 0x30, 0x83,
-	0x71, 0x69, 0x48, 0x3b, 0xd3, 0x6c, 0xf8, 0x17, 0xf3, 0x6f, 0xdc, 0x6e, 0x31, 0x4c, 0x21, 0xba,
-	0x95, 0xb5, 0xf8, 0x3f, 0x0d, 0x66, 0xfb, 0x2a, 0x7a, 0x96, 0x75, 0xf6, 0x06, 0x47, 0xbf, 0xf2,
-	0x7d, 0x67, 0x8d, 0xd7, 0x78, 0x09, 0x49, 0x45, 0x0d, 0xf5, 0x9e, 0x4b, 0x4c, 0xe6, 0x67, 0xf9,
-	0xd0, 0x36, 0xdf, 0x61, 0x65, 0xf6, 0xe9, 0x55, 0x89, 0xe7, 0x9b, 0x9d, 0x03, 0xfc, 0x50, 0x1c,
-	0xc1, 0x5e, 0x55, 0x17, 0xc5, 0xb2, 0xaa, 0xd2, 0x7f, 0x83, 0xb8, 0xb9, 0x2a, 0xef, 0x6b, 0xb5,
-	0x4c, 0x83, 0xf9, 0x03, 0x1c, 0xaf, 0x9a, 0x17, 0xbd, 0xb2, 0x4f, 0xfa, 0xbb, 0xba, 0xe2, 0x89,
-	0xf0, 0x1a, 0x46, 0x5b, 0x59, 0x28, 0x76, 0xc4, 0xf3, 0x52, 0xa7, 0x27, 0x7f, 0x16, 0x5b, 0x27,
-	0xbc, 0xf3, 0xc5, 0x47, 0x00, 0x00, 0x00, 0xff, 0xff, 0xa1, 0x88, 0xa4, 0xab, 0x7c, 0x01, 0x00,
-	0x00,
+	// 948 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x56, 0x5f, 0x6f, 0x1b, 0x45,
+	0x10, 0xaf, 0xcf, 0x7f, 0x72, 0x37, 0x76, 0x12, 0x77, 0x5b, 0xa5, 0xd7, 0x28, 0x20, 0xeb, 0x54,
+	0x81, 0xc5, 0x43, 0x84, 0x82, 0x84, 0x78, 0xa8, 0x40, 0x8e, 0xed, 0xa4, 0x0e, 0xa9, 0x63, 0xd6,
+	0xb1, 0x40, 0xbc, 0x44, 0x6b, 0xdf, 0xd6, 0x3e, 0x7c, 0xde, 0x75, 0x6f, 0xf7, 0x2a, 0x92, 0xaf,
+	0xc4, 0x03, 0x82, 0x6f, 0x00, 0x1f, 0x05, 0xbe, 0x08, 0xda, 0xdd, 0x3b, 0xe7, 0xfc, 0x0f, 0x42,
+	0xd5, 0x27, 0x6b, 0x7e, 0x33, 0x37, 0xbb, 0xf3, 0x9b, 0xdf, 0xcc, 0x1a, 0x1e, 0x33, 0x36, 0x1f,
+	0xde, 0x44, 0x3c, 0x96, 0x54, 0x1c, 0xcf, 0x23, 0x2e, 0x39, 0x2a, 0x28, 0xc8, 0xfb, 0xdd, 0x02,
+	0x74, 0xc1, 0x03, 0xd6, 0x0c, 0x63, 0x21, 0x69, 0x84, 0xe9, 0xdb, 0x98, 0x0a, 0x89, 0xf6, 0xc0,
+	0xea, 0xb4, 0xdc, 0x5c, 0x2d, 0x57, 0x77, 0xb0, 0x15, 0xb4, 0xb4, 0xdd, 0x73, 0xad, 0xc4, 0xee,
+	0x21, 0x04, 0x85, 0x1e, 0x8f, 0xa4, 0x9b, 0xd7, 0x48, 0x61, 0xce, 0x23, 0x89, 0x3c, 0xa8, 0x74,
+	0x98, 0xa4, 0x11, 0x23, 0xa1, 0xf6, 0x15, 0xb4, 0xaf, 0x12, 0x64, 0x30, 0x54, 0x85, 0xfc, 0x79,
+	0x6f, 0xe0, 0x16, 0x6b, 0xb9, 0xba, 0x8d, 0xf3, 0xe3, 0xde, 0x40, 0x65, 0xfa, 0x91, 0x33, 0xea,
+	0x96, 0x4c, 0xa6, 0x3b, 0xce, 0xa8, 0xc2, 0x30, 0x19, 0x4d, 0xdd, 0x1d, 0x83, 0x45, 0x64, 0x34,
+	0x45, 0x47, 0xe0, 0xb4, 0x02, 0x31, 0x6d, 0x86, 0x44, 0x08, 0xd7, 0xd6, 0x0e, 0xc7, 0x4f, 0x01,
+	0x74, 0x08, 0xf6, 0x59, 0x44, 0xa9, 0x8a, 0x70, 0x9d, 0x5a, 0xae, 0x5e, 0xc0, 0xf6, 0x9b, 0xc4,
+	0x56, 0x5f, 0x5e, 0x73, 0x49, 0x42, 0xed, 0x04, 0xed, 0x74, 0x64, 0x0a, 0xa0, 0x13, 0x70, 0x3a,
+	0xec, 0x1d, 0x65, 0x92, 0x47, 0xb7, 0x6e, 0xb9, 0x96, 0xaf, 0x97, 0x4f, 0x9e, 0x1e, 0x2b, 0x6a,
+	0x8e, 0x17, 0x70, 0x9b, 0xc9, 0xe8, 0x16, 0x3b, 0x41, 0x6a, 0x7b, 0x7f, 0xe6, 0x60, 0x6f, 0xd9,
+	0x8b, 0x9e, 0x42, 0xf1, 0x9a, 0x4f, 0x29, 0x4b, 0x38, 0x2b, 0x4a, 0x65, 0xa0, 0x03, 0x28, 0xf5,
+	0x48, 0x44, 0x99, 0x4c, 0xa8, 0x2b, 0xcd, 0xb5, 0xa5, 0xae, 0xdb, 0x9c, 0xd0, 0xd1, 0x54, 0xc4,
+	0xb3, 0x84, 0x42, 0x7b, 0x94, 0xd8, 0xaa, 0xf8, 0x7e, 0x70, 0x47, 0x35, 0x7d, 0x79, 0x5c, 0x10,
+	0xc1, 0x1d, 0x55, 0x25, 0x34, 0xf9, 0x6c, 0x1e, 0x52, 0x49, 0xfd, 0x84, 0x3c, 0x67, 0x94, 0x02,
+	0xea, 0x94, 0xbe, 0x8c, 0x28, 0x99, 0x69, 0x12, 0x6d, 0x5c, 0x12, 0xda, 0xd2, 0x85, 0x4f, 0xe2,
+	0xd9, 0x90, 0x91, 0x20, 0xd4, 0x5c, 0xda, 0xd8, 0x91, 0x29, 0xe0, 0xfd, 0x96, 0x83, 0x27, 0x4b,
+	0x9d, 0x17, 0x73, 0xce, 0x04, 0x45, 0x2f, 0x55, 0x36, 0x22, 0x63, 0xa1, 0x4b, 0xd9, 0x3b, 0x79,
+	0x61, 0xd8, 0xd8, 0x10, 0xaa, 0x31, 0x13, 0xab, 0xce, 0x54, 0xbf, 0xe8, 0x05, 0xec, 0x5e, 0x52,
+	0xe2, 0xd3, 0xa8, 0xe1, 0xfb, 0x11, 0x15, 0x22, 0x29, 0x7c, 0x37, 0xcc, 0x82, 0xde, 0x97, 0x00,
+	0xf7, 0xdf, 0xa2, 0x32, 0xec, 0xf4, 0x07, 0xcd, 0x66, 0xbb, 0xdf, 0xaf, 0x3e, 0x52, 0xc6, 0x59,
+	0xa3, 0x73, 0x39, 0xc0, 0xed, 0x6a, 0x0e, 0xed, 0x01, 0x74, 0xaf, 0xae, 0x6f, 0x2e, 0xdb, 0x8d,
+	0x56, 0x1b, 0x57, 0x2d, 0xef, 0x07, 0xa8, 0x9c, 0x05, 0x21, 0x4d, 0xb9, 0xfb, 0x70, 0xac, 0x7b,
+	0x3f, 0xc1, 0x01, 0xa6, 0x4a, 0xc6, 0x69, 0x84, 0x48, 0x47, 0xe1, 0x63, 0x80, 0x16, 0x91, 0xa4,
+	0xcb, 0x7d, 0xba, 0x18, 0x09, 0xf0, 0x17, 0x08, 0xfa, 0x1c, 0x9c, 0xc5, 0x37, 0xae, 0xa5, 0x05,
+	0x84, 0x0c, 0x65, 0xd9, 0xab, 0x62, 0x27, 0x3d, 0x4a, 0x78, 0x7f, 0xe7, 0xe0, 0xd9, 0xda, 0x61,
+	0x09, 0xfb, 0xa7, 0x2b, 0xec, 0x7f, 0x66, 0x52, 0x6d, 0x09, 0x4f, 0xf0, 0xf5, 0x1e, 0xbc, 0x0e,
+	0xc4, 0x8c, 0xc8, 0xd1, 0xa4, 0xc9, 0xe3, 0x84, 0x86, 0x22, 0xde, 0x9d, 0x65, 0xc1, 0xf5, 0x4e,
+	0xe5, 0x37, 0x75, 0xea, 0x2b, 0xa8, 0x64, 0xcf, 0xf8, 0x1f, 0xbd, 0xfa, 0xcb, 0x82, 0xea, 0x2b,
+	0x4a, 0x22, 0x39, 0xa4, 0x44, 0x3e, 0x94, 0x4c, 0x17, 0x76, 0x5e, 0x51, 0x12, 0xca, 0xc9, 0xad,
+	0xbe, 0xb4, 0x8d, 0x77, 0x26, 0xc6, 0x5c, 0x9a, 0xf0, 0xfc, 0xca, 0x84, 0x1f, 0x82, 0x3d, 0x10,
+	0xd4, 0xd7, 0xbe, 0x82, 0xf1, 0xc5, 0x89, 0xbd, 0x3c, 0xfd, 0xc5, 0xd5, 0xe9, 0x3f, 0x02, 0x47,
+	0x77, 0x49, 0xd3, 0x54, 0xd2, 0x13, 0xe7, 0xbc, 0x49, 0x01, 0x54, 0x83, 0x32, 0x8e, 0x19, 0x0b,
+	0xd8, 0xf8, 0x82, 0x0f, 0x85, 0x1e, 0xa1, 0x22, 0x2e, 0x47, 0xf7, 0x90, 0xaa, 0xe7, 0xbb, 0x98,
+	0xc6, 0xd4, 0xd7, 0x01, 0xb6, 0x0e, 0x80, 0xb7, 0x0b, 0x04, 0xd5, 0x61, 0xbf, 0xc3, 0xce, 0xc2,
+	0x60, 0x3c, 0x91, 0x83, 0x79, 0xc8, 0x89, 0x2f, 0xf4, 0x7a, 0x2a, 0xe2, 0xfd, 0x60, 0x19, 0x4e,
+	0x37, 0x23, 0xdc, 0x6f, 0x46, 0x0f, 0x2a, 0xe7, 0xbd, 0x41, 0xe3, 0x1d, 0x09, 0x42, 0x32, 0x0c,
+	0xa9, 0x5b, 0xd6, 0xae, 0xca, 0x38, 0x83, 0x79, 0x7f, 0xe4, 0xe0, 0x71, 0x86, 0xe4, 0x44, 0x44,
+	0xdf, 0xac, 0x88, 0xe8, 0x53, 0x23, 0xa2, 0xb5, 0xc0, 0x7b, 0xe4, 0xbd, 0xa6, 0xb8, 0x0d, 0xfb,
+	0x2b, 0x09, 0x50, 0x15, 0x2a, 0x8d, 0xe6, 0xb7, 0xdd, 0xab, 0xef, 0x2f, 0xdb, 0xad, 0xf3, 0x76,
+	0xab, 0xfa, 0x48, 0x21, 0x83, 0xae, 0x42, 0xba, 0x37, 0xdd, 0xab, 0xd6, 0x26, 0xa1, 0x4c, 0x00,
+	0x9d, 0x86, 0x7c, 0x34, 0x35, 0x3a, 0x7b, 0xa8, 0x52, 0x96, 0xf6, 0xb6, 0xf5, 0xb0, 0xbd, 0xfd,
+	0x8b, 0x05, 0x4f, 0x96, 0x8e, 0x4a, 0xf8, 0xfa, 0x7a, 0x85, 0xaf, 0x4f, 0x4c, 0xa2, 0x0d, 0xa1,
+	0x5b, 0x07, 0xee, 0xbf, 0xe9, 0x52, 0xfd, 0x7c, 0x1d, 0x08, 0x11, 0xb0, 0xb1, 0x91, 0x5b, 0x5e,
+	0x0b, 0xa1, 0x32, 0xcb, 0x60, 0xaa, 0xea, 0xf6, 0xcf, 0x32, 0x22, 0x26, 0xa2, 0x60, 0xf4, 0x44,
+	0x17, 0x88, 0xf2, 0xf7, 0x25, 0x49, 0x05, 0x5b, 0x34, 0x7e, 0xb1, 0x40, 0xbc, 0x8b, 0xf7, 0x1d,
+	0xd7, 0xb5, 0x3e, 0xe5, 0x4f, 0x7e, 0xb5, 0xe0, 0xa0, 0x4b, 0x66, 0x54, 0x13, 0x9e, 0x3c, 0xe2,
+	0x58, 0xff, 0x83, 0x40, 0xa7, 0x50, 0xce, 0xbc, 0x07, 0xc8, 0xdd, 0xf0, 0x44, 0xe8, 0x2e, 0x1e,
+	0x3e, 0xdf, 0xfa, 0x78, 0xa0, 0x2e, 0xec, 0xaf, 0x6c, 0x35, 0x74, 0xb4, 0x65, 0xd9, 0x99, 0x5c,
+	0x1f, 0xfd, 0xeb, 0x2a, 0x44, 0x2f, 0xc1, 0x59, 0xa8, 0x11, 0x1d, 0xac, 0x29, 0xde, 0xe4, 0x78,
+	0xb6, 0x65, 0x12, 0x50, 0x33, 0x25, 0x4e, 0x37, 0x5d, 0xa4, 0x25, 0xad, 0x0b, 0xf3, 0xf0, 0xf9,
+	0x06, 0x8f, 0x49, 0x32, 0x2c, 0xe9, 0x7f, 0x56, 0x5f, 0xfc, 0x33, 0x00, 0xcf, 0xcd, 0x6c, 0xca,
+	0x6e, 0x09, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -203,6 +931,9 @@ const _ = grpc.SupportPackageIsVersion6
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type NameNodeInternalRoutesClient interface {
 	JoinCluster(ctx context.Context, in *JoinClusterRequest, opts ...grpc.CallOption) (*JoinClusterResponse, error)
+	ReportChecksums(ctx context.Context, in *ReportChecksumsRequest, opts ...grpc.CallOption) (*ReportChecksumsResponse, error)
+	Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error)
+	ReportBlocks(ctx context.Context, in *BlockReportRequest, opts ...grpc.CallOption) (*BlockReportResponse, error)
 }
 
 type nameNodeInternalRoutesClient struct {
@@ -222,9 +953,39 @@ func (c *nameNodeInternalRoutesClient) JoinCluster(ctx context.Context, in *Join
 	return out, nil
 }
 
+func (c *nameNodeInternalRoutesClient) ReportChecksums(ctx context.Context, in *ReportChecksumsRequest, opts ...grpc.CallOption) (*ReportChecksumsResponse, error) {
+	out := new(ReportChecksumsResponse)
+	err := c.cc.Invoke(ctx, "/nnpb.NameNodeInternalRoutes/ReportChecksums", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nameNodeInternalRoutesClient) Heartbeat(ctx context.Context, in *HeartbeatRequest, opts ...grpc.CallOption) (*HeartbeatResponse, error) {
+	out := new(HeartbeatResponse)
+	err := c.cc.Invoke(ctx, "/nnpb.NameNodeInternalRoutes/Heartbeat", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *nameNodeInternalRoutesClient) ReportBlocks(ctx context.Context, in *BlockReportRequest, opts ...grpc.CallOption) (*BlockReportResponse, error) {
+	out := new(BlockReportResponse)
+	err := c.cc.Invoke(ctx, "/nnpb.NameNodeInternalRoutes/ReportBlocks", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // NameNodeInternalRoutesServer is the server API for NameNodeInternalRoutes service.
 type NameNodeInternalRoutesServer interface {
 	JoinCluster(context.Context, *JoinClusterRequest) (*JoinClusterResponse, error)
+	ReportChecksums(context.Context, *ReportChecksumsRequest) (*ReportChecksumsResponse, error)
+	Heartbeat(context.Context, *HeartbeatRequest) (*HeartbeatResponse, error)
+	ReportBlocks(context.Context, *BlockReportRequest) (*BlockReportResponse, error)
 }
 
 // UnimplementedNameNodeInternalRoutesServer can be embedded to have forward compatible implementations.
@@ -234,6 +995,15 @@ type UnimplementedNameNodeInternalRoutesServer struct {
 func (*UnimplementedNameNodeInternalRoutesServer) JoinCluster(ctx context.Context, req *JoinClusterRequest) (*JoinClusterResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method JoinCluster not implemented")
 }
+func (*UnimplementedNameNodeInternalRoutesServer) ReportChecksums(ctx context.Context, req *ReportChecksumsRequest) (*ReportChecksumsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportChecksums not implemented")
+}
+func (*UnimplementedNameNodeInternalRoutesServer) Heartbeat(ctx context.Context, req *HeartbeatRequest) (*HeartbeatResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Heartbeat not implemented")
+}
+func (*UnimplementedNameNodeInternalRoutesServer) ReportBlocks(ctx context.Context, req *BlockReportRequest) (*BlockReportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportBlocks not implemented")
+}
 
 func RegisterNameNodeInternalRoutesServer(s *grpc.Server, srv NameNodeInternalRoutesServer) {
 	s.RegisterService(&_NameNodeInternalRoutes_serviceDesc, srv)
@@ -257,6 +1027,60 @@ func _NameNodeInternalRoutes_JoinCluster_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NameNodeInternalRoutes_ReportChecksums_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportChecksumsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NameNodeInternalRoutesServer).ReportChecksums(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/nnpb.NameNodeInternalRoutes/ReportChecksums",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NameNodeInternalRoutesServer).ReportChecksums(ctx, req.(*ReportChecksumsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NameNodeInternalRoutes_Heartbeat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HeartbeatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NameNodeInternalRoutesServer).Heartbeat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/nnpb.NameNodeInternalRoutes/Heartbeat",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NameNodeInternalRoutesServer).Heartbeat(ctx, req.(*HeartbeatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NameNodeInternalRoutes_ReportBlocks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BlockReportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NameNodeInternalRoutesServer).ReportBlocks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/nnpb.NameNodeInternalRoutes/ReportBlocks",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NameNodeInternalRoutesServer).ReportBlocks(ctx, req.(*BlockReportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _NameNodeInternalRoutes_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "nnpb.NameNodeInternalRoutes",
 	HandlerType: (*NameNodeInternalRoutesServer)(nil),
@@ -265,6 +1089,18 @@ var _NameNodeInternalRoutes_serviceDesc = grpc.ServiceDesc{
 			MethodName: "JoinCluster",
 			Handler:    _NameNodeInternalRoutes_JoinCluster_Handler,
 		},
+		{
+			MethodName: "ReportChecksums",
+			Handler:    _NameNodeInternalRoutes_ReportChecksums_Handler,
+		},
+		{
+			MethodName: "Heartbeat",
+			Handler:    _NameNodeInternalRoutes_Heartbeat_Handler,
+		},
+		{
+			MethodName: "ReportBlocks",
+			Handler:    _NameNodeInternalRoutes_ReportBlocks_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "nnpb_routes.proto",
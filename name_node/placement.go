@@ -0,0 +1,214 @@
+package namenode
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/SayedAlesawy/Videra-Storage/config"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+// PlacementRequest Describes the replicas to be placed for a file
+type PlacementRequest struct {
+	Holders []DataNodeData //Data nodes holding (or about to hold) a copy of the file, the source node first
+	Count   int            //Number of replicas to place
+	Size    int64          //Size of the file in bytes, 0 if unknown
+}
+
+// PlacementPolicy Chooses the data nodes to hold new replicas of a file out of the healthy candidates sorted by ID,
+// a policy only depends on its inputs, so the same request and candidates always get the same nodes
+type PlacementPolicy interface {
+	Place(request PlacementRequest, candidates []DataNodeData) []DataNodeData
+}
+
+// placementPolicies Maps the configurable policy names to the placement policies
+var placementPolicies = map[string]PlacementPolicy{
+	"ring":   RingPlacement{},
+	"spread": SpreadPlacement{},
+}
+
+// GetPlacementPolicy A function to get the placement policy with the given name, defaulting to spread
+func GetPlacementPolicy(name string) PlacementPolicy {
+	if policy, ok := placementPolicies[name]; ok {
+		return policy
+	}
+
+	return SpreadPlacement{}
+}
+
+// PlaceReplicas A function to choose the given number of distinct healthy data nodes to hold new replicas
+// of a file of the given size, using the configured placement policy, the excluded nodes already hold
+// or are about to hold a copy of the file, so their failure domains are taken into account
+func (nameNode *NameNode) PlaceReplicas(nodeID string, excluded map[string]bool, count int, size int64) ([]DataNodeData, error) {
+	request := PlacementRequest{
+		Holders: []DataNodeData{{ID: nodeID}},
+		Count:   count,
+		Size:    size,
+	}
+
+	candidates := placementCandidates(&request, nameNode.GetAllDataNodeData(), nameNode.GetNodeStates(), excluded)
+
+	policyName := config.ConfigurationManagerInstance("").NameNodeConfig().PlacementPolicy
+	chosenDataNodes := GetPlacementPolicy(policyName).Place(request, candidates)
+
+	// There's no enough available nodes
+	if len(chosenDataNodes) < count {
+		return chosenDataNodes, errors.New(fmt.Sprintf("Only %d of %d datanodes available for replication", len(chosenDataNodes), count))
+	}
+
+	return chosenDataNodes, nil
+}
+
+// placementCandidates A function to get the data nodes that can hold new replicas sorted by ID, the source node
+// and the excluded nodes are added to the holders of the request instead, whose first holder is the source node
+func placementCandidates(request *PlacementRequest, dataNodes []DataNodeData, states map[string]NodeState, excluded map[string]bool) []DataNodeData {
+	sort.Slice(dataNodes, func(i, j int) bool {
+		return dataNodes[i].ID < dataNodes[j].ID
+	})
+
+	var candidates []DataNodeData
+	for _, dataNode := range dataNodes {
+		switch {
+		case dataNode.ID == request.Holders[0].ID:
+			request.Holders[0] = dataNode
+		case excluded[dataNode.ID]:
+			request.Holders = append(request.Holders, dataNode)
+		case dataNode.Latency == 0 && !states[dataNode.ID].isLeaving():
+			// nodes that missed pings aren't trusted with new replicas, and leaving nodes take none
+			candidates = append(candidates, dataNode)
+		}
+	}
+
+	return candidates
+}
+
+// RingPlacement Picks the candidates in order of their IDs, starting from the node next to the source node
+type RingPlacement struct{}
+
+// Place A function to choose the nodes next to the source node in the ring of candidates
+func (policy RingPlacement) Place(request PlacementRequest, candidates []DataNodeData) []DataNodeData {
+	var chosenDataNodes []DataNodeData
+
+	candidates = withFreeCapacity(candidates, request.Size)
+	if len(candidates) == 0 || len(request.Holders) == 0 {
+		return chosenDataNodes
+	}
+
+	startIdx := sort.Search(len(candidates), func(idx int) bool {
+		return candidates[idx].ID > request.Holders[0].ID
+	})
+
+	for step := 0; step < len(candidates) && len(chosenDataNodes) < request.Count; step++ {
+		chosenDataNodes = append(chosenDataNodes, candidates[(startIdx+step)%len(candidates)])
+	}
+
+	return chosenDataNodes
+}
+
+// SpreadPlacement Spreads the copies of a file across failure domains, each replica goes to the candidate
+// whose zone then rack hold the fewest copies, ties are broken by the most free disk then by ID
+type SpreadPlacement struct{}
+
+// Place A function to choose the candidates spreading the copies of the file across zones and racks
+func (policy SpreadPlacement) Place(request PlacementRequest, candidates []DataNodeData) []DataNodeData {
+	var chosenDataNodes []DataNodeData
+
+	zoneCopies := make(map[string]int)
+	rackCopies := make(map[string]int)
+	for _, holder := range request.Holders {
+		zoneCopies[holder.Zone]++
+		rackCopies[rackOf(holder)]++
+	}
+
+	remaining := append([]DataNodeData{}, withFreeCapacity(candidates, request.Size)...)
+	for len(chosenDataNodes) < request.Count && len(remaining) > 0 {
+		bestIdx := 0
+		for idx, candidate := range remaining[1:] {
+			best := remaining[bestIdx]
+
+			if zoneCopies[candidate.Zone] != zoneCopies[best.Zone] {
+				if zoneCopies[candidate.Zone] < zoneCopies[best.Zone] {
+					bestIdx = idx + 1
+				}
+				continue
+			}
+
+			if rackCopies[rackOf(candidate)] != rackCopies[rackOf(best)] {
+				if rackCopies[rackOf(candidate)] < rackCopies[rackOf(best)] {
+					bestIdx = idx + 1
+				}
+				continue
+			}
+
+			if candidate.FreeDisk > best.FreeDisk || (candidate.FreeDisk == best.FreeDisk && candidate.ID < best.ID) {
+				bestIdx = idx + 1
+			}
+		}
+
+		chosen := remaining[bestIdx]
+		chosenDataNodes = append(chosenDataNodes, chosen)
+		zoneCopies[chosen.Zone]++
+		rackCopies[rackOf(chosen)]++
+		remaining = append(remaining[:bestIdx], remaining[bestIdx+1:]...)
+	}
+
+	return chosenDataNodes
+}
+
+// rackOf is a function to get the failure domain of a data node's rack, racks are only unique within a zone
+func rackOf(dataNode DataNodeData) string {
+	return fmt.Sprintf("%s/%s", dataNode.Zone, dataNode.Rack)
+}
+
+// withFreeCapacity is a function to filter out the candidates without enough free disk for a file of the given size,
+// nodes that didn't report their disk are kept
+func withFreeCapacity(candidates []DataNodeData, size int64) []DataNodeData {
+	var eligible []DataNodeData
+
+	for _, candidate := range candidates {
+		if candidate.TotalDisk == 0 || size <= 0 || candidate.FreeDisk >= uint64(size) {
+			eligible = append(eligible, candidate)
+		}
+	}
+
+	return eligible
+}
+
+// PlaceModelReplicas A function to choose the data nodes to hold the replicas of a model, which are all
+// the healthy GPU nodes other than the given one, or the configured set of model replica nodes if any,
+// so videos associated with the model can be uploaded to any of them
+func (nameNode *NameNode) PlaceModelReplicas(nodeID string) []DataNodeData {
+	var chosenDataNodes []DataNodeData
+
+	modelReplicaNodes := make(map[string]bool)
+	for _, id := range strings.Split(config.ConfigurationManagerInstance("").NameNodeConfig().ModelReplicaNodes, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			modelReplicaNodes[id] = true
+		}
+	}
+
+	dataNodes := nameNode.GetAllDataNodeData()
+	sort.Slice(dataNodes, func(i, j int) bool {
+		return dataNodes[i].ID < dataNodes[j].ID
+	})
+
+	states := nameNode.GetNodeStates()
+	for _, dataNode := range dataNodes {
+		if dataNode.ID == nodeID || dataNode.Latency > 0 || states[dataNode.ID].isLeaving() {
+			continue
+		}
+
+		if len(modelReplicaNodes) == 0 && !dataNode.GPU {
+			continue
+		}
+
+		if len(modelReplicaNodes) != 0 && !modelReplicaNodes[dataNode.ID] {
+			continue
+		}
+
+		chosenDataNodes = append(chosenDataNodes, dataNode)
+	}
+
+	return chosenDataNodes
+}
@@ -0,0 +1,248 @@
+package namenode
+
+import (
+	"reflect"
+	"testing"
+)
+
+// idsOf is a function to get the IDs of the given data nodes, in order
+func idsOf(dataNodes []DataNodeData) []string {
+	ids := []string{}
+	for _, dataNode := range dataNodes {
+		ids = append(ids, dataNode.ID)
+	}
+
+	return ids
+}
+
+func TestSpreadPlacement(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     PlacementRequest
+		candidates  []DataNodeData
+		expectedIDs []string
+	}{
+		{
+			name:    "replicas go to the zones holding no copy",
+			request: PlacementRequest{Holders: []DataNodeData{{ID: "1", Zone: "a", Rack: "r1"}}, Count: 2},
+			candidates: []DataNodeData{
+				{ID: "2", Zone: "a", Rack: "r2", FreeDisk: 900},
+				{ID: "3", Zone: "b", Rack: "r1"},
+				{ID: "4", Zone: "c", Rack: "r1"},
+			},
+			expectedIDs: []string{"3", "4"},
+		},
+		{
+			name:    "replicas go to the racks holding no copy within the same zone",
+			request: PlacementRequest{Holders: []DataNodeData{{ID: "1", Zone: "a", Rack: "r1"}}, Count: 1},
+			candidates: []DataNodeData{
+				{ID: "2", Zone: "a", Rack: "r1", FreeDisk: 900},
+				{ID: "3", Zone: "a", Rack: "r2"},
+			},
+			expectedIDs: []string{"3"},
+		},
+		{
+			name:    "racks are only unique within their zone",
+			request: PlacementRequest{Holders: []DataNodeData{{ID: "1", Zone: "a", Rack: "r1"}}, Count: 1},
+			candidates: []DataNodeData{
+				{ID: "2", Zone: "a", Rack: "r2"},
+				{ID: "3", Zone: "b", Rack: "r1"},
+			},
+			expectedIDs: []string{"3"},
+		},
+		{
+			name: "every holder counts against its zone and rack",
+			request: PlacementRequest{
+				Holders: []DataNodeData{{ID: "1", Zone: "a", Rack: "r1"}, {ID: "2", Zone: "b", Rack: "r1"}},
+				Count:   2,
+			},
+			candidates: []DataNodeData{
+				{ID: "3", Zone: "a", Rack: "r2"},
+				{ID: "4", Zone: "b", Rack: "r2"},
+				{ID: "5", Zone: "c", Rack: "r1"},
+			},
+			expectedIDs: []string{"5", "3"},
+		},
+		{
+			name:    "copies keep spreading once every zone holds one",
+			request: PlacementRequest{Holders: []DataNodeData{{ID: "1", Zone: "a", Rack: "r1"}}, Count: 3},
+			candidates: []DataNodeData{
+				{ID: "2", Zone: "a", Rack: "r2"},
+				{ID: "3", Zone: "b", Rack: "r1"},
+				{ID: "4", Zone: "b", Rack: "r1"},
+				{ID: "5", Zone: "b", Rack: "r2"},
+			},
+			expectedIDs: []string{"3", "2", "5"},
+		},
+		{
+			name:    "ties are broken by the most free disk then by ID",
+			request: PlacementRequest{Holders: []DataNodeData{{ID: "1", Zone: "a"}}, Count: 3},
+			candidates: []DataNodeData{
+				{ID: "2", Zone: "b", FreeDisk: 100},
+				{ID: "3", Zone: "c", FreeDisk: 300},
+				{ID: "4", Zone: "d", FreeDisk: 100},
+			},
+			expectedIDs: []string{"3", "2", "4"},
+		},
+		{
+			name:    "full nodes are filtered out, nodes that didn't report their disk are kept",
+			request: PlacementRequest{Holders: []DataNodeData{{ID: "1", Zone: "a"}}, Count: 2, Size: 500},
+			candidates: []DataNodeData{
+				{ID: "2", Zone: "b", TotalDisk: 1000, FreeDisk: 400},
+				{ID: "3", Zone: "a", TotalDisk: 1000, FreeDisk: 500},
+				{ID: "4", Zone: "c"},
+			},
+			expectedIDs: []string{"4", "3"},
+		},
+		{
+			name:    "asking for more replicas than candidates places one on each",
+			request: PlacementRequest{Holders: []DataNodeData{{ID: "1", Zone: "a"}}, Count: 5},
+			candidates: []DataNodeData{
+				{ID: "2", Zone: "a"},
+				{ID: "3", Zone: "b"},
+			},
+			expectedIDs: []string{"3", "2"},
+		},
+		{
+			name:        "no candidates place no replica",
+			request:     PlacementRequest{Holders: []DataNodeData{{ID: "1", Zone: "a"}}, Count: 2},
+			expectedIDs: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			candidates := append([]DataNodeData{}, test.candidates...)
+
+			chosen := idsOf(SpreadPlacement{}.Place(test.request, candidates))
+			if !reflect.DeepEqual(chosen, test.expectedIDs) {
+				t.Errorf("expected nodes %v, found %v", test.expectedIDs, chosen)
+			}
+
+			if !reflect.DeepEqual(idsOf(candidates), idsOf(test.candidates)) {
+				t.Errorf("expected candidates to be left untouched, found %v", idsOf(candidates))
+			}
+		})
+	}
+}
+
+func TestRingPlacement(t *testing.T) {
+	candidates := []DataNodeData{{ID: "1"}, {ID: "3"}, {ID: "5"}}
+
+	tests := []struct {
+		name        string
+		request     PlacementRequest
+		candidates  []DataNodeData
+		expectedIDs []string
+	}{
+		{
+			name:        "replicas go to the nodes next to the source node",
+			request:     PlacementRequest{Holders: []DataNodeData{{ID: "2"}}, Count: 2},
+			candidates:  candidates,
+			expectedIDs: []string{"3", "5"},
+		},
+		{
+			name:        "the ring wraps around after the last node",
+			request:     PlacementRequest{Holders: []DataNodeData{{ID: "4"}}, Count: 2},
+			candidates:  candidates,
+			expectedIDs: []string{"5", "1"},
+		},
+		{
+			name:        "a source node after all the candidates starts from the first one",
+			request:     PlacementRequest{Holders: []DataNodeData{{ID: "9"}}, Count: 2},
+			candidates:  candidates,
+			expectedIDs: []string{"1", "3"},
+		},
+		{
+			name:        "asking for more replicas than candidates places one on each",
+			request:     PlacementRequest{Holders: []DataNodeData{{ID: "3"}}, Count: 5},
+			candidates:  candidates,
+			expectedIDs: []string{"5", "1", "3"},
+		},
+		{
+			name:    "full nodes are filtered out, nodes that didn't report their disk are kept",
+			request: PlacementRequest{Holders: []DataNodeData{{ID: "2"}}, Count: 2, Size: 500},
+			candidates: []DataNodeData{
+				{ID: "1"},
+				{ID: "3", TotalDisk: 1000, FreeDisk: 400},
+				{ID: "5", TotalDisk: 1000, FreeDisk: 500},
+			},
+			expectedIDs: []string{"5", "1"},
+		},
+		{
+			name:        "a request with no holder places no replica",
+			request:     PlacementRequest{Count: 2},
+			candidates:  candidates,
+			expectedIDs: []string{},
+		},
+		{
+			name:        "no candidates place no replica",
+			request:     PlacementRequest{Holders: []DataNodeData{{ID: "2"}}, Count: 2},
+			expectedIDs: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chosen := idsOf(RingPlacement{}.Place(test.request, test.candidates))
+			if !reflect.DeepEqual(chosen, test.expectedIDs) {
+				t.Errorf("expected nodes %v, found %v", test.expectedIDs, chosen)
+			}
+		})
+	}
+}
+
+func TestPlacementCandidates(t *testing.T) {
+	dataNodes := []DataNodeData{
+		{ID: "7", Zone: "b"},
+		{ID: "1", Zone: "a"},
+		{ID: "3", Latency: 2},
+		{ID: "2", Zone: "b"},
+		{ID: "4"},
+		{ID: "5"},
+		{ID: "6"},
+	}
+	states := map[string]NodeState{
+		"4": {ID: "4", State: NodeStateDraining},
+		"5": {ID: "5", State: NodeStateDecommissioned},
+		"6": {ID: "6", State: NodeStateActive},
+	}
+
+	request := PlacementRequest{Holders: []DataNodeData{{ID: "1"}}, Count: 2}
+	candidates := placementCandidates(&request, dataNodes, states, map[string]bool{"2": true})
+
+	if ids := idsOf(candidates); !reflect.DeepEqual(ids, []string{"6", "7"}) {
+		t.Errorf("expected offline, draining and decommissioned nodes to be filtered out, found candidates %v", ids)
+	}
+
+	if ids := idsOf(request.Holders); !reflect.DeepEqual(ids, []string{"1", "2"}) {
+		t.Errorf("expected the source and excluded nodes to be holders, found %v", ids)
+	}
+
+	if request.Holders[0].Zone != "a" {
+		t.Errorf("expected the source node to be filled from its data, found %#v", request.Holders[0])
+	}
+
+	if ids := idsOf(SpreadPlacement{}.Place(request, candidates)); !reflect.DeepEqual(ids, []string{"6", "7"}) {
+		t.Errorf("expected the replicas to avoid the zones of the holders, found %v", ids)
+	}
+}
+
+func TestGetPlacementPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected PlacementPolicy
+	}{
+		{name: "ring", expected: RingPlacement{}},
+		{name: "spread", expected: SpreadPlacement{}},
+		{name: "unknown", expected: SpreadPlacement{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if policy := GetPlacementPolicy(test.name); policy != test.expected {
+				t.Errorf("expected policy %#v, found %#v", test.expected, policy)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Expr Represents a parsed search query, matching videos by the tags of their clips
+type Expr interface {
+	compile(c *compiler) string
+	collectTags(negated bool, tags *[]string)
+}
+
+// Tag Matches the videos having a clip with the tag
+type Tag struct {
+	Name string //Tag of the clip
+}
+
+// And Matches the videos matched by both expressions
+type And struct {
+	Left  Expr
+	Right Expr
+}
+
+// Or Matches the videos matched by any of the expressions
+type Or struct {
+	Left  Expr
+	Right Expr
+}
+
+// Not Matches the videos not matched by the expression
+type Not struct {
+	Expr Expr
+}
+
+// Near Matches the videos having a clip for each of the tags, all starting within the window from a clip of the first tag
+type Near struct {
+	Tags   []string //Tags of the clips, the first one anchors the window
+	Window uint64   //Maximum distance in seconds between the start of the anchor clip and the others
+}
+
+// ClipFilter Builds an extra condition on the clips referenced by a query, given the alias of the clips table,
+// like restricting them to a time range, an empty condition keeps all the clips
+type ClipFilter func(alias string) (string, []interface{})
+
+// compiler Accumulates the arguments of the SQL condition being built, in the order of their placeholders
+type compiler struct {
+	tokenColumn string        //Column holding the token of the video being matched
+	filter      ClipFilter    //Extra condition on the referenced clips
+	args        []interface{} //Arguments of the placeholders so far
+	aliases     int           //Number of aliases of the clips table used so far
+}
+
+// Compile A function to compile a search query into an SQL condition matching the videos whose token
+// is held by the given column, the condition only uses placeholders for values, given by the returned arguments
+func Compile(expr Expr, tokenColumn string, filter ClipFilter) (string, []interface{}) {
+	c := compiler{tokenColumn: tokenColumn, filter: filter}
+	condition := expr.compile(&c)
+
+	return condition, c.args
+}
+
+// MatchingTags A function to get the distinct tags a video is matched by, which are the tags not under a NOT
+func MatchingTags(expr Expr) []string {
+	var tags []string
+	expr.collectTags(false, &tags)
+
+	seen := make(map[string]bool)
+	distinct := []string{}
+	for _, tag := range tags {
+		if !seen[tag] {
+			seen[tag] = true
+			distinct = append(distinct, tag)
+		}
+	}
+
+	return distinct
+}
+
+// alias A function to get a new alias of the clips table
+func (c *compiler) alias() string {
+	alias := fmt.Sprintf("q%d", c.aliases)
+	c.aliases++
+
+	return alias
+}
+
+// clipCondition A function to build the condition matching a clip of the tag, on the given alias
+func (c *compiler) clipCondition(alias string, tag string) (string, []interface{}) {
+	condition := fmt.Sprintf("%s.tag = ?", alias)
+	args := []interface{}{tag}
+
+	if c.filter != nil {
+		if extra, extraArgs := c.filter(alias); extra != "" {
+			condition = fmt.Sprintf("%s AND %s", condition, extra)
+			args = append(args, extraArgs...)
+		}
+	}
+
+	return condition, args
+}
+
+// compile A function to match the videos having a clip of the tag
+func (tag Tag) compile(c *compiler) string {
+	alias := c.alias()
+	condition, args := c.clipCondition(alias, tag.Name)
+	c.args = append(c.args, args...)
+
+	return fmt.Sprintf("EXISTS (SELECT 1 FROM clips AS %s WHERE %s.token = %s AND %s)", alias, alias, c.tokenColumn, condition)
+}
+
+// compile A function to match the videos matched by both expressions
+func (and And) compile(c *compiler) string {
+	left := and.Left.compile(c)
+	right := and.Right.compile(c)
+
+	return fmt.Sprintf("(%s AND %s)", left, right)
+}
+
+// compile A function to match the videos matched by any of the expressions
+func (or Or) compile(c *compiler) string {
+	left := or.Left.compile(c)
+	right := or.Right.compile(c)
+
+	return fmt.Sprintf("(%s OR %s)", left, right)
+}
+
+// compile A function to match the videos not matched by the expression
+func (not Not) compile(c *compiler) string {
+	return fmt.Sprintf("NOT (%s)", not.Expr.compile(c))
+}
+
+// compile A function to match the videos having a clip of the first tag, joined to a clip of each other tag within the window
+func (near Near) compile(c *compiler) string {
+	anchor := c.alias()
+
+	// the joins come before the where clause, so their arguments come first
+	var joins []string
+	for _, tag := range near.Tags[1:] {
+		alias := c.alias()
+		condition, args := c.clipCondition(alias, tag)
+
+		joins = append(joins, fmt.Sprintf(
+			"INNER JOIN clips AS %s ON %s.token = %s.token AND %s AND ABS(CAST(%s.start_time AS SIGNED) - CAST(%s.start_time AS SIGNED)) <= ?",
+			alias, alias, anchor, condition, alias, anchor))
+		c.args = append(c.args, args...)
+		c.args = append(c.args, near.Window)
+	}
+
+	condition, args := c.clipCondition(anchor, near.Tags[0])
+	c.args = append(c.args, args...)
+
+	return fmt.Sprintf("EXISTS (SELECT 1 FROM clips AS %s %s WHERE %s.token = %s AND %s)",
+		anchor, strings.Join(joins, " "), anchor, c.tokenColumn, condition)
+}
+
+// collectTags A function to collect the tag unless negated
+func (tag Tag) collectTags(negated bool, tags *[]string) {
+	if !negated {
+		*tags = append(*tags, tag.Name)
+	}
+}
+
+// collectTags A function to collect the tags of both expressions
+func (and And) collectTags(negated bool, tags *[]string) {
+	and.Left.collectTags(negated, tags)
+	and.Right.collectTags(negated, tags)
+}
+
+// collectTags A function to collect the tags of both expressions
+func (or Or) collectTags(negated bool, tags *[]string) {
+	or.Left.collectTags(negated, tags)
+	or.Right.collectTags(negated, tags)
+}
+
+// collectTags A function to collect the tags of the expression, flipping whether they are negated
+func (not Not) collectTags(negated bool, tags *[]string) {
+	not.Expr.collectTags(!negated, tags)
+}
+
+// collectTags A function to collect the tags unless negated
+func (near Near) collectTags(negated bool, tags *[]string) {
+	if !negated {
+		*tags = append(*tags, near.Tags...)
+	}
+}
@@ -0,0 +1,147 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// timeRange A filter restricting the clips to start within [10, 20), like the time range of a search
+func timeRange(alias string) (string, []interface{}) {
+	return fmt.Sprintf("%s.start_time >= ? AND %s.start_time < ?", alias, alias), []interface{}{10, 20}
+}
+
+// mustParse is a function to parse a query, failing the test if it's invalid
+func mustParse(t *testing.T, input string) Expr {
+	expr, err := Parse(input)
+	if err != nil {
+		t.Fatalf("unable to parse query %q: %v", input, err)
+	}
+
+	return expr
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name              string
+		input             string
+		filter            ClipFilter
+		expectedCondition string
+		expectedArgs      []interface{}
+	}{
+		{
+			name:              "a tag matches the videos having a clip of it",
+			input:             "person",
+			expectedCondition: "EXISTS (SELECT 1 FROM clips AS q0 WHERE q0.token = clips.token AND q0.tag = ?)",
+			expectedArgs:      []interface{}{"person"},
+		},
+		{
+			name:  "operators get a subquery per tag, with the arguments in the order of the tags",
+			input: "person car OR NOT bus",
+			expectedCondition: "((EXISTS (SELECT 1 FROM clips AS q0 WHERE q0.token = clips.token AND q0.tag = ?) AND " +
+				"EXISTS (SELECT 1 FROM clips AS q1 WHERE q1.token = clips.token AND q1.tag = ?)) OR " +
+				"NOT (EXISTS (SELECT 1 FROM clips AS q2 WHERE q2.token = clips.token AND q2.tag = ?)))",
+			expectedArgs: []interface{}{"person", "car", "bus"},
+		},
+		{
+			name:              "a filter with no condition keeps all the clips",
+			input:             "person",
+			filter:            func(alias string) (string, []interface{}) { return "", nil },
+			expectedCondition: "EXISTS (SELECT 1 FROM clips AS q0 WHERE q0.token = clips.token AND q0.tag = ?)",
+			expectedArgs:      []interface{}{"person"},
+		},
+		{
+			name:   "the filter arguments follow the tag of each subquery",
+			input:  "person NOT car",
+			filter: timeRange,
+			expectedCondition: "(EXISTS (SELECT 1 FROM clips AS q0 WHERE q0.token = clips.token AND q0.tag = ? AND q0.start_time >= ? AND q0.start_time < ?) AND " +
+				"NOT (EXISTS (SELECT 1 FROM clips AS q1 WHERE q1.token = clips.token AND q1.tag = ? AND q1.start_time >= ? AND q1.start_time < ?)))",
+			expectedArgs: []interface{}{"person", 10, 20, "car", 10, 20},
+		},
+		{
+			name:  "NEAR joins a clip of each other tag to a clip of the first one",
+			input: "NEAR(person, car, bus, 30)",
+			expectedCondition: "EXISTS (SELECT 1 FROM clips AS q0 " +
+				"INNER JOIN clips AS q1 ON q1.token = q0.token AND q1.tag = ? AND ABS(CAST(q1.start_time AS SIGNED) - CAST(q0.start_time AS SIGNED)) <= ? " +
+				"INNER JOIN clips AS q2 ON q2.token = q0.token AND q2.tag = ? AND ABS(CAST(q2.start_time AS SIGNED) - CAST(q0.start_time AS SIGNED)) <= ? " +
+				"WHERE q0.token = clips.token AND q0.tag = ?)",
+			expectedArgs: []interface{}{"car", uint64(30), "bus", uint64(30), "person"},
+		},
+		{
+			name:   "NEAR with a filter gets the join arguments, then the anchor ones",
+			input:  "NEAR(person, car, 30)",
+			filter: timeRange,
+			expectedCondition: "EXISTS (SELECT 1 FROM clips AS q0 " +
+				"INNER JOIN clips AS q1 ON q1.token = q0.token AND q1.tag = ? AND q1.start_time >= ? AND q1.start_time < ? AND ABS(CAST(q1.start_time AS SIGNED) - CAST(q0.start_time AS SIGNED)) <= ? " +
+				"WHERE q0.token = clips.token AND q0.tag = ? AND q0.start_time >= ? AND q0.start_time < ?)",
+			expectedArgs: []interface{}{"car", 10, 20, uint64(30), "person", 10, 20},
+		},
+		{
+			name:   "NEAR with a filter among other terms keeps the arguments of each term together",
+			input:  "bike NEAR(person, car, 5) OR bus",
+			filter: timeRange,
+			expectedCondition: "((EXISTS (SELECT 1 FROM clips AS q0 WHERE q0.token = clips.token AND q0.tag = ? AND q0.start_time >= ? AND q0.start_time < ?) AND " +
+				"EXISTS (SELECT 1 FROM clips AS q1 " +
+				"INNER JOIN clips AS q2 ON q2.token = q1.token AND q2.tag = ? AND q2.start_time >= ? AND q2.start_time < ? AND ABS(CAST(q2.start_time AS SIGNED) - CAST(q1.start_time AS SIGNED)) <= ? " +
+				"WHERE q1.token = clips.token AND q1.tag = ? AND q1.start_time >= ? AND q1.start_time < ?)) OR " +
+				"EXISTS (SELECT 1 FROM clips AS q3 WHERE q3.token = clips.token AND q3.tag = ? AND q3.start_time >= ? AND q3.start_time < ?))",
+			expectedArgs: []interface{}{"bike", 10, 20, "car", 10, 20, uint64(5), "person", 10, 20, "bus", 10, 20},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			condition, args := Compile(mustParse(t, test.input), "clips.token", test.filter)
+
+			if condition != test.expectedCondition {
+				t.Errorf("expected condition\n%s\nfound\n%s", test.expectedCondition, condition)
+			}
+
+			if !reflect.DeepEqual(args, test.expectedArgs) {
+				t.Errorf("expected arguments %v, found %v", test.expectedArgs, args)
+			}
+
+			if placeholders := strings.Count(condition, "?"); placeholders != len(args) {
+				t.Errorf("expected an argument per placeholder, found %d placeholders and %d arguments", placeholders, len(args))
+			}
+		})
+	}
+}
+
+func TestMatchingTags(t *testing.T) {
+	tests := []struct {
+		name         string
+		input        string
+		expectedTags []string
+	}{
+		{
+			name:         "the tags are listed once, in order",
+			input:        "person car OR person",
+			expectedTags: []string{"person", "car"},
+		},
+		{
+			name:         "negated tags are left out",
+			input:        "person NOT (car OR NEAR(bus, bike, 5))",
+			expectedTags: []string{"person"},
+		},
+		{
+			name:         "tags negated twice are kept",
+			input:        "NOT NOT person NEAR(car, bus, 5)",
+			expectedTags: []string{"person", "car", "bus"},
+		},
+		{
+			name:         "a query of negated tags matches by no tag",
+			input:        "NOT person",
+			expectedTags: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if tags := MatchingTags(mustParse(t, test.input)); !reflect.DeepEqual(tags, test.expectedTags) {
+				t.Errorf("expected tags %v, found %v", test.expectedTags, tags)
+			}
+		})
+	}
+}
@@ -0,0 +1,348 @@
+package query
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+// MaxQueryLength Maximum length in bytes of a search query
+var MaxQueryLength = 500
+
+// MaxQueryTerms Maximum number of tags referenced by a search query, each of them costs a subquery
+var MaxQueryTerms = 16
+
+// MaxNearWindow Maximum window in seconds of a NEAR term
+var MaxNearWindow uint64 = 3600
+
+// Error Represents an invalid search query, along with the position at which it was found invalid
+type Error struct {
+	Message  string `json:"error"`    //Description of the problem
+	Position int    `json:"position"` //Offset in bytes into the query at which the problem was found
+}
+
+// Error A function to describe the invalid query
+func (err *Error) Error() string {
+	return fmt.Sprintf("%s at position %d", err.Message, err.Position)
+}
+
+// tokenKind Represents the kinds of the tokens of a search query
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenTag
+	tokenNumber
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenNear
+	tokenOpen
+	tokenClose
+	tokenComma
+)
+
+// token Represents a token of a search query
+type token struct {
+	kind     tokenKind //Kind of the token
+	text     string    //Text of the token, unquoted for quoted tags
+	position int       //Offset in bytes into the query at which the token starts
+}
+
+// keywords Maps the keywords of the query language to their tokens, keywords are case insensitive
+var keywords = map[string]tokenKind{
+	"AND":  tokenAnd,
+	"OR":   tokenOr,
+	"NOT":  tokenNot,
+	"NEAR": tokenNear,
+}
+
+// parser Parses a search query into an expression by recursive descent
+type parser struct {
+	tokens []token //Tokens of the query
+	next   int     //Index of the next token to consume
+	terms  int     //Number of tags referenced so far
+}
+
+// Parse A function to parse a search query into an expression, the language is
+//
+//	expr    = and { "OR" and }
+//	and     = unary { [ "AND" ] unary }
+//	unary   = "NOT" unary | primary
+//	primary = tag | "(" expr ")" | "NEAR" "(" tag "," tag { "," tag } "," seconds ")"
+//
+// adjacent terms are joined by AND, so "person car NOT bicycle" matches videos with a person and a car but no bicycle,
+// tags with spaces or keywords are quoted ("traffic light"), and NEAR matches videos where all the tags
+// start within the given number of seconds from the first one
+func Parse(input string) (Expr, error) {
+	if len(input) > MaxQueryLength {
+		return nil, &Error{Message: fmt.Sprintf("Query is longer than %d characters", MaxQueryLength), Position: MaxQueryLength}
+	}
+
+	tokens, err := tokenize(input)
+	if errors.IsError(err) {
+		return nil, err
+	}
+
+	p := parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, &Error{Message: "Query is empty", Position: 0}
+	}
+
+	expr, err := p.parseOr()
+	if errors.IsError(err) {
+		return nil, err
+	}
+
+	if next := p.peek(); next.kind != tokenEOF {
+		return nil, &Error{Message: fmt.Sprintf("Unexpected %s", describe(next)), Position: next.position}
+	}
+
+	return expr, nil
+}
+
+// tokenize A function to split a search query into tokens
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+
+	for i := 0; i < len(input); {
+		c := rune(input[i])
+
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenOpen, text: "(", position: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenClose, text: ")", position: i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", position: i})
+			i++
+		case c == '"':
+			end := strings.IndexByte(input[i+1:], '"')
+			if end == -1 {
+				return nil, &Error{Message: "Unterminated quoted tag", Position: i}
+			}
+			if end == 0 {
+				return nil, &Error{Message: "Empty quoted tag", Position: i}
+			}
+
+			tokens = append(tokens, token{kind: tokenTag, text: input[i+1 : i+1+end], position: i})
+			i += end + 2
+		default:
+			start := i
+			for i < len(input) {
+				r, size := utf8.DecodeRuneInString(input[i:])
+				if !isWordChar(r) {
+					break
+				}
+				i += size
+			}
+			if start == i {
+				r, _ := utf8.DecodeRuneInString(input[i:])
+				return nil, &Error{Message: fmt.Sprintf("Unexpected character %q", r), Position: i}
+			}
+
+			word := input[start:i]
+			kind, isKeyword := keywords[strings.ToUpper(word)]
+			if !isKeyword {
+				kind = tokenTag
+				if _, err := strconv.ParseUint(word, 10, 64); !errors.IsError(err) {
+					kind = tokenNumber
+				}
+			}
+
+			tokens = append(tokens, token{kind: kind, text: word, position: start})
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, position: len(input)}), nil
+}
+
+// isWordChar A function to check if a character can be part of an unquoted tag
+func isWordChar(c rune) bool {
+	return unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '-' || c == '.' || c == ':'
+}
+
+// peek A function to get the next token without consuming it
+func (p *parser) peek() token {
+	return p.tokens[p.next]
+}
+
+// consume A function to consume the next token
+func (p *parser) consume() token {
+	next := p.tokens[p.next]
+	if next.kind != tokenEOF {
+		p.next++
+	}
+
+	return next
+}
+
+// expect A function to consume the next token, failing if it's not of the given kind
+func (p *parser) expect(kind tokenKind, expected string) (token, error) {
+	next := p.consume()
+	if next.kind != kind {
+		return next, &Error{Message: fmt.Sprintf("Expected %s, found %s", expected, describe(next)), Position: next.position}
+	}
+
+	return next, nil
+}
+
+// parseOr A function to parse terms joined by OR
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if errors.IsError(err) {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOr {
+		p.consume()
+
+		right, err := p.parseAnd()
+		if errors.IsError(err) {
+			return nil, err
+		}
+
+		left = Or{Left: left, Right: right}
+	}
+
+	return left, nil
+}
+
+// parseAnd A function to parse terms joined by AND, or adjacent to each other
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if errors.IsError(err) {
+		return nil, err
+	}
+
+	for {
+		switch p.peek().kind {
+		case tokenAnd:
+			p.consume()
+		case tokenTag, tokenNumber, tokenNot, tokenNear, tokenOpen:
+		default:
+			return left, nil
+		}
+
+		right, err := p.parseUnary()
+		if errors.IsError(err) {
+			return nil, err
+		}
+
+		left = And{Left: left, Right: right}
+	}
+}
+
+// parseUnary A function to parse a negated or a primary term
+func (p *parser) parseUnary() (Expr, error) {
+	if p.peek().kind != tokenNot {
+		return p.parsePrimary()
+	}
+
+	p.consume()
+
+	expr, err := p.parseUnary()
+	if errors.IsError(err) {
+		return nil, err
+	}
+
+	return Not{Expr: expr}, nil
+}
+
+// parsePrimary A function to parse a tag, a parenthesized expression or a NEAR term
+func (p *parser) parsePrimary() (Expr, error) {
+	next := p.peek()
+
+	switch next.kind {
+	case tokenTag, tokenNumber:
+		return p.parseTag()
+	case tokenOpen:
+		p.consume()
+
+		expr, err := p.parseOr()
+		if errors.IsError(err) {
+			return nil, err
+		}
+
+		if _, err := p.expect(tokenClose, "closing parenthesis"); errors.IsError(err) {
+			return nil, err
+		}
+
+		return expr, nil
+	case tokenNear:
+		return p.parseNear()
+	default:
+		return nil, &Error{Message: fmt.Sprintf("Expected tag, found %s", describe(next)), Position: next.position}
+	}
+}
+
+// parseTag A function to parse a tag, counting it against the maximum number of terms
+func (p *parser) parseTag() (Expr, error) {
+	next := p.consume()
+	if next.kind != tokenTag && next.kind != tokenNumber {
+		return nil, &Error{Message: fmt.Sprintf("Expected tag, found %s", describe(next)), Position: next.position}
+	}
+
+	p.terms++
+	if p.terms > MaxQueryTerms {
+		return nil, &Error{Message: fmt.Sprintf("Query references more than %d tags", MaxQueryTerms), Position: next.position}
+	}
+
+	return Tag{Name: next.text}, nil
+}
+
+// parseNear A function to parse a NEAR term, its last argument is the window in seconds
+func (p *parser) parseNear() (Expr, error) {
+	near := p.consume()
+
+	if _, err := p.expect(tokenOpen, "opening parenthesis after NEAR"); errors.IsError(err) {
+		return nil, err
+	}
+
+	var tags []string
+	for {
+		next := p.peek()
+		if next.kind == tokenNumber && len(tags) >= 2 && p.tokens[p.next+1].kind == tokenClose {
+			window, err := strconv.ParseUint(next.text, 10, 64)
+			if errors.IsError(err) || window > MaxNearWindow {
+				return nil, &Error{Message: fmt.Sprintf("NEAR window must be at most %d seconds", MaxNearWindow), Position: next.position}
+			}
+
+			p.consume()
+			p.consume()
+
+			return Near{Tags: tags, Window: window}, nil
+		}
+
+		tag, err := p.parseTag()
+		if errors.IsError(err) {
+			return nil, err
+		}
+		tags = append(tags, tag.(Tag).Name)
+
+		if next := p.peek(); next.kind == tokenClose {
+			return nil, &Error{Message: "NEAR takes at least two tags followed by a window in seconds", Position: near.position}
+		}
+
+		if _, err := p.expect(tokenComma, "comma in NEAR"); errors.IsError(err) {
+			return nil, err
+		}
+	}
+}
+
+// describe A function to describe a token in error messages
+func describe(t token) string {
+	if t.kind == tokenEOF {
+		return "end of query"
+	}
+
+	return fmt.Sprintf("%q", t.text)
+}
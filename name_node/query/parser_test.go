@@ -0,0 +1,294 @@
+package query
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Expr
+	}{
+		{
+			name:     "a single tag",
+			input:    "person",
+			expected: Tag{Name: "person"},
+		},
+		{
+			name:     "adjacent terms are joined by AND from the left",
+			input:    "person car bus",
+			expected: And{Left: And{Left: Tag{Name: "person"}, Right: Tag{Name: "car"}}, Right: Tag{Name: "bus"}},
+		},
+		{
+			name:     "AND binds tighter than OR on its right",
+			input:    "person OR car AND bus",
+			expected: Or{Left: Tag{Name: "person"}, Right: And{Left: Tag{Name: "car"}, Right: Tag{Name: "bus"}}},
+		},
+		{
+			name:     "AND binds tighter than OR on its left",
+			input:    "person car OR bus",
+			expected: Or{Left: And{Left: Tag{Name: "person"}, Right: Tag{Name: "car"}}, Right: Tag{Name: "bus"}},
+		},
+		{
+			name:     "OR is joined from the left",
+			input:    "person OR car OR bus",
+			expected: Or{Left: Or{Left: Tag{Name: "person"}, Right: Tag{Name: "car"}}, Right: Tag{Name: "bus"}},
+		},
+		{
+			name:     "parentheses override the precedence",
+			input:    "(person OR car) bus",
+			expected: And{Left: Or{Left: Tag{Name: "person"}, Right: Tag{Name: "car"}}, Right: Tag{Name: "bus"}},
+		},
+		{
+			name:     "NOT binds tighter than AND",
+			input:    "NOT person car",
+			expected: And{Left: Not{Expr: Tag{Name: "person"}}, Right: Tag{Name: "car"}},
+		},
+		{
+			name:     "NOT applies to parenthesized expressions",
+			input:    "NOT (person OR car)",
+			expected: Not{Expr: Or{Left: Tag{Name: "person"}, Right: Tag{Name: "car"}}},
+		},
+		{
+			name:     "NOT can be repeated",
+			input:    "NOT NOT person",
+			expected: Not{Expr: Not{Expr: Tag{Name: "person"}}},
+		},
+		{
+			name:     "keywords are case insensitive",
+			input:    "person and car or not bus",
+			expected: Or{Left: And{Left: Tag{Name: "person"}, Right: Tag{Name: "car"}}, Right: Not{Expr: Tag{Name: "bus"}}},
+		},
+		{
+			name:     "quoted tags keep their spaces and aren't keywords",
+			input:    `"traffic light" "OR"`,
+			expected: And{Left: Tag{Name: "traffic light"}, Right: Tag{Name: "OR"}},
+		},
+		{
+			name:     "numbers and word characters are tags",
+			input:    "42 sign_2.0-a:b",
+			expected: And{Left: Tag{Name: "42"}, Right: Tag{Name: "sign_2.0-a:b"}},
+		},
+		{
+			name:     "NEAR takes two tags and a window",
+			input:    "NEAR(person, car, 30)",
+			expected: Near{Tags: []string{"person", "car"}, Window: 30},
+		},
+		{
+			name:     "NEAR takes more than two tags and a window up to the maximum",
+			input:    "near(person, car, bus, 3600)",
+			expected: Near{Tags: []string{"person", "car", "bus"}, Window: 3600},
+		},
+		{
+			name:     "NEAR takes numbers as tags before the window",
+			input:    "NEAR(person, 10, 20)",
+			expected: Near{Tags: []string{"person", "10"}, Window: 20},
+		},
+		{
+			name:     "NEAR takes quoted tags",
+			input:    `NEAR("traffic light", car, 5)`,
+			expected: Near{Tags: []string{"traffic light", "car"}, Window: 5},
+		},
+		{
+			name:  "NEAR is a term among others",
+			input: "person NEAR(car, bus, 5) OR NOT bike",
+			expected: Or{
+				Left:  And{Left: Tag{Name: "person"}, Right: Near{Tags: []string{"car", "bus"}, Window: 5}},
+				Right: Not{Expr: Tag{Name: "bike"}},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := Parse(test.input)
+			if err != nil {
+				t.Fatalf("expected query to be parsed, found error %v", err)
+			}
+
+			if !reflect.DeepEqual(expr, test.expected) {
+				t.Errorf("expected expression %#v, found %#v", test.expected, expr)
+			}
+		})
+	}
+}
+
+func TestParseAtLimits(t *testing.T) {
+	inputs := map[string]string{
+		"a query at the maximum length":         strings.Repeat("a", MaxQueryLength),
+		"a query at the maximum number of tags": strings.Repeat("t ", MaxQueryTerms),
+		"the tags of NEAR up to the maximum":    strings.Repeat("t ", MaxQueryTerms-2) + "NEAR(a, b, 5)",
+	}
+
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse(input); err != nil {
+				t.Errorf("expected query to be parsed, found error %v", err)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name             string
+		input            string
+		expectedMessage  string
+		expectedPosition int
+	}{
+		{
+			name:             "an empty query",
+			input:            "",
+			expectedMessage:  "Query is empty",
+			expectedPosition: 0,
+		},
+		{
+			name:             "a query of spaces",
+			input:            "   ",
+			expectedMessage:  "Query is empty",
+			expectedPosition: 0,
+		},
+		{
+			name:             "a query longer than the maximum",
+			input:            strings.Repeat("a", MaxQueryLength+1),
+			expectedMessage:  "Query is longer than 500 characters",
+			expectedPosition: MaxQueryLength,
+		},
+		{
+			name:             "a query with more tags than the maximum",
+			input:            strings.Repeat("t ", MaxQueryTerms+1),
+			expectedMessage:  "Query references more than 16 tags",
+			expectedPosition: 2 * MaxQueryTerms,
+		},
+		{
+			name:             "the tags of NEAR count against the maximum",
+			input:            strings.Repeat("t ", MaxQueryTerms-1) + "NEAR(a, b, 5)",
+			expectedMessage:  "Query references more than 16 tags",
+			expectedPosition: 2*(MaxQueryTerms-1) + 8,
+		},
+		{
+			name:             "an operator missing its right term",
+			input:            "person OR",
+			expectedMessage:  "Expected tag, found end of query",
+			expectedPosition: 9,
+		},
+		{
+			name:             "NOT missing its term",
+			input:            "person NOT",
+			expectedMessage:  "Expected tag, found end of query",
+			expectedPosition: 10,
+		},
+		{
+			name:             "an operator in place of a term",
+			input:            "person AND OR car",
+			expectedMessage:  `Expected tag, found "OR"`,
+			expectedPosition: 11,
+		},
+		{
+			name:             "an unclosed parenthesis",
+			input:            "(person car",
+			expectedMessage:  "Expected closing parenthesis, found end of query",
+			expectedPosition: 11,
+		},
+		{
+			name:             "an unopened parenthesis",
+			input:            "person ) car",
+			expectedMessage:  `Unexpected ")"`,
+			expectedPosition: 7,
+		},
+		{
+			name:             "a comma out of NEAR",
+			input:            "person, car",
+			expectedMessage:  `Unexpected ","`,
+			expectedPosition: 6,
+		},
+		{
+			name:             "an unterminated quoted tag",
+			input:            `person "traffic light`,
+			expectedMessage:  "Unterminated quoted tag",
+			expectedPosition: 7,
+		},
+		{
+			name:             "an empty quoted tag",
+			input:            `person ""`,
+			expectedMessage:  "Empty quoted tag",
+			expectedPosition: 7,
+		},
+		{
+			name:             "a character that can't be in a tag",
+			input:            "person & car",
+			expectedMessage:  "Unexpected character '&'",
+			expectedPosition: 7,
+		},
+		{
+			name:             "NEAR with a single tag",
+			input:            "person NEAR(car, 5)",
+			expectedMessage:  "NEAR takes at least two tags followed by a window in seconds",
+			expectedPosition: 7,
+		},
+		{
+			name:             "NEAR with no window",
+			input:            "NEAR(person, car)",
+			expectedMessage:  "NEAR takes at least two tags followed by a window in seconds",
+			expectedPosition: 0,
+		},
+		{
+			name:             "NEAR with a window over the maximum",
+			input:            "NEAR(person, car, 3601)",
+			expectedMessage:  "NEAR window must be at most 3600 seconds",
+			expectedPosition: 18,
+		},
+		{
+			name:             "NEAR with a window out of range is left with no window",
+			input:            "NEAR(person, car, 99999999999999999999)",
+			expectedMessage:  "NEAR takes at least two tags followed by a window in seconds",
+			expectedPosition: 0,
+		},
+		{
+			name:             "NEAR with no parenthesis",
+			input:            "NEAR person, car, 5",
+			expectedMessage:  `Expected opening parenthesis after NEAR, found "person"`,
+			expectedPosition: 5,
+		},
+		{
+			name:             "NEAR with a missing comma",
+			input:            "NEAR(person car, 5)",
+			expectedMessage:  `Expected comma in NEAR, found "car"`,
+			expectedPosition: 12,
+		},
+		{
+			name:             "NEAR with an expression as argument",
+			input:            "NEAR(person, NOT car, 5)",
+			expectedMessage:  `Expected tag, found "NOT"`,
+			expectedPosition: 13,
+		},
+		{
+			name:             "NEAR left open",
+			input:            "NEAR(person, car, 5",
+			expectedMessage:  "Expected comma in NEAR, found end of query",
+			expectedPosition: 19,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			expr, err := Parse(test.input)
+			if err == nil {
+				t.Fatalf("expected an error, found expression %#v", expr)
+			}
+
+			queryErr, ok := err.(*Error)
+			if !ok {
+				t.Fatalf("expected a query error, found %#v", err)
+			}
+
+			if queryErr.Message != test.expectedMessage || queryErr.Position != test.expectedPosition {
+				t.Errorf("expected error %q at position %d, found %q at position %d",
+					test.expectedMessage, test.expectedPosition, queryErr.Message, queryErr.Position)
+			}
+		})
+	}
+}
@@ -0,0 +1,357 @@
+package namenode
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/SayedAlesawy/Videra-Storage/config"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+const (
+	//ReplicationJobPending represents a job waiting to be run
+	ReplicationJobPending string = "pending"
+	//ReplicationJobRunning represents a job copying the file
+	ReplicationJobRunning string = "running"
+	//ReplicationJobDone represents a job that restored a replica
+	ReplicationJobDone string = "done"
+	//ReplicationJobFailed represents a job that ran out of attempts or has no copy to restore from
+	ReplicationJobFailed string = "failed"
+)
+
+// ReplicationJob Represents a job copying a file from a surviving holder to a new data node
+type ReplicationJob struct {
+	ID           string    `json:"id"`                  //Unique ID of the job
+	Token        string    `json:"token"`               //Token of the original file
+	SourceNodeID string    `json:"source_node_id"`      //ID of the data node holding the copy to restore from
+	TargetNodeID string    `json:"target_node_id"`      //ID of the data node receiving the new replica
+	Status       string    `json:"status"`              //Status of the job (pending, running, done, failed)
+	Attempts     int       `json:"attempts"`            //Number of times the job was run
+	Error        string    `json:"error,omitempty"`     //Error of the last failed attempt
+	NewToken     string    `json:"new_token,omitempty"` //Token of the restored replica on the target data node
+	CreatedAt    time.Time `json:"created_at"`          //Time at which the job was scheduled
+	UpdatedAt    time.Time `json:"updated_at"`          //Time at which the job was last updated
+}
+
+// ScheduleReReplication A function to schedule copy jobs for the files that became under-replicated
+// by losing the copies held by an offline data node
+func (nameNode *NameNode) ScheduleReReplication(offlineNode DataNodeData) {
+	tokens, err := nameNode.GetFilesHeldBy(offlineNode.ID)
+	if errors.IsError(err) {
+		log.Println(logPrefix, fmt.Sprintf("Unable to fetch files held by data node %s", offlineNode.ID), err)
+		return
+	}
+
+	log.Println(logPrefix, fmt.Sprintf("Data node %s held %d files, checking their replicas", offlineNode.ID, len(tokens)))
+
+	for _, token := range tokens {
+		nameNode.scheduleFileReReplication(token)
+	}
+}
+
+// fileHolders Represents the data nodes holding the copies of a file
+type fileHolders struct {
+	staying       []DataNodeData  //Online data nodes staying in the cluster and holding a completed copy
+	leaving       []DataNodeData  //Online draining or decommissioned data nodes holding a completed copy
+	holderIDs     map[string]bool //IDs of all the data nodes holding a copy, completed or not
+	desiredCopies int             //Intended number of copies of the file
+	size          int64           //Size of the file in bytes
+}
+
+// getFileHolders A function to get the data nodes holding the copies of a file
+func (nameNode *NameNode) getFileHolders(token string, liveNodes map[string]DataNodeData, states map[string]NodeState) (fileHolders, error) {
+	copies, err := nameNode.GetFileCopies(token)
+	if errors.IsError(err) {
+		return fileHolders{}, err
+	}
+
+	expectation, _ := nameNode.getFileExpectation(token, copies)
+
+	return holdersOf(copies, liveNodes, states, expectation), nil
+}
+
+// holdersOf A function to get the data nodes holding the given copies of a file, the intended number of copies
+// is the expected one, falling back to the replication factor for files with no recorded expectation
+func holdersOf(copies []FileInfo, liveNodes map[string]DataNodeData, states map[string]NodeState, expectation fileExpectation) fileHolders {
+	holders := fileHolders{
+		holderIDs:     make(map[string]bool),
+		desiredCopies: config.ConfigurationManagerInstance("").NameNodeConfig().ReplicationFactor,
+	}
+
+	if expectation.Copies > 0 {
+		holders.desiredCopies = expectation.Copies
+	}
+
+	for _, fileCopy := range copies {
+		holders.size = fileCopy.Size
+	}
+
+	for _, fileCopy := range copies {
+		dataNode, live := liveNodes[fileCopy.DataNodeID]
+		if live && fileCopy.CompletedAt != nil && !holders.holderIDs[fileCopy.DataNodeID] {
+			if states[dataNode.ID].isLeaving() {
+				holders.leaving = append(holders.leaving, dataNode)
+			} else {
+				holders.staying = append(holders.staying, dataNode)
+			}
+		}
+
+		holders.holderIDs[fileCopy.DataNodeID] = true
+	}
+
+	return holders
+}
+
+// scheduleFileReReplication A function to schedule copy jobs for a file, one job per replica
+// missing from the online data nodes staying in the cluster, deleted files aren't restored
+func (nameNode *NameNode) scheduleFileReReplication(token string) {
+	if nameNode.isPendingDeletion(token) {
+		return
+	}
+
+	liveNodes := make(map[string]DataNodeData)
+	for _, dataNode := range nameNode.GetAllDataNodeData() {
+		liveNodes[dataNode.ID] = dataNode
+	}
+
+	holders, err := nameNode.getFileHolders(token, liveNodes, nameNode.GetNodeStates())
+	if errors.IsError(err) {
+		log.Println(logPrefix, fmt.Sprintf("Unable to fetch copies of file %s", token), err)
+		return
+	}
+
+	excluded := make(map[string]bool)
+	for id := range holders.holderIDs {
+		excluded[id] = true
+	}
+
+	activeJobs := 0
+	for _, job := range nameNode.GetReplicationJobs() {
+		if job.Token == token && (job.Status == ReplicationJobPending || job.Status == ReplicationJobRunning) {
+			excluded[job.TargetNodeID] = true
+			activeJobs++
+		}
+	}
+
+	missing := holders.desiredCopies - len(holders.staying) - activeJobs
+	if missing <= 0 {
+		return
+	}
+
+	if len(holders.staying) == 0 && len(holders.leaving) == 0 {
+		log.Println(logPrefix, fmt.Sprintf("File %s has no surviving copy to restore from", token))
+
+		job := newReplicationJob(token, "", "")
+		job.Status = ReplicationJobFailed
+		job.Error = "No surviving copy to restore from"
+		nameNode.saveReplicationJob(job)
+		return
+	}
+
+	// copies on leaving data nodes are only used when no staying node holds the file
+	source := append(holders.staying, holders.leaving...)[0]
+	targets, err := nameNode.PlaceReplicas(source.ID, excluded, missing, holders.size)
+	if errors.IsError(err) {
+		log.Println(logPrefix, fmt.Sprintf("File %s stays under-replicated", token), err)
+	}
+
+	for _, target := range targets {
+		log.Println(logPrefix, fmt.Sprintf("Scheduling copy of file %s from data node %s to %s", token, source.ID, target.ID))
+
+		nameNode.saveReplicationJob(newReplicationJob(token, source.ID, target.ID))
+	}
+}
+
+// newReplicationJob A function to create a pending job copying a file between data nodes, its ID is unique,
+// so the jobs of a file never overwrite each other, even when scheduled for the same data nodes
+func newReplicationJob(token string, sourceNodeID string, targetNodeID string) ReplicationJob {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+
+	now := time.Now()
+	return ReplicationJob{
+		ID:           fmt.Sprintf("%s-%d-%x", token, now.UnixNano(), suffix),
+		Token:        token,
+		SourceNodeID: sourceNodeID,
+		TargetNodeID: targetNodeID,
+		Status:       ReplicationJobPending,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// RunReplicationJobs A function to periodically run the pending re-replication jobs, only done by the leader
+func (nameNode *NameNode) RunReplicationJobs() {
+	leading := false
+
+	for range time.Tick(nameNode.ReReplicationInterval) {
+		if !nameNode.IsLeader() {
+			leading = false
+			continue
+		}
+
+		// jobs left running by the previous leader are retried
+		if !leading {
+			leading = true
+			nameNode.resetRunningJobs()
+		}
+
+		for _, job := range nameNode.GetReplicationJobs() {
+			if job.Status == ReplicationJobPending {
+				nameNode.runReplicationJob(job)
+			}
+		}
+
+		nameNode.expireReplicationJobs()
+	}
+}
+
+// expireReplicationJobs A function to remove the done and failed jobs that weren't updated within the retention,
+// along with the results of the copies repaired by them
+func (nameNode *NameNode) expireReplicationJobs() {
+	deadline := time.Now().Add(-nameNode.replicationJobsRetention)
+
+	var expiredIDs []string
+	expired := make(map[string]bool)
+	for _, job := range nameNode.GetReplicationJobs() {
+		over := job.Status == ReplicationJobDone || job.Status == ReplicationJobFailed
+		if over && job.UpdatedAt.Before(deadline) {
+			expiredIDs = append(expiredIDs, job.ID)
+			expired[job.ID] = true
+		}
+	}
+
+	if len(expiredIDs) == 0 {
+		return
+	}
+
+	log.Println(logPrefix, fmt.Sprintf("Removing %d expired re-replication jobs", len(expiredIDs)))
+
+	err := nameNode.deleteFromHash(nameNode.replicationJobsKey, expiredIDs...)
+	if errors.IsError(err) {
+		log.Println(logPrefix, fmt.Sprintf("Unable to delete from redis hash: %s", nameNode.replicationJobsKey), err)
+		return
+	}
+
+	for _, result := range nameNode.GetScrubResults() {
+		if result.Status == ScrubResultRepaired && expired[result.RepairJobID] {
+			nameNode.deleteFromHash(nameNode.scrubResultsKey, result.Token)
+		}
+	}
+}
+
+// resetRunningJobs A function to get the running jobs back to pending, so they're retried
+func (nameNode *NameNode) resetRunningJobs() {
+	for _, job := range nameNode.GetReplicationJobs() {
+		if job.Status == ReplicationJobRunning {
+			job.Status = ReplicationJobPending
+			nameNode.saveReplicationJob(job)
+		}
+	}
+}
+
+// runReplicationJob A function to copy a file to the target data node of a job
+func (nameNode *NameNode) runReplicationJob(job ReplicationJob) {
+	liveNodes := make(map[string]DataNodeData)
+	for _, dataNode := range nameNode.GetAllDataNodeData() {
+		liveNodes[dataNode.ID] = dataNode
+	}
+
+	source, sourceLive := liveNodes[job.SourceNodeID]
+	target, targetLive := liveNodes[job.TargetNodeID]
+	if !sourceLive || !targetLive {
+		// the file is checked again, so the job is replaced by one between online data nodes
+		nameNode.failReplicationJob(job, "Source or target data node went offline")
+		nameNode.scheduleFileReReplication(job.Token)
+		return
+	}
+
+	job.Status = ReplicationJobRunning
+	job.Attempts++
+	nameNode.saveReplicationJob(job)
+
+	newToken, err := nameNode.CopyFileToDataNode(source, job.Token, target)
+	if errors.IsError(err) {
+		log.Println(logPrefix, fmt.Sprintf("Attempt %d of job %s failed", job.Attempts, job.ID), err)
+
+		if job.Attempts >= nameNode.reReplicationRetries {
+			nameNode.failReplicationJob(job, err.Error())
+			return
+		}
+
+		job.Status = ReplicationJobPending
+		job.Error = err.Error()
+		nameNode.saveReplicationJob(job)
+		return
+	}
+
+	log.Println(logPrefix, fmt.Sprintf("Restored replica of file %s on data node %s as %s", job.Token, target.ID, newToken))
+
+	job.Status = ReplicationJobDone
+	job.Error = ""
+	job.NewToken = newToken
+	nameNode.saveReplicationJob(job)
+	nameNode.resolveRepair(job)
+}
+
+// failReplicationJob A function to mark a job as failed
+func (nameNode *NameNode) failReplicationJob(job ReplicationJob, reason string) {
+	log.Println(logPrefix, fmt.Sprintf("Job %s failed: %s", job.ID, reason))
+
+	job.Status = ReplicationJobFailed
+	job.Error = reason
+	nameNode.saveReplicationJob(job)
+	nameNode.resolveRepair(job)
+}
+
+// GetReplicationJobs A function to get all re-replication jobs, ordered by their scheduling time
+func (nameNode *NameNode) GetReplicationJobs() []ReplicationJob {
+	var jobs []ReplicationJob
+
+	encodedJobs, err := nameNode.getAllFromHash(nameNode.replicationJobsKey)
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to fetch re-replication jobs from redis")
+
+		return jobs
+	}
+
+	for _, encodedJob := range encodedJobs {
+		var job ReplicationJob
+
+		err := json.Unmarshal([]byte(encodedJob), &job)
+		if errors.IsError(err) {
+			log.Println(logPrefix, "Unable to decode re-replication job", encodedJob)
+
+			continue
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt.Before(jobs[j].CreatedAt)
+	})
+
+	return jobs
+}
+
+// saveReplicationJob A function to insert or update a re-replication job
+func (nameNode *NameNode) saveReplicationJob(job ReplicationJob) {
+	job.UpdatedAt = time.Now()
+
+	encodedJob, err := json.Marshal(job)
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to marshal re-replication job", job)
+
+		return
+	}
+
+	err = nameNode.insertIntoHash(nameNode.replicationJobsKey, job.ID, string(encodedJob))
+	if errors.IsError(err) {
+		log.Println(logPrefix, fmt.Sprintf("Unable to insert into redis hash: %s for job: %s", nameNode.replicationJobsKey, job.ID))
+	}
+}
@@ -0,0 +1,209 @@
+package namenode
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SayedAlesawy/Videra-Storage/config"
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+// RoutingRequest Describes an upload to be routed to a data node
+type RoutingRequest struct {
+	FileType     string          //Type of the file being uploaded (video, model)
+	Model        string          //Token of the model associated with the video, if any
+	ModelHolders map[string]bool //IDs of the data nodes holding the model associated with the video, if any
+}
+
+// RoutingPolicy Chooses the data node an upload is routed to out of the healthy candidates sorted by ID,
+// a policy only depends on its inputs, so the same request and candidates always get the same node
+type RoutingPolicy interface {
+	Route(request RoutingRequest, candidates []DataNodeData) (DataNodeData, bool)
+}
+
+// GetRoutingPolicy A function to get the routing policy with the given name, defaulting to least queued,
+// weighted round robin takes the weights of the nodes as comma separated id:weight pairs
+func GetRoutingPolicy(name string, weights string) RoutingPolicy {
+	switch name {
+	case "least-recent":
+		return LeastRecentRouting{}
+	case "most-free-disk":
+		return MostFreeDiskRouting{}
+	case "weighted-round-robin":
+		return WeightedRoundRobinRouting{Weights: parseRoutingWeights(weights)}
+	case "model-affinity":
+		return ModelAffinityRouting{Fallback: LeastQueuedRouting{}}
+	default:
+		return LeastQueuedRouting{}
+	}
+}
+
+// RouteUpload A function to choose the healthy GPU data node an upload is routed to, out of the nodes
+// not leaving the cluster, using the configured routing policy
+func (nameNode *NameNode) RouteUpload(request RoutingRequest) (DataNodeData, error) {
+	dataNodes := nameNode.GetAllDataNodeData()
+	// There's no available nodes
+	if len(dataNodes) == 0 {
+		return DataNodeData{}, errors.New("No datanodes available")
+	}
+
+	candidates := routingCandidates(request, dataNodes, nameNode.GetNodeStates())
+	if request.Model != "" && len(candidates) == 0 {
+		return DataNodeData{}, errors.New("Can't find a machine with GPU holding the model")
+	}
+
+	nameNodeConfig := config.ConfigurationManagerInstance("").NameNodeConfig()
+	chosenDataNode, ok := GetRoutingPolicy(nameNodeConfig.RoutingPolicy, nameNodeConfig.RoutingWeights).Route(request, candidates)
+	if !ok {
+		return DataNodeData{}, errors.New("Can't find a machine with GPU")
+	}
+
+	return chosenDataNode, nil
+}
+
+// routingCandidates A function to get the healthy GPU data nodes not leaving the cluster sorted by ID, a video
+// is only ingested by a node holding a completed copy of its model, so its candidates are limited to the holders
+func routingCandidates(request RoutingRequest, dataNodes []DataNodeData, states map[string]NodeState) []DataNodeData {
+	sort.Slice(dataNodes, func(i, j int) bool {
+		return dataNodes[i].ID < dataNodes[j].ID
+	})
+
+	var candidates []DataNodeData
+	for _, dataNode := range dataNodes {
+		if request.Model != "" && !request.ModelHolders[dataNode.ID] {
+			continue
+		}
+
+		if dataNode.GPU && dataNode.Latency == 0 && !states[dataNode.ID].isLeaving() {
+			candidates = append(candidates, dataNode)
+		}
+	}
+
+	return candidates
+}
+
+// LeastRecentRouting Routes to the node least recently routed to
+type LeastRecentRouting struct{}
+
+// Route A function to choose the node least recently routed to
+func (policy LeastRecentRouting) Route(request RoutingRequest, candidates []DataNodeData) (DataNodeData, bool) {
+	return pickBest(candidates, func(dataNode DataNodeData, best DataNodeData) bool {
+		return dataNode.LastRequestTime.Before(best.LastRequestTime)
+	})
+}
+
+// LeastQueuedRouting Routes to the node with the fewest jobs and uploads in progress,
+// ties are broken by choosing the node least recently routed to
+type LeastQueuedRouting struct{}
+
+// Route A function to choose the node with the minimum load
+func (policy LeastQueuedRouting) Route(request RoutingRequest, candidates []DataNodeData) (DataNodeData, bool) {
+	return pickBest(candidates, func(dataNode DataNodeData, best DataNodeData) bool {
+		if dataNode.Load() != best.Load() {
+			return dataNode.Load() < best.Load()
+		}
+
+		return dataNode.LastRequestTime.Before(best.LastRequestTime)
+	})
+}
+
+// MostFreeDiskRouting Routes to the node with the most free disk, ties are broken by the minimum load
+type MostFreeDiskRouting struct{}
+
+// Route A function to choose the node with the most free disk
+func (policy MostFreeDiskRouting) Route(request RoutingRequest, candidates []DataNodeData) (DataNodeData, bool) {
+	return pickBest(candidates, func(dataNode DataNodeData, best DataNodeData) bool {
+		if dataNode.FreeDisk != best.FreeDisk {
+			return dataNode.FreeDisk > best.FreeDisk
+		}
+
+		return dataNode.Load() < best.Load()
+	})
+}
+
+// WeightedRoundRobinRouting Spreads the uploads over the nodes proportionally to their weights,
+// by routing to the node with the fewest requests relative to its weight, nodes weigh 1 unless configured
+type WeightedRoundRobinRouting struct {
+	Weights map[string]int //Weight of each data node by ID
+}
+
+// Route A function to choose the node with the fewest requests relative to its weight
+func (policy WeightedRoundRobinRouting) Route(request RoutingRequest, candidates []DataNodeData) (DataNodeData, bool) {
+	return pickBest(candidates, func(dataNode DataNodeData, best DataNodeData) bool {
+		// compares (count + 1) / weight of both nodes, cross multiplied to stay in integers
+		share := uint64(dataNode.RequestCount+1) * uint64(policy.weightOf(best))
+		bestShare := uint64(best.RequestCount+1) * uint64(policy.weightOf(dataNode))
+
+		return share < bestShare
+	})
+}
+
+// weightOf is a function to get the weight of a data node
+func (policy WeightedRoundRobinRouting) weightOf(dataNode DataNodeData) int {
+	if weight, ok := policy.Weights[dataNode.ID]; ok && weight > 0 {
+		return weight
+	}
+
+	return 1
+}
+
+// ModelAffinityRouting Routes a video to one of the nodes holding its associated model, so it's ingested
+// without fetching the model, uploads with no holder among the candidates are routed by the fallback policy
+type ModelAffinityRouting struct {
+	Fallback RoutingPolicy //Policy choosing among the holders of the model, or among all the candidates
+}
+
+// Route A function to choose a node holding the model associated with the video
+func (policy ModelAffinityRouting) Route(request RoutingRequest, candidates []DataNodeData) (DataNodeData, bool) {
+	var holders []DataNodeData
+	for _, candidate := range candidates {
+		if request.ModelHolders[candidate.ID] {
+			holders = append(holders, candidate)
+		}
+	}
+
+	if len(holders) != 0 {
+		return policy.Fallback.Route(request, holders)
+	}
+
+	return policy.Fallback.Route(request, candidates)
+}
+
+// pickBest is a function to pick the candidate that's better than all the others, keeping the first one on ties
+func pickBest(candidates []DataNodeData, better func(dataNode DataNodeData, best DataNodeData) bool) (DataNodeData, bool) {
+	if len(candidates) == 0 {
+		return DataNodeData{}, false
+	}
+
+	best := candidates[0]
+	for _, candidate := range candidates[1:] {
+		if better(candidate, best) {
+			best = candidate
+		}
+	}
+
+	return best, true
+}
+
+// parseRoutingWeights is a function to parse the weights of the nodes given as comma separated id:weight pairs,
+// malformed pairs are skipped
+func parseRoutingWeights(encodedWeights string) map[string]int {
+	weights := make(map[string]int)
+
+	for _, pair := range strings.Split(encodedWeights, ",") {
+		parts := strings.Split(strings.TrimSpace(pair), ":")
+		if len(parts) != 2 {
+			continue
+		}
+
+		weight, err := strconv.Atoi(parts[1])
+		if errors.IsError(err) {
+			continue
+		}
+
+		weights[parts[0]] = weight
+	}
+
+	return weights
+}
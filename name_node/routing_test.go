@@ -0,0 +1,296 @@
+package namenode
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// routingNow Reference time of the last requests routed to the test nodes
+var routingNow = time.Date(2020, 6, 1, 10, 0, 0, 0, time.UTC)
+
+// routedAt is a function to get the time the given number of seconds after the reference time
+func routedAt(seconds int) time.Time {
+	return routingNow.Add(time.Duration(seconds) * time.Second)
+}
+
+func TestRoutingPolicies(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     RoutingPolicy
+		request    RoutingRequest
+		candidates []DataNodeData
+		expectedID string
+	}{
+		{
+			name:   "least recent picks the node routed to first",
+			policy: LeastRecentRouting{},
+			candidates: []DataNodeData{
+				{ID: "1", LastRequestTime: routedAt(20)},
+				{ID: "2", LastRequestTime: routedAt(10)},
+				{ID: "3", LastRequestTime: routedAt(30)},
+			},
+			expectedID: "2",
+		},
+		{
+			name:   "least recent keeps the first node on ties",
+			policy: LeastRecentRouting{},
+			candidates: []DataNodeData{
+				{ID: "1", LastRequestTime: routedAt(10), RunningJobs: 5},
+				{ID: "2", LastRequestTime: routedAt(10)},
+			},
+			expectedID: "1",
+		},
+		{
+			name:   "least queued picks the node with the minimum load",
+			policy: LeastQueuedRouting{},
+			candidates: []DataNodeData{
+				{ID: "1", RunningJobs: 1, QueuedJobs: 2},
+				{ID: "2", InFlightUploads: 2},
+				{ID: "3", RunningJobs: 1, InFlightUploads: 3},
+			},
+			expectedID: "2",
+		},
+		{
+			name:   "least queued breaks load ties by the least recent node",
+			policy: LeastQueuedRouting{},
+			candidates: []DataNodeData{
+				{ID: "1", QueuedJobs: 1, LastRequestTime: routedAt(30)},
+				{ID: "2", RunningJobs: 1, LastRequestTime: routedAt(10)},
+				{ID: "3", RunningJobs: 2, LastRequestTime: routedAt(0)},
+			},
+			expectedID: "2",
+		},
+		{
+			name:   "most free disk picks the node with the most free bytes",
+			policy: MostFreeDiskRouting{},
+			candidates: []DataNodeData{
+				{ID: "1", FreeDisk: 100},
+				{ID: "2", FreeDisk: 300, RunningJobs: 4},
+				{ID: "3", FreeDisk: 200},
+			},
+			expectedID: "2",
+		},
+		{
+			name:   "most free disk breaks ties by the minimum load",
+			policy: MostFreeDiskRouting{},
+			candidates: []DataNodeData{
+				{ID: "1", FreeDisk: 300, QueuedJobs: 2},
+				{ID: "2", FreeDisk: 300, QueuedJobs: 1},
+				{ID: "3", FreeDisk: 100},
+			},
+			expectedID: "2",
+		},
+		{
+			name:   "weighted round robin picks the node with the fewest requests relative to its weight",
+			policy: WeightedRoundRobinRouting{Weights: map[string]int{"1": 1, "2": 3}},
+			candidates: []DataNodeData{
+				{ID: "1", RequestCount: 1},
+				{ID: "2", RequestCount: 4},
+			},
+			expectedID: "2",
+		},
+		{
+			name:   "weighted round robin weighs unknown nodes 1",
+			policy: WeightedRoundRobinRouting{Weights: map[string]int{"1": 2}},
+			candidates: []DataNodeData{
+				{ID: "1", RequestCount: 4},
+				{ID: "2", RequestCount: 1},
+			},
+			expectedID: "2",
+		},
+		{
+			name:    "model affinity picks among the holders of the model",
+			policy:  ModelAffinityRouting{Fallback: LeastQueuedRouting{}},
+			request: RoutingRequest{FileType: "video", ModelHolders: map[string]bool{"2": true, "3": true}},
+			candidates: []DataNodeData{
+				{ID: "1"},
+				{ID: "2", QueuedJobs: 3},
+				{ID: "3", QueuedJobs: 2},
+			},
+			expectedID: "3",
+		},
+		{
+			name:    "model affinity falls back to all the candidates when no holder of the model is a free GPU node",
+			policy:  ModelAffinityRouting{Fallback: LeastQueuedRouting{}},
+			request: RoutingRequest{FileType: "video", ModelHolders: map[string]bool{"4": true}},
+			candidates: []DataNodeData{
+				{ID: "1", QueuedJobs: 1},
+				{ID: "2"},
+			},
+			expectedID: "2",
+		},
+		{
+			name:    "model affinity falls back to all the candidates for uploads with no model",
+			policy:  ModelAffinityRouting{Fallback: LeastRecentRouting{}},
+			request: RoutingRequest{FileType: "model"},
+			candidates: []DataNodeData{
+				{ID: "1", LastRequestTime: routedAt(10)},
+				{ID: "2", LastRequestTime: routedAt(0)},
+			},
+			expectedID: "2",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			chosen, ok := test.policy.Route(test.request, test.candidates)
+			if !ok {
+				t.Fatalf("expected node %s, no node was chosen", test.expectedID)
+			}
+
+			if chosen.ID != test.expectedID {
+				t.Errorf("expected node %s, found %s", test.expectedID, chosen.ID)
+			}
+		})
+	}
+}
+
+func TestRoutingPoliciesWithNoCandidates(t *testing.T) {
+	policies := map[string]RoutingPolicy{
+		"least-recent":         LeastRecentRouting{},
+		"least-queued":         LeastQueuedRouting{},
+		"most-free-disk":       MostFreeDiskRouting{},
+		"weighted-round-robin": WeightedRoundRobinRouting{Weights: map[string]int{"1": 2}},
+		"model-affinity":       ModelAffinityRouting{Fallback: LeastQueuedRouting{}},
+	}
+
+	request := RoutingRequest{FileType: "video", ModelHolders: map[string]bool{"1": true}}
+	for name, policy := range policies {
+		t.Run(name, func(t *testing.T) {
+			if chosen, ok := policy.Route(request, nil); ok {
+				t.Errorf("expected no node, found %s", chosen.ID)
+			}
+		})
+	}
+}
+
+func TestRoutingCandidates(t *testing.T) {
+	dataNodes := []DataNodeData{
+		{ID: "6", GPU: true},
+		{ID: "1", GPU: true},
+		{ID: "2"},
+		{ID: "3", GPU: true, Latency: 1},
+		{ID: "4", GPU: true},
+		{ID: "5", GPU: true},
+	}
+	states := map[string]NodeState{
+		"4": {ID: "4", State: NodeStateDraining},
+		"5": {ID: "5", State: NodeStateActive},
+	}
+
+	tests := []struct {
+		name        string
+		request     RoutingRequest
+		expectedIDs []string
+	}{
+		{
+			name:        "offline, leaving and non GPU nodes are filtered out",
+			request:     RoutingRequest{FileType: "model"},
+			expectedIDs: []string{"1", "5", "6"},
+		},
+		{
+			name:        "a video is only routed to the holders of its model",
+			request:     RoutingRequest{FileType: "video", Model: "m", ModelHolders: map[string]bool{"2": true, "4": true, "6": true}},
+			expectedIDs: []string{"6"},
+		},
+		{
+			name:        "a video whose model has no holder has no candidate",
+			request:     RoutingRequest{FileType: "video", Model: "m", ModelHolders: map[string]bool{}},
+			expectedIDs: []string{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			candidates := append([]DataNodeData{}, dataNodes...)
+			if ids := idsOf(routingCandidates(test.request, candidates, states)); !reflect.DeepEqual(ids, test.expectedIDs) {
+				t.Errorf("expected candidates %v, found %v", test.expectedIDs, ids)
+			}
+		})
+	}
+}
+
+func TestWeightedRoundRobinShares(t *testing.T) {
+	tests := []struct {
+		name           string
+		weights        string
+		ids            []string
+		requests       int
+		expectedCounts map[string]uint
+	}{
+		{
+			name:           "requests are spread proportionally to the weights",
+			weights:        "1:1,2:2,3:3",
+			ids:            []string{"1", "2", "3"},
+			requests:       60,
+			expectedCounts: map[string]uint{"1": 10, "2": 20, "3": 30},
+		},
+		{
+			name:           "unknown, malformed and non positive weights count as 1",
+			weights:        "1:3, 2:x,3:0,bad",
+			ids:            []string{"1", "2", "3", "4"},
+			requests:       60,
+			expectedCounts: map[string]uint{"1": 30, "2": 10, "3": 10, "4": 10},
+		},
+		{
+			name:           "no weights spread the requests evenly",
+			weights:        "",
+			ids:            []string{"1", "2"},
+			requests:       10,
+			expectedCounts: map[string]uint{"1": 5, "2": 5},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy := GetRoutingPolicy("weighted-round-robin", test.weights)
+
+			candidates := make([]DataNodeData, len(test.ids))
+			for i, id := range test.ids {
+				candidates[i] = DataNodeData{ID: id}
+			}
+
+			for i := 0; i < test.requests; i++ {
+				chosen, ok := policy.Route(RoutingRequest{}, candidates)
+				if !ok {
+					t.Fatalf("no node was chosen for request %d", i)
+				}
+
+				for j := range candidates {
+					if candidates[j].ID == chosen.ID {
+						candidates[j].RequestCount++
+					}
+				}
+			}
+
+			for _, candidate := range candidates {
+				if candidate.RequestCount != test.expectedCounts[candidate.ID] {
+					t.Errorf("expected node %s to get %d requests, found %d",
+						candidate.ID, test.expectedCounts[candidate.ID], candidate.RequestCount)
+				}
+			}
+		})
+	}
+}
+
+func TestGetRoutingPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected RoutingPolicy
+	}{
+		{name: "least-recent", expected: LeastRecentRouting{}},
+		{name: "least-queued", expected: LeastQueuedRouting{}},
+		{name: "most-free-disk", expected: MostFreeDiskRouting{}},
+		{name: "model-affinity", expected: ModelAffinityRouting{Fallback: LeastQueuedRouting{}}},
+		{name: "unknown", expected: LeastQueuedRouting{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if policy := GetRoutingPolicy(test.name, ""); policy != test.expected {
+				t.Errorf("expected policy %#v, found %#v", test.expected, policy)
+			}
+		})
+	}
+}
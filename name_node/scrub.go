@@ -0,0 +1,376 @@
+package namenode
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/SayedAlesawy/Videra-Storage/utils/errors"
+)
+
+const (
+	//ScrubResultDiverged represents a diverged copy waiting for a verified copy to be repaired from
+	ScrubResultDiverged string = "diverged"
+	//ScrubResultRepairing represents a diverged copy replaced by a copy of a verified holder
+	ScrubResultRepairing string = "repairing"
+	//ScrubResultRepaired represents a diverged copy whose replacement was restored
+	ScrubResultRepaired string = "repaired"
+)
+
+// Names of the scrubbing counters
+const (
+	scrubbedMetric         = "scrubbed"
+	verifiedMetric         = "verified"
+	divergedMetric         = "diverged"
+	repairsScheduledMetric = "repairs_scheduled"
+)
+
+// ReportedChecksum Represents the checksum of a file copy as computed from disk by the scrubber of its data node
+type ReportedChecksum struct {
+	Token    string //Token of the file copy
+	Parent   string //Token of the original file
+	Checksum string //SHA-256 digest of the copy (hex)
+}
+
+// fileExpectation Represents what the copies of a file are expected to hold, recorded from its original,
+// so it outlives the original being deleted to be repaired
+type fileExpectation struct {
+	Checksum string `json:"checksum"` //Checksum of the original, computed when it was uploaded
+	Copies   int    `json:"copies"`   //Intended number of copies of the file
+}
+
+// ScrubResult Represents a file copy found by a scrubber to diverge from its original
+type ScrubResult struct {
+	Token            string    `json:"token"`                   //Token of the diverged copy
+	Parent           string    `json:"parent"`                  //Token of the original file
+	DataNodeID       string    `json:"data_node_id"`            //ID of the data node holding the diverged copy
+	ExpectedChecksum string    `json:"expected_checksum"`       //Checksum of the original, computed when it was uploaded
+	FoundChecksum    string    `json:"found_checksum"`          //Checksum of the copy, as computed by the scrubber
+	Status           string    `json:"status"`                  //Status of the copy (diverged, repairing, repaired)
+	RepairJobID      string    `json:"repair_job_id,omitempty"` //ID of the re-replication job restoring the copy
+	DetectedAt       time.Time `json:"detected_at"`             //Time at which the divergence was first reported
+	UpdatedAt        time.Time `json:"updated_at"`              //Time at which the result was last updated
+}
+
+// CheckChecksums A function to compare the checksums reported by the scrubber of a data node against
+// the checksums of the originals, diverged copies are replaced by copies of verified holders,
+// it returns the number of diverged copies
+func (nameNode *NameNode) CheckChecksums(dataNodeID string, checksums []ReportedChecksum) int {
+	nameNode.incrementScrubMetric(scrubbedMetric, len(checksums))
+
+	diverged := 0
+	for _, reported := range checksums {
+		err := nameNode.insertIntoHash(nameNode.scrubChecksumsKey, reported.Token, reported.Checksum)
+		if errors.IsError(err) {
+			log.Println(logPrefix, fmt.Sprintf("Unable to insert into redis hash: %s for file: %s", nameNode.scrubChecksumsKey, reported.Token))
+		}
+
+		copies, err := nameNode.GetFileCopies(reported.Parent)
+		if errors.IsError(err) {
+			log.Println(logPrefix, fmt.Sprintf("Unable to fetch copies of file %s", reported.Parent), err)
+			continue
+		}
+
+		// the checksum of the original was verified against the client's and the replicas' ones on upload,
+		// it's recorded while the original is there, as a diverged original is replaced by a copy with a new token
+		expectation, found := nameNode.recordFileExpectation(reported.Parent, copies)
+		if !found || expectation.Checksum == "" {
+			log.Println(logPrefix, fmt.Sprintf("File %s has no recorded checksum to verify copy %s against", reported.Parent, reported.Token))
+			continue
+		}
+		expectedChecksum := expectation.Checksum
+
+		if reported.Checksum == expectedChecksum {
+			nameNode.incrementScrubMetric(verifiedMetric, 1)
+			nameNode.deleteFromHash(nameNode.scrubResultsKey, reported.Token)
+			continue
+		}
+
+		log.Println(logPrefix, fmt.Sprintf("Copy %s of file %s on data node %s diverged, expected checksum %s found %s",
+			reported.Token, reported.Parent, dataNodeID, expectedChecksum, reported.Checksum))
+
+		diverged++
+		nameNode.incrementScrubMetric(divergedMetric, 1)
+
+		result, found := nameNode.getScrubResult(reported.Token)
+		if found && result.Status == ScrubResultRepairing {
+			continue
+		}
+
+		now := time.Now()
+		if !found {
+			result = ScrubResult{
+				Token:      reported.Token,
+				Parent:     reported.Parent,
+				DataNodeID: dataNodeID,
+				Status:     ScrubResultDiverged,
+				DetectedAt: now,
+			}
+		}
+		result.ExpectedChecksum = expectedChecksum
+		result.FoundChecksum = reported.Checksum
+
+		nameNode.repairCopy(&result, copies)
+		nameNode.saveScrubResult(result)
+	}
+
+	return diverged
+}
+
+// repairCopy A function to replace a diverged copy by a copy of a holder verified by its scrubber,
+// the diverged copy is deleted, then a re-replication job copies the verified one to the same data node
+func (nameNode *NameNode) repairCopy(result *ScrubResult, copies []FileInfo) {
+	liveNodes := make(map[string]DataNodeData)
+	for _, dataNode := range nameNode.GetAllDataNodeData() {
+		liveNodes[dataNode.ID] = dataNode
+	}
+
+	target, live := liveNodes[result.DataNodeID]
+	if !live {
+		return
+	}
+
+	var source DataNodeData
+	verified := false
+	for _, fileCopy := range copies {
+		dataNode, live := liveNodes[fileCopy.DataNodeID]
+		if !live || fileCopy.CompletedAt == nil || fileCopy.DataNodeID == result.DataNodeID {
+			continue
+		}
+
+		checksum, err := nameNode.getFromHash(nameNode.scrubChecksumsKey, fileCopy.Token)
+		if !errors.IsError(err) && checksum == result.ExpectedChecksum {
+			source = dataNode
+			verified = true
+			break
+		}
+	}
+
+	if !verified {
+		log.Println(logPrefix, fmt.Sprintf("File %s has no verified copy to repair copy %s from", result.Parent, result.Token))
+		return
+	}
+
+	err := nameNode.DeleteFileFromDataNode(target, result.Parent)
+	if errors.IsError(err) {
+		log.Println(logPrefix, fmt.Sprintf("Unable to delete diverged copy %s", result.Token), err)
+		return
+	}
+	nameNode.deleteFromHash(nameNode.scrubChecksumsKey, result.Token)
+
+	log.Println(logPrefix, fmt.Sprintf("Scheduling repair of copy %s from data node %s to %s", result.Token, source.ID, target.ID))
+
+	job := newReplicationJob(result.Parent, source.ID, target.ID)
+	nameNode.saveReplicationJob(job)
+	nameNode.incrementScrubMetric(repairsScheduledMetric, 1)
+
+	result.Status = ScrubResultRepairing
+	result.RepairJobID = job.ID
+}
+
+// resolveRepair A function to update the scrub result of the copy repaired by a re-replication job once the job
+// is over, the replacement is restored under a new token, so the result isn't updated by the scrubber anymore,
+// a copy whose repair failed is diverged again
+func (nameNode *NameNode) resolveRepair(job ReplicationJob) {
+	for _, result := range nameNode.GetScrubResults() {
+		if result.RepairJobID != job.ID || result.Status != ScrubResultRepairing {
+			continue
+		}
+
+		result.Status = ScrubResultDiverged
+		if job.Status == ReplicationJobDone {
+			result.Status = ScrubResultRepaired
+		}
+		nameNode.saveScrubResult(result)
+	}
+}
+
+// unresolved Indicates whether a diverged copy wasn't repaired yet
+func (result ScrubResult) unresolved() bool {
+	return result.Status != ScrubResultRepaired
+}
+
+// GetScrubResults A function to get the diverged copies found by the scrubbers, ordered by their detection time
+func (nameNode *NameNode) GetScrubResults() []ScrubResult {
+	var results []ScrubResult
+
+	encodedResults, err := nameNode.getAllFromHash(nameNode.scrubResultsKey)
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to fetch scrub results from redis")
+
+		return results
+	}
+
+	for _, encodedResult := range encodedResults {
+		var result ScrubResult
+
+		err := json.Unmarshal([]byte(encodedResult), &result)
+		if errors.IsError(err) {
+			log.Println(logPrefix, "Unable to decode scrub result", encodedResult)
+
+			continue
+		}
+
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].DetectedAt.Before(results[j].DetectedAt)
+	})
+
+	return results
+}
+
+// GetScrubMetrics A function to get the scrubbing counters
+func (nameNode *NameNode) GetScrubMetrics() map[string]int64 {
+	metrics := map[string]int64{
+		scrubbedMetric:         0,
+		verifiedMetric:         0,
+		divergedMetric:         0,
+		repairsScheduledMetric: 0,
+	}
+
+	encodedMetrics, err := nameNode.getAllFromHash(nameNode.scrubMetricsKey)
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to fetch scrub metrics from redis")
+
+		return metrics
+	}
+
+	for name, encodedValue := range encodedMetrics {
+		value, err := strconv.ParseInt(encodedValue, 10, 64)
+		if !errors.IsError(err) {
+			metrics[name] = value
+		}
+	}
+
+	return metrics
+}
+
+// getFileExpectation A function to get the checksum and intended number of copies of a file,
+// taken from its original if it's there, otherwise from the ones recorded from it
+func (nameNode *NameNode) getFileExpectation(token string, copies []FileInfo) (fileExpectation, bool) {
+	if expectation, found := expectationOf(copies); found {
+		return expectation, true
+	}
+
+	var expectation fileExpectation
+
+	encodedExpectation, err := nameNode.getFromHash(nameNode.fileExpectationsKey, token)
+	if errors.IsError(err) {
+		return expectation, false
+	}
+
+	err = json.Unmarshal([]byte(encodedExpectation), &expectation)
+
+	return expectation, !errors.IsError(err)
+}
+
+// getFileExpectations A function to get the checksum and intended number of copies recorded for each file, by token
+func (nameNode *NameNode) getFileExpectations() map[string]fileExpectation {
+	expectations := make(map[string]fileExpectation)
+
+	encodedExpectations, err := nameNode.getAllFromHash(nameNode.fileExpectationsKey)
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to fetch file expectations from redis")
+
+		return expectations
+	}
+
+	for token, encodedExpectation := range encodedExpectations {
+		var expectation fileExpectation
+
+		err := json.Unmarshal([]byte(encodedExpectation), &expectation)
+		if errors.IsError(err) {
+			log.Println(logPrefix, "Unable to decode file expectation", encodedExpectation)
+
+			continue
+		}
+
+		expectations[token] = expectation
+	}
+
+	return expectations
+}
+
+// recordFileExpectation A function to get the checksum and intended number of copies of a file,
+// recording them while its original is there
+func (nameNode *NameNode) recordFileExpectation(token string, copies []FileInfo) (fileExpectation, bool) {
+	expectation, found := expectationOf(copies)
+	if !found {
+		return nameNode.getFileExpectation(token, copies)
+	}
+
+	if expectation.Checksum == "" {
+		return expectation, true
+	}
+
+	encodedExpectation, err := json.Marshal(expectation)
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to marshal file expectation", expectation)
+
+		return expectation, true
+	}
+
+	err = nameNode.insertIntoHash(nameNode.fileExpectationsKey, token, string(encodedExpectation))
+	if errors.IsError(err) {
+		log.Println(logPrefix, fmt.Sprintf("Unable to insert into redis hash: %s for file: %s", nameNode.fileExpectationsKey, token))
+	}
+
+	return expectation, true
+}
+
+// expectationOf A function to get the checksum and intended number of copies of a file from its original,
+// the original is forwarded to all the replicas of the file, so it tells the intended number of copies,
+// the checksum is empty until the original completes
+func expectationOf(copies []FileInfo) (fileExpectation, bool) {
+	for _, fileCopy := range copies {
+		if fileCopy.Token == fileCopy.Parent {
+			return fileExpectation{Checksum: fileCopy.Checksum, Copies: fileCopy.ReplicaCount + 1}, true
+		}
+	}
+
+	return fileExpectation{}, false
+}
+
+// getScrubResult A function to get the scrub result of a file copy
+func (nameNode *NameNode) getScrubResult(token string) (ScrubResult, bool) {
+	var result ScrubResult
+
+	encodedResult, err := nameNode.getFromHash(nameNode.scrubResultsKey, token)
+	if errors.IsError(err) {
+		return result, false
+	}
+
+	err = json.Unmarshal([]byte(encodedResult), &result)
+
+	return result, !errors.IsError(err)
+}
+
+// saveScrubResult A function to insert or update a scrub result
+func (nameNode *NameNode) saveScrubResult(result ScrubResult) {
+	result.UpdatedAt = time.Now()
+
+	encodedResult, err := json.Marshal(result)
+	if errors.IsError(err) {
+		log.Println(logPrefix, "Unable to marshal scrub result", result)
+
+		return
+	}
+
+	err = nameNode.insertIntoHash(nameNode.scrubResultsKey, result.Token, string(encodedResult))
+	if errors.IsError(err) {
+		log.Println(logPrefix, fmt.Sprintf("Unable to insert into redis hash: %s for file: %s", nameNode.scrubResultsKey, result.Token))
+	}
+}
+
+// incrementScrubMetric A function to increment a scrubbing counter
+func (nameNode *NameNode) incrementScrubMetric(name string, increment int) {
+	err := nameNode.incrementHashField(nameNode.scrubMetricsKey, name, int64(increment))
+	if errors.IsError(err) {
+		log.Println(logPrefix, fmt.Sprintf("Unable to increment %s in redis hash: %s", name, nameNode.scrubMetricsKey))
+	}
+}
@@ -9,12 +9,33 @@ import (
 
 // NameNode Represents a tracking node in the storage system
 type NameNode struct {
+	ID                       string             //Unique ID of the name node instance
 	IP                       string             //IP of the name node host
 	InternalPort             string             //Port on which all internal comm is done
+	Port                     string             //Port on which all external comm is done
 	dataNodesTrackingKey     string             //The key of the redis hash used to track data nodes
 	dataNodeOfflineThreshold int                //Threshold of missed pings at which a data node is considered offline
 	InteralReqTimeout        time.Duration      //Timeout for internal requests
 	HealthCheckInterval      time.Duration      //The frequency of the health check request to data nodes
+	replicationJobsKey       string             //The key of the redis hash used to track re-replication jobs
+	ReReplicationInterval    time.Duration      //The frequency of running pending re-replication jobs
+	reReplicationRetries     int                //Number of attempts of a re-replication job before it's considered failed
+	reReplicationTimeout     time.Duration      //Timeout for copying a file to a new data node
+	replicationJobsRetention time.Duration      //Time for which done and failed re-replication jobs are kept
+	scrubChecksumsKey        string             //The key of the redis hash used to track the checksums reported by the scrubbers
+	scrubResultsKey          string             //The key of the redis hash used to track the diverged copies
+	scrubMetricsKey          string             //The key of the redis hash used to track the scrubbing counters
+	fileExpectationsKey      string             //The key of the redis hash used to track the checksum and intended number of copies of each file
+	nodeStatesKey            string             //The key of the redis hash used to track the draining and decommissioned data nodes
+	DecommissionInterval     time.Duration      //The frequency of migrating the files off the draining data nodes
+	leaderLeaseKey           string             //The key of the lease held by the leader name node
+	inventoryKeyPrefix       string             //The prefix of the keys of the redis hashes used to track the file copies held by each data node
+	locationsKeyPrefix       string             //The prefix of the keys of the redis hashes used to track the data nodes holding each file
+	discrepanciesKey         string             //The key of the redis hash used to track the discrepancies found in the block reports
+	pendingDeletionsKey      string             //The key of the redis hash used to track the deletions waiting for offline data nodes
+	leaderLeaseTTL           time.Duration      //Time after which the lease of a leader that stopped renewing it expires
+	LeaderRenewInterval      time.Duration      //The frequency of renewing or campaigning for the leader lease
+	leader                   int32              //Set to 1 while the name node holds the leader lease
 	DataNodes                []DataNodeData     //Array of all tracked data nodes
 	cache                    *redis.Client      //Used by the name node to access a persistent caching layer
 	DB                       *database.Database //Database connection
@@ -27,7 +48,32 @@ type DataNodeData struct {
 	InternalPort    string    `json:"internal_port"`     //Port on which the name node communicates with the data node
 	Port            string    `json:"port"`              //Port on which the data node communicates with clients
 	GPU             bool      `json:"gpu"`               //GPU status of the data node
+	Zone            string    `json:"zone"`              //Zone (failure domain) the data node is deployed in
+	Rack            string    `json:"rack"`              //Rack the data node is mounted on, within its zone
+	DiskClass       string    `json:"disk_class"`        //Class of the disk holding the files (hdd, ssd)
+	FreeDisk        uint64    `json:"free_disk"`         //Free bytes on the disk holding the files, as last reported
+	TotalDisk       uint64    `json:"total_disk"`        //Total bytes of the disk holding the files, 0 if unknown
+	UsedDisk        uint64    `json:"used_disk"`         //Used bytes on the disk holding the files, as last reported
+	FileCount       int64     `json:"file_count"`        //Number of files held by the data node
+	RunningJobs     int       `json:"running_jobs"`      //Number of ingestion jobs being executed by the data node
+	QueuedJobs      int       `json:"queued_jobs"`       //Number of ingestion jobs waiting to be executed by the data node
+	InFlightUploads int       `json:"in_flight_uploads"` //Number of uploads in progress on the data node
+	GPUAvailable    bool      `json:"gpu_available"`     //Indicates if the GPU of the data node can take more jobs
+	LastHeartbeat   time.Time `json:"last_heartbeat"`    //Time of the last successful health check of the data node
 	Latency         int       `json:"latency"`           //Count of missed pings by the data node
 	RequestCount    uint      `json:"request_count"`     //Number of clients requests routed to the data node
 	LastRequestTime time.Time `json:"last_request_time"` //Stores the timestamp of the last request served by a node
 }
+
+// NodeLoad Represents the capacity and load reported by a data node
+type NodeLoad struct {
+	FreeDisk        uint64 //Free bytes on the disk holding the files
+	UsedDisk        uint64 //Used bytes on the disk holding the files
+	TotalDisk       uint64 //Total bytes of the disk holding the files
+	FileCount       int64  //Number of files held by the data node
+	RunningJobs     int    //Number of ingestion jobs being executed
+	QueuedJobs      int    //Number of ingestion jobs waiting to be executed
+	InFlightUploads int    //Number of uploads in progress
+	GPU             bool   //Indicates if the data node has a GPU
+	GPUAvailable    bool   //Indicates if the GPU can take more jobs
+}
@@ -10,7 +10,7 @@ import (
 
 // getDataNodeAddress A function to get a data node address
 func (nameNode *NameNode) getDataNodeInternalAddress(dataNode DataNodeData) string {
-	return fmt.Sprintf("%s:%s", dataNode.IP, dataNode.InternalPort)
+	return GetInternalAddress(dataNode.IP, dataNode.InternalPort)
 }
 
 // NewDataNodeData A function to obtain a new data node data object
@@ -27,6 +27,27 @@ func NewDataNodeData(id string, ip string, internalPort string, port string, gpu
 	}
 }
 
+// updateLoad A function to record the capacity and load reported by a healthy data node,
+// which clears the pings it missed
+func (dataNodeData *DataNodeData) updateLoad(load NodeLoad) {
+	dataNodeData.Latency = 0
+	dataNodeData.FreeDisk = load.FreeDisk
+	dataNodeData.UsedDisk = load.UsedDisk
+	dataNodeData.TotalDisk = load.TotalDisk
+	dataNodeData.FileCount = load.FileCount
+	dataNodeData.RunningJobs = load.RunningJobs
+	dataNodeData.QueuedJobs = load.QueuedJobs
+	dataNodeData.InFlightUploads = load.InFlightUploads
+	dataNodeData.GPU = load.GPU
+	dataNodeData.GPUAvailable = load.GPUAvailable
+	dataNodeData.LastHeartbeat = time.Now()
+}
+
+// Load A function to get the load of a data node, as the number of its jobs and uploads in progress
+func (dataNodeData *DataNodeData) Load() int {
+	return dataNodeData.RunningJobs + dataNodeData.QueuedJobs + dataNodeData.InFlightUploads
+}
+
 // encode A function to encode the data node data into json format
 func (dataNodeData *DataNodeData) encode() (string, error) {
 	encodedData, err := json.Marshal(dataNodeData)
@@ -49,6 +70,11 @@ func (nameNode *NameNode) decodeDataNodeData(encodedData string) (DataNodeData,
 	return dataNodeData, nil
 }
 
+// GetInternalAddress returns the address of the internal routes of a data node from ip and internal port
+func GetInternalAddress(ip string, internalPort string) string {
+	return fmt.Sprintf("%s:%s", ip, internalPort)
+}
+
 // GetURL returns URL from ip and port
 func GetURL(ip string, port string) string {
 	return fmt.Sprintf("http://%s:%s", ip, port)